@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/audit"
+)
+
+// runAudit audits the commitments of the channel and reports the mismatches.
+func runAudit(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	var (
+		chainFlags chainFlags
+		portID     string
+		channelID  string
+	)
+	chainFlags.register(fs, "")
+	fs.StringVar(&portID, "port", defaultPortID, "the port ID of the channel")
+	fs.StringVar(&channelID, "channel", "", "the channel ID of the channel (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if channelID == "" {
+		return errors.New("-channel is required")
+	}
+
+	chain, err := chainFlags.newChain()
+	if err != nil {
+		return err
+	}
+	report, err := audit.AuditCommitments(ctx, chain, portID, channelID)
+	if err != nil {
+		return err
+	}
+	fmt.Println(report)
+	if !report.OK() {
+		return errCheckFailed
+	}
+	return nil
+}
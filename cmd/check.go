@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/audit"
+)
+
+// runCheck checks the consistency of a connection or a channel with its counterparty, or of all of them
+// between the chains, and reports the inconsistencies.
+func runCheck(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	var (
+		chainFlags, counterpartyFlags chainFlags
+		connectionID, portID          string
+		channelID, ports              string
+		all                           bool
+	)
+	chainFlags.register(fs, "")
+	counterpartyFlags.register(fs, "counterparty-")
+	fs.StringVar(&connectionID, "connection", "", "the connection ID to check")
+	fs.StringVar(&portID, "port", "", "the port ID of the channel to check")
+	fs.StringVar(&channelID, "channel", "", "the channel ID to check, which checks its connection as well")
+	fs.BoolVar(&all, "all", false, "check all the connections and channels between the chains")
+	fs.StringVar(&ports, "ports", defaultPortID, "the comma-separated port IDs to look the channels up under with -all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var n int
+	for _, set := range []bool{connectionID != "", channelID != "", all} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return errors.New("one of -connection, -channel or -all is required")
+	}
+
+	chain, err := chainFlags.newChain()
+	if err != nil {
+		return err
+	}
+	counterparty, err := counterpartyFlags.newChain()
+	if err != nil {
+		return err
+	}
+	var report *audit.ConsistencyReport
+	switch {
+	case all:
+		var portIDs []string
+		for _, p := range strings.Split(ports, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				portIDs = append(portIDs, p)
+			}
+		}
+		report, err = audit.CheckAll(ctx, chain, counterparty, portIDs...)
+	case connectionID != "":
+		report, err = audit.CheckConnection(ctx, chain, counterparty, connectionID)
+	default:
+		if portID == "" {
+			return errors.New("-port is required with -channel")
+		}
+		report, err = audit.CheckChannel(ctx, chain, counterparty, portID, channelID)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(report)
+	if !report.OK() {
+		return errCheckFailed
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// runClear relays the packets sent from a channel that have not been received on the counterparty chain,
+// and the acknowledgements written for them that have not been acknowledged on the chain.
+func runClear(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("clear", flag.ContinueOnError)
+	var (
+		chainFlags, counterpartyFlags chainFlags
+		portID                        string
+		channelID                     string
+	)
+	chainFlags.register(fs, "")
+	counterpartyFlags.register(fs, "counterparty-")
+	fs.StringVar(&portID, "port", defaultPortID, "the port ID of the channel")
+	fs.StringVar(&channelID, "channel", "", "the channel ID of the channel (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if channelID == "" {
+		return errors.New("-channel is required")
+	}
+
+	chain, err := chainFlags.newChain()
+	if err != nil {
+		return err
+	}
+	counterparty, err := counterpartyFlags.newChain()
+	if err != nil {
+		return err
+	}
+	txOpts, err := chainFlags.txOpts()
+	if err != nil {
+		return err
+	}
+	counterpartyTxOpts, err := counterpartyFlags.txOpts()
+	if err != nil {
+		return err
+	}
+	cleared, err := chain.ClearPackets(ctx, txOpts(ctx), counterparty, counterpartyTxOpts(ctx), portID, channelID)
+	if err != nil {
+		return err
+	}
+	fmt.Println(cleared)
+	return nil
+}
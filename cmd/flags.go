@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/consts"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/wallet"
+)
+
+const (
+	// defaultMnemonic is the mnemonic the development chains are funded with.
+	defaultMnemonic = "math razor capable expose worth grape metal sunset metal sudden usage scheme"
+	// defaultPortID is the port ICS20TransferBank is bound to.
+	defaultPortID = "transfer"
+)
+
+// chainFlags are the flags to connect to a chain and its IBC contracts.
+// The addresses of the contracts default to the ones in pkg/consts.
+type chainFlags struct {
+	rpc        string
+	chainID    int64
+	clientType string
+	mnemonic   string
+	config     contractConfig
+}
+
+// register registers the flags to the flag set. The names of the flags are prefixed with the prefix
+// so that a command can take the flags of several chains.
+func (f *chainFlags) register(fs *flag.FlagSet, prefix string) {
+	fs.StringVar(&f.rpc, prefix+"rpc", "http://127.0.0.1:8545", "the JSON-RPC endpoint of the chain, or comma-separated endpoints of its nodes to fail over between")
+	fs.Int64Var(&f.chainID, prefix+"chain-id", 2018, "the EIP-155 chain ID of the chain")
+	fs.StringVar(&f.clientType, prefix+"client-type", ibcclient.BesuIBFT2Client, "the type of the light client tracking the chain")
+	fs.StringVar(&f.mnemonic, prefix+"mnemonic", defaultMnemonic, "the mnemonic of the account sending the transactions to the chain")
+
+	f.config = contractConfig{
+		ibcHost:           common.HexToAddress(consts.IBCHostAddress),
+		ibcHandler:        common.HexToAddress(consts.IBCHandlerAddress),
+		ibcIdentifier:     common.HexToAddress(consts.IBCIdentifierAddress),
+		ics20TransferBank: common.HexToAddress(consts.ICS20TransferBankAddress),
+		ics20Bank:         common.HexToAddress(consts.ICS20BankAddress),
+	}
+	addressVar(fs, &f.config.ibcHost, prefix+"ibc-host", "the address of IBCHost")
+	addressVar(fs, &f.config.ibcHandler, prefix+"ibc-handler", "the address of IBCHandler")
+	addressVar(fs, &f.config.ibcIdentifier, prefix+"ibc-identifier", "the address of IBCIdentifier")
+	addressVar(fs, &f.config.ics20TransferBank, prefix+"ics20-transfer-bank", "the address of ICS20TransferBank")
+	addressVar(fs, &f.config.ics20Bank, prefix+"ics20-bank", "the address of ICS20Bank")
+}
+
+// newChain connects to the chain, and binds the IBC contracts on it.
+func (f *chainFlags) newChain() (*host.Chain, error) {
+	var (
+		cl  *client.Client
+		err error
+	)
+	endpoints := strings.Split(f.rpc, ",")
+	switch {
+	case f.clientType == ibcclient.BesuIBFT2Client && len(endpoints) > 1:
+		cl, err = client.NewFailoverBesuClient(endpoints, f.clientType)
+	case f.clientType == ibcclient.BesuIBFT2Client:
+		cl, err = client.NewBesuClient(f.rpc, f.clientType)
+	case f.clientType == ibcclient.MockClient && len(endpoints) > 1:
+		cl, err = client.NewFailoverETHClient(endpoints, f.clientType)
+	case f.clientType == ibcclient.MockClient:
+		cl, err = client.NewETHClient(f.rpc, f.clientType)
+	default:
+		return nil, fmt.Errorf("unknown client type: '%v'", f.clientType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return host.NewChain(*cl, fmt.Sprint(f.chainID), f.config)
+}
+
+// txOpts returns the options of the transactions signed by the first account of the mnemonic.
+func (f *chainFlags) txOpts() (client.GenTxOpts, error) {
+	key, err := wallet.GetPrvKeyFromMnemonicAndHDWPath(f.mnemonic, "m/44'/60'/0'/0/0")
+	if err != nil {
+		return nil, err
+	}
+	return client.MakeGenTxOpts(big.NewInt(f.chainID), key), nil
+}
+
+// contractConfig is the ContractConfig given by the flags.
+type contractConfig struct {
+	ibcHost           common.Address
+	ibcHandler        common.Address
+	ibcIdentifier     common.Address
+	ics20TransferBank common.Address
+	ics20Bank         common.Address
+}
+
+var _ host.ContractConfig = contractConfig{}
+
+func (c contractConfig) GetIBCHostAddress() common.Address           { return c.ibcHost }
+func (c contractConfig) GetIBCHandlerAddress() common.Address        { return c.ibcHandler }
+func (c contractConfig) GetIBCIdentifierAddress() common.Address     { return c.ibcIdentifier }
+func (c contractConfig) GetICS20TransferBankAddress() common.Address { return c.ics20TransferBank }
+func (c contractConfig) GetICS20BankAddress() common.Address         { return c.ics20Bank }
+
+// addressValue is a flag.Value of an address.
+type addressValue common.Address
+
+func addressVar(fs *flag.FlagSet, p *common.Address, name, usage string) {
+	fs.Var((*addressValue)(p), name, usage)
+}
+
+func (v *addressValue) String() string {
+	return common.Address(*v).Hex()
+}
+
+func (v *addressValue) Set(s string) error {
+	if !common.IsHexAddress(s) {
+		return fmt.Errorf("invalid address: %v", s)
+	}
+	*v = addressValue(common.HexToAddress(s))
+	return nil
+}
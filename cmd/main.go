@@ -0,0 +1,64 @@
+// Command ibcsol inspects the IBC state of chains running the IBC contracts, and clears the packets pending on them.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+)
+
+// errCheckFailed is returned by a command whose check found problems, which are already reported.
+var errCheckFailed = errors.New("check failed")
+
+type command struct {
+	description string
+	run         func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"audit":        {"audit the commitments of a channel stored in IBCHost", runAudit},
+	"check":        {"check the consistency of a connection or a channel with its counterparty, or of all of them", runCheck},
+	"clear":        {"relay the pending packets and acknowledgements of a channel, except the timed-out packets", runClear},
+	"supply":       {"check that the tokens escrowed for a channel back the vouchers on the counterparty", runSupply},
+	"misbehaviour": {"watch the headers submitted to an IBFT2 client for misbehaviour", runMisbehaviour},
+	"staleness":    {"watch the clients tracking the counterparty for staleness and validator-set changes", runStaleness},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %v\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := cmd.run(ctx, os.Args[2:]); err == flag.ErrHelp {
+		os.Exit(2)
+	} else if err == errCheckFailed {
+		os.Exit(1)
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %v <command> [flags]\n\nCommands:\n", os.Args[0])
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12v%v\n", name, commands[name].description)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/metrics"
+)
+
+// registerMetricsFlag registers the flag of the address to expose the metrics at.
+func registerMetricsFlag(fs *flag.FlagSet, addr *string) {
+	fs.StringVar(addr, "metrics-addr", "", "the address to expose the Prometheus metrics at /metrics, e.g. :9090 (disabled if empty)")
+}
+
+// serveMetrics starts recording the metrics, and exposes them at the address until the context is done.
+// Nothing is done if the address is empty.
+func serveMetrics(ctx context.Context, addr string) error {
+	if addr == "" {
+		return nil
+	}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(prometheus.NewGoCollector(), prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+	recorder, err := metrics.NewPrometheusRecorder(reg)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(reg))
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	metrics.SetRecorder(recorder)
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/monitor"
+)
+
+// runMisbehaviour watches the headers submitted to an IBFT2 client and prints the evidence of misbehaviour.
+func runMisbehaviour(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("misbehaviour", flag.ContinueOnError)
+	var (
+		chainFlags, counterpartyFlags chainFlags
+		clientID                      string
+		fromBlock                     int64
+		pollInterval                  = monitor.DefaultPollInterval
+		metricsAddr                   string
+	)
+	chainFlags.register(fs, "")
+	counterpartyFlags.register(fs, "counterparty-")
+	fs.StringVar(&clientID, "client", "", "the client ID of the IBFT2 client tracking the counterparty chain (required)")
+	fs.Int64Var(&fromBlock, "from", -1, "the block number to start checking from, which defaults to the latest block")
+	fs.DurationVar(&pollInterval, "interval", pollInterval, "the interval to poll the chain for new blocks")
+	registerMetricsFlag(fs, &metricsAddr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if clientID == "" {
+		return errors.New("-client is required")
+	}
+
+	if err := serveMetrics(ctx, metricsAddr); err != nil {
+		return err
+	}
+
+	chain, err := chainFlags.newChain()
+	if err != nil {
+		return err
+	}
+	counterparty, err := counterpartyFlags.newChain()
+	if err != nil {
+		return err
+	}
+	if fromBlock < 0 {
+		head, err := chain.Client().BlockByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		fromBlock = head.Number().Int64()
+	}
+	m := monitor.NewMisbehaviourMonitor(chain, counterparty, clientID)
+	m.PollInterval = pollInterval
+	m.Alert = func(evidence monitor.Evidence) {
+		fmt.Println(evidence)
+	}
+	if err := m.Run(ctx, uint64(fromBlock)); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/monitor"
+)
+
+// runStaleness watches the clients tracking the counterparty chain, and prints the status of the clients that need attention.
+func runStaleness(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("staleness", flag.ContinueOnError)
+	var (
+		chainFlags, counterpartyFlags chainFlags
+		clientIDs                     string
+		maxLag                        = monitor.DefaultMaxLag
+		pollInterval                  = monitor.DefaultPollInterval
+		metricsAddr                   string
+		refresh                       bool
+	)
+	chainFlags.register(fs, "")
+	counterpartyFlags.register(fs, "counterparty-")
+	fs.StringVar(&clientIDs, "clients", "", "comma-separated client IDs to watch, which defaults to all the clients tracking the counterparty chain")
+	fs.Uint64Var(&maxLag, "max-lag", maxLag, "the number of blocks a client may lag behind the head of the counterparty chain")
+	fs.DurationVar(&pollInterval, "interval", pollInterval, "the interval to check the clients")
+	fs.BoolVar(&refresh, "refresh", false, "update the clients that are stale or whose trusted validator set is at risk")
+	registerMetricsFlag(fs, &metricsAddr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := serveMetrics(ctx, metricsAddr); err != nil {
+		return err
+	}
+
+	chain, err := chainFlags.newChain()
+	if err != nil {
+		return err
+	}
+	counterparty, err := counterpartyFlags.newChain()
+	if err != nil {
+		return err
+	}
+	m := monitor.NewStalenessMonitor(chain, counterparty)
+	if clientIDs != "" {
+		m.ClientIDs = strings.Split(clientIDs, ",")
+	}
+	m.MaxLag = maxLag
+	m.PollInterval = pollInterval
+	m.AutoRefresh = refresh
+	if refresh {
+		if m.TxOpts, err = chainFlags.txOpts(); err != nil {
+			return err
+		}
+	}
+	m.Alert = func(status monitor.ClientStatus) {
+		fmt.Println(status)
+	}
+	if err := m.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
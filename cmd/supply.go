@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/audit"
+)
+
+// runSupply checks that the tokens escrowed for a channel equal the vouchers outstanding on the counterparty chain.
+func runSupply(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("supply", flag.ContinueOnError)
+	var (
+		chainFlags, counterpartyFlags chainFlags
+		portID, channelID             string
+		baseDenom, holders            string
+	)
+	chainFlags.register(fs, "")
+	counterpartyFlags.register(fs, "counterparty-")
+	fs.StringVar(&portID, "port", defaultPortID, "the port ID of the channel")
+	fs.StringVar(&channelID, "channel", "", "the channel ID of the channel (required)")
+	fs.StringVar(&baseDenom, "denom", "", "the denomination of the tokens native to the chain (required)")
+	fs.StringVar(&holders, "holders", "", "the comma-separated addresses of the voucher holders not found in the transfer packets")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if channelID == "" || baseDenom == "" {
+		return errors.New("-channel and -denom are required")
+	}
+	var extraHolders []common.Address
+	for _, s := range strings.Split(holders, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		} else if !common.IsHexAddress(s) {
+			return fmt.Errorf("invalid address: %v", s)
+		}
+		extraHolders = append(extraHolders, common.HexToAddress(s))
+	}
+
+	chain, err := chainFlags.newChain()
+	if err != nil {
+		return err
+	}
+	counterparty, err := counterpartyFlags.newChain()
+	if err != nil {
+		return err
+	}
+	report, err := audit.CheckSupply(ctx, chain, counterparty, portID, channelID, baseDenom, extraHolders...)
+	if err != nil {
+		return err
+	}
+	fmt.Println(report)
+	if !report.OK() {
+		return errCheckFailed
+	}
+	return nil
+}
@@ -8,8 +8,6 @@ import "./IBCHost.sol";
 
 library IBCConnection {
 
-    string constant commitmentPrefix = "ibc";
-
     /* Public functions */
 
     // ConnOpenInit initialises a connection attempt on chain A. The generated connection identifier
@@ -54,7 +52,7 @@ library IBCConnection {
             counterparty: Counterparty.Data({
                 client_id: msg_.clientId,
                 connection_id: "",
-                prefix: MerklePrefix.Data({key_prefix: bytes(commitmentPrefix)})
+                prefix: MerklePrefix.Data({key_prefix: host.getCommitmentPrefix()})
             })
         });
 
@@ -88,7 +86,7 @@ library IBCConnection {
         Counterparty.Data memory expectedCounterparty = Counterparty.Data({
             client_id: connection.client_id,
             connection_id: msg_.connectionId,
-            prefix: MerklePrefix.Data({key_prefix: bytes(commitmentPrefix)}) 
+            prefix: MerklePrefix.Data({key_prefix: host.getCommitmentPrefix()}) 
         });
 
         ConnectionEnd.Data memory expectedConnection = ConnectionEnd.Data({
@@ -122,7 +120,7 @@ library IBCConnection {
         Counterparty.Data memory expectedCounterparty = Counterparty.Data({
             client_id: connection.client_id,
             connection_id: msg_.connectionId,
-            prefix: MerklePrefix.Data({key_prefix: bytes(commitmentPrefix)}) 
+            prefix: MerklePrefix.Data({key_prefix: host.getCommitmentPrefix()}) 
         });
 
         ConnectionEnd.Data memory expectedConnection = ConnectionEnd.Data({
@@ -159,6 +159,11 @@ contract IBCHandler {
         host.setExpectedTimePerBlock(expectedTimePerBlock_);
     }
 
+    function setCommitmentPrefix(bytes calldata commitmentPrefix_) external {
+        onlyOwner();
+        host.setCommitmentPrefix(commitmentPrefix_);
+    }
+
     function lookupModuleByPortId(string memory portId) internal view returns (IModuleCallbacks) {
         (address module, bool found) = host.getModuleOwner(IBCIdentifier.portCapabilityPath(portId));
         require(found);
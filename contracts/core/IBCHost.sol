@@ -36,8 +36,11 @@ contract IBCHost {
 
     uint64 expectedTimePerBlock;
 
+    bytes commitmentPrefix;
+
     constructor() {
         owner = msg.sender;
+        commitmentPrefix = bytes("ibc");
     }
 
     function setIBCModule(address ibcModule_) external {
@@ -167,6 +170,7 @@ contract IBCHost {
     function setNextSequenceRecv(string calldata portId, string calldata channelId, uint64 sequence) external {
         onlyIBCModule();
         nextSequenceRecvs[portId][channelId] = sequence;
+        commitments[IBCIdentifier.nextSequenceRecvCommitmentKey(portId, channelId)] = bytes32(uint256(sequence));
     }
 
     function getNextSequenceRecv(string calldata portId, string calldata channelId) external view returns (uint64) {
@@ -219,6 +223,7 @@ contract IBCHost {
     function setPacketReceipt(string calldata portId, string calldata channelId, uint64 sequence) external {
         onlyIBCModule();
         packetReceipts[portId][channelId][sequence] = true;
+        commitments[IBCIdentifier.packetReceiptCommitmentKey(portId, channelId, sequence)] = bytes32(uint256(1));
     }
 
     function hasPacketReceipt(string calldata portId, string calldata channelId, uint64 sequence) external view returns (bool) {
@@ -234,6 +239,20 @@ contract IBCHost {
         expectedTimePerBlock = expectedTimePerBlock_;
     }
 
+    // commitment prefix
+
+    function getCommitmentPrefix() external view returns (bytes memory) {
+        return commitmentPrefix;
+    }
+
+    function setCommitmentPrefix(bytes calldata commitmentPrefix_) external {
+        onlyIBCModule();
+        require(commitmentPrefix_.length > 0, "commitmentPrefix must not be empty");
+        // the counterparty chains keep the prefix in their connections
+        require(nextConnectionSequence == 0, "commitmentPrefix cannot be changed after a connection is created");
+        commitmentPrefix = commitmentPrefix_;
+    }
+
     // capabilities
 
     function claimCapability(bytes calldata name, address addr) external {
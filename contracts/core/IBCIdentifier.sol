@@ -12,6 +12,8 @@ library IBCIdentifier {
     uint8 constant channelPrefix = 3;
     uint8 constant packetPrefix = 4;
     uint8 constant packetAckPrefix = 5;
+    uint8 constant packetReceiptPrefix = 6;
+    uint8 constant nextSequenceRecvPrefix = 7;
 
     // Commitment key generator
 
@@ -39,6 +41,14 @@ library IBCIdentifier {
         return keccak256(abi.encodePacked(packetAckPrefix, portId, "/", channelId, "/", sequence));
     }
 
+    function packetReceiptCommitmentKey(string memory portId, string memory channelId, uint64 sequence) public pure returns (bytes32) {
+        return keccak256(abi.encodePacked(packetReceiptPrefix, portId, "/", channelId, "/", sequence));
+    }
+
+    function nextSequenceRecvCommitmentKey(string memory portId, string memory channelId) public pure returns (bytes32) {
+        return keccak256(abi.encodePacked(nextSequenceRecvPrefix, portId, "/", channelId));
+    }
+
     // Slot calculator
 
     function clientStateCommitmentSlot(string calldata clientId) external pure returns (bytes32) {
@@ -65,6 +75,14 @@ library IBCIdentifier {
         return keccak256(abi.encodePacked(packetAcknowledgementCommitmentKey(portId, channelId, sequence), commitmentSlot));
     }
 
+    function packetReceiptCommitmentSlot(string calldata portId, string calldata channelId, uint64 sequence) external pure returns (bytes32) {
+        return keccak256(abi.encodePacked(packetReceiptCommitmentKey(portId, channelId, sequence), commitmentSlot));
+    }
+
+    function nextSequenceRecvCommitmentSlot(string calldata portId, string calldata channelId) external pure returns (bytes32) {
+        return keccak256(abi.encodePacked(nextSequenceRecvCommitmentKey(portId, channelId), commitmentSlot));
+    }
+
     // CapabilityPath
 
     function portCapabilityPath(string calldata portId) external pure returns (bytes memory) {
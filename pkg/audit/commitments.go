@@ -0,0 +1,289 @@
+// Package audit checks the IBC state stored on-chain against the state recomputed in Go.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+)
+
+// Kind is the kind of a commitment stored in IBCHost.
+type Kind string
+
+const (
+	KindConnection                Kind = "connection"
+	KindChannel                   Kind = "channel"
+	KindPacketCommitment          Kind = "packet_commitment"
+	KindAcknowledgementCommitment Kind = "acknowledgement_commitment"
+)
+
+// Mismatch is a commitment stored in IBCHost that differs from the recomputed one.
+// Expected is nil if no commitment is expected to be stored, and Actual is nil if no commitment is stored.
+type Mismatch struct {
+	Kind     Kind
+	Path     string
+	Expected []byte
+	Actual   []byte
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%v %v: expected=%v actual=%v", m.Kind, m.Path, formatCommitment(m.Expected), formatCommitment(m.Actual))
+}
+
+// CommitmentReport is the result of auditing the commitments of a channel.
+type CommitmentReport struct {
+	PortID    string
+	ChannelID string
+	// Height is the block number the commitments are audited at.
+	Height uint64
+	// Connections are the connection IDs of the channel hops whose commitments were audited.
+	Connections []string
+	// Packets is the number of packets sent on the channel.
+	Packets int
+	// ClearedPackets is the number of packets whose commitments were deleted on acknowledgement or timeout.
+	ClearedPackets int
+	// Acknowledgements is the number of acknowledgements written for the packets received on the channel.
+	Acknowledgements int
+	Mismatches       []Mismatch
+}
+
+// OK returns true if no mismatch is found.
+func (r *CommitmentReport) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+func (r *CommitmentReport) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "port=%v channel=%v height=%v connections=%v packets=%v cleared=%v acknowledgements=%v mismatches=%v",
+		r.PortID, r.ChannelID, r.Height, r.Connections, r.Packets, r.ClearedPackets, r.Acknowledgements, len(r.Mismatches))
+	for _, m := range r.Mismatches {
+		fmt.Fprintf(&buf, "\n  %v", m)
+	}
+	return buf.String()
+}
+
+// AuditCommitments recomputes the commitments of the channel, its connection hops, the packets sent
+// on it and the acknowledgements written for the packets received on it, and compares them with the
+// commitments stored in IBCHost. The packets and the acknowledgements are taken from the SendPacket
+// and WriteAcknowledgement events of IBCHandler. All the state is read at the latest block so that
+// transactions committed while auditing do not cause false mismatches.
+func AuditCommitments(ctx context.Context, chain *host.Chain, portID, channelID string) (*CommitmentReport, error) {
+	block, err := chain.Client().BlockByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	a := auditor{chain: chain, height: block.Number()}
+	report := &CommitmentReport{PortID: portID, ChannelID: channelID, Height: block.NumberU64()}
+
+	channel, found, err := chain.IBCHost.GetChannel(a.callOpts(ctx), portID, channelID)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, fmt.Errorf("channel not found: portID=%v channelID=%v", portID, channelID)
+	}
+	bz, err := proto.Marshal(convert.ChannelToPB(channel))
+	if err != nil {
+		return nil, err
+	}
+	slot, err := chain.IBCIdentifier.ChannelCommitmentSlot(a.callOpts(ctx), portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.compareStorage(ctx, report, KindChannel, channelPath(portID, channelID), slot, crypto.Keccak256(bz)); err != nil {
+		return nil, err
+	}
+
+	for _, connectionID := range channel.ConnectionHops {
+		conn, found, err := chain.IBCHost.GetConnection(a.callOpts(ctx), connectionID)
+		if err != nil {
+			return nil, err
+		} else if !found {
+			return nil, fmt.Errorf("connection not found: %v", connectionID)
+		}
+		bz, err := proto.Marshal(convert.ConnectionEndToPB(conn))
+		if err != nil {
+			return nil, err
+		}
+		slot, err := chain.IBCIdentifier.ConnectionCommitmentSlot(a.callOpts(ctx), connectionID)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.compareStorage(ctx, report, KindConnection, connectionPath(connectionID), slot, crypto.Keccak256(bz)); err != nil {
+			return nil, err
+		}
+		report.Connections = append(report.Connections, connectionID)
+	}
+
+	if err := a.auditPacketCommitments(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := a.auditAcknowledgementCommitments(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+type auditor struct {
+	chain  *host.Chain
+	height *big.Int
+}
+
+func (a auditor) callOpts(ctx context.Context) *bind.CallOpts {
+	opts := a.chain.CallOpts(ctx)
+	opts.BlockNumber = a.height
+	return opts
+}
+
+func (a auditor) filterOpts(ctx context.Context) *bind.FilterOpts {
+	end := a.height.Uint64()
+	return &bind.FilterOpts{Start: 0, End: &end, Context: ctx}
+}
+
+// compareStorage compares the commitment stored at the slot of IBCHost with the expected one.
+func (a auditor) compareStorage(ctx context.Context, report *CommitmentReport, kind Kind, path string, slot [32]byte, expected []byte) error {
+	actual, err := a.chain.Client().StorageAt(ctx, a.chain.IBCHostAddress(), common.Hash(slot), a.height)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, actual) {
+		report.Mismatches = append(report.Mismatches, Mismatch{Kind: kind, Path: path, Expected: expected, Actual: nonZero(actual)})
+	}
+	return nil
+}
+
+// auditPacketCommitments compares the commitments of the packets sent on the channel. A missing commitment
+// means that the packet has been acknowledged or timed out, but any commitment stored for a sequence
+// whose packet is not found in the events is reported.
+func (a auditor) auditPacketCommitments(ctx context.Context, report *CommitmentReport) error {
+	packets, err := a.chain.SentPackets(ctx, a.filterOpts(ctx))
+	if err != nil {
+		return err
+	}
+
+	sent := make(map[uint64]bool)
+	for _, packet := range packets {
+		if packet.SourcePort != report.PortID || packet.SourceChannel != report.ChannelID || sent[packet.Sequence] {
+			continue
+		}
+		sent[packet.Sequence] = true
+		report.Packets++
+
+		commitment, found, err := a.chain.IBCHost.GetPacketCommitment(a.callOpts(ctx), packet.SourcePort, packet.SourceChannel, packet.Sequence)
+		if err != nil {
+			return err
+		} else if !found {
+			report.ClearedPackets++
+			continue
+		}
+		if expected := channeltypes.CommitPacket(packet); !bytes.Equal(expected, commitment[:]) {
+			report.Mismatches = append(report.Mismatches, Mismatch{
+				Kind:     KindPacketCommitment,
+				Path:     packetCommitmentPath(packet.SourcePort, packet.SourceChannel, packet.Sequence),
+				Expected: expected,
+				Actual:   commitment[:],
+			})
+		}
+	}
+
+	nextSequenceSend, err := a.chain.IBCHost.GetNextSequenceSend(a.callOpts(ctx), report.PortID, report.ChannelID)
+	if err != nil {
+		return err
+	}
+	for seq := uint64(1); seq < nextSequenceSend; seq++ {
+		if sent[seq] {
+			continue
+		}
+		commitment, found, err := a.chain.IBCHost.GetPacketCommitment(a.callOpts(ctx), report.PortID, report.ChannelID, seq)
+		if err != nil {
+			return err
+		} else if found {
+			report.Mismatches = append(report.Mismatches, Mismatch{
+				Kind:   KindPacketCommitment,
+				Path:   packetCommitmentPath(report.PortID, report.ChannelID, seq),
+				Actual: commitment[:],
+			})
+		}
+	}
+	return nil
+}
+
+// auditAcknowledgementCommitments compares the commitments of the acknowledgements written for the packets
+// received on the channel. The commitments of acknowledgements are never deleted.
+func (a auditor) auditAcknowledgementCommitments(ctx context.Context, report *CommitmentReport) error {
+	it, err := a.chain.IBCHandler.FilterWriteAcknowledgement(a.filterOpts(ctx))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		ev := it.Event
+		if ev.DestinationPortId != report.PortID || ev.DestinationChannel != report.ChannelID {
+			continue
+		}
+		report.Acknowledgements++
+
+		commitment, found, err := a.chain.IBCHost.GetPacketAcknowledgementCommitment(a.callOpts(ctx), ev.DestinationPortId, ev.DestinationChannel, ev.Sequence)
+		if err != nil {
+			return err
+		}
+		expected := channeltypes.CommitAcknowledgement(ev.Acknowledgement)
+		if !found || !bytes.Equal(expected, commitment[:]) {
+			m := Mismatch{
+				Kind:     KindAcknowledgementCommitment,
+				Path:     packetAcknowledgementPath(ev.DestinationPortId, ev.DestinationChannel, ev.Sequence),
+				Expected: expected,
+			}
+			if found {
+				m.Actual = commitment[:]
+			}
+			report.Mismatches = append(report.Mismatches, m)
+		}
+	}
+	return it.Error()
+}
+
+// nonZero returns nil if the storage value is zero, which means that no commitment is stored.
+func nonZero(value []byte) []byte {
+	for _, b := range value {
+		if b != 0 {
+			return value
+		}
+	}
+	return nil
+}
+
+func formatCommitment(commitment []byte) string {
+	if commitment == nil {
+		return "none"
+	}
+	return fmt.Sprintf("0x%x", commitment)
+}
+
+// The paths of the commitments defined in ICS-24, which are used to identify them in the reports.
+
+func connectionPath(connectionID string) string {
+	return fmt.Sprintf("connections/%v", connectionID)
+}
+
+func channelPath(portID, channelID string) string {
+	return fmt.Sprintf("channelEnds/ports/%v/channels/%v", portID, channelID)
+}
+
+func packetCommitmentPath(portID, channelID string, sequence uint64) string {
+	return fmt.Sprintf("commitments/ports/%v/channels/%v/sequences/%v", portID, channelID, sequence)
+}
+
+func packetAcknowledgementPath(portID, channelID string, sequence uint64) string {
+	return fmt.Sprintf("acks/ports/%v/channels/%v/sequences/%v", portID, channelID, sequence)
+}
@@ -0,0 +1,23 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMismatchString(t *testing.T) {
+	m := Mismatch{
+		Kind:     KindPacketCommitment,
+		Path:     packetCommitmentPath("transfer", "channel-0", 1),
+		Expected: []byte{0x01, 0x02},
+	}
+	require.Equal(t, "packet_commitment commitments/ports/transfer/channels/channel-0/sequences/1: expected=0x0102 actual=none", m.String())
+}
+
+func TestNonZero(t *testing.T) {
+	require.Nil(t, nonZero(make([]byte, 32)))
+	require.Nil(t, nonZero(nil))
+	value := append(make([]byte, 31), 1)
+	require.Equal(t, value, nonZero(value))
+}
@@ -0,0 +1,641 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchost"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	ibft2clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/ibft2"
+	connectiontypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/connection"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+)
+
+// Inconsistency is a state of a chain that does not match the state of its counterparty chain.
+type Inconsistency struct {
+	// ChainID is the chain ID of the chain the state is stored on.
+	ChainID string
+	Path    string
+	Message string
+}
+
+func (i Inconsistency) String() string {
+	return fmt.Sprintf("chain=%v %v: %v", i.ChainID, i.Path, i.Message)
+}
+
+// ConsistencyReport is the result of checking the state of a chain against its counterparty chain.
+type ConsistencyReport struct {
+	ChainID             string
+	CounterpartyChainID string
+	// Checked are the paths of the states checked on either chain.
+	Checked         []string
+	Inconsistencies []Inconsistency
+	// Unpaired are the connections and the channels in INIT for which no counterparty has been created on
+	// the counterparty chain yet. They are only looked for by CheckAll.
+	Unpaired []Inconsistency
+	// Notes are what could not be checked, such as the chains tracked by the mock clients.
+	Notes []string
+}
+
+// OK returns true if no inconsistency is found.
+func (r *ConsistencyReport) OK() bool {
+	return len(r.Inconsistencies) == 0
+}
+
+func (r *ConsistencyReport) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "chain=%v counterparty=%v checked=%v inconsistencies=%v",
+		r.ChainID, r.CounterpartyChainID, r.Checked, len(r.Inconsistencies))
+	for _, i := range r.Inconsistencies {
+		fmt.Fprintf(&buf, "\n  %v", i)
+	}
+	if len(r.Unpaired) > 0 {
+		fmt.Fprintf(&buf, "\nunpaired=%v", len(r.Unpaired))
+		for _, u := range r.Unpaired {
+			fmt.Fprintf(&buf, "\n  %v", u)
+		}
+	}
+	for _, n := range r.Notes {
+		fmt.Fprintf(&buf, "\nnote: %v", n)
+	}
+	return buf.String()
+}
+
+// CheckConnection checks that the connection on the chain and its counterparty connection point to each
+// other, that their states are reachable by a connection handshake, and that their clients track each
+// other's chain. The counterparty connection is not checked while the connection is in INIT since the
+// counterparty connection ID is not known yet.
+func CheckConnection(ctx context.Context, chain, counterparty *host.Chain, connectionID string) (*ConsistencyReport, error) {
+	c := newChecker(chain, counterparty)
+	if err := c.checkConnection(ctx, chain, counterparty, connectionID); err != nil {
+		return nil, err
+	}
+	return c.report, nil
+}
+
+// CheckChannel checks that the channel on the chain and its counterparty channel point to each other,
+// that their states are reachable by a channel handshake, and that their orderings match. The connection
+// hops of both channels are checked as CheckConnection does. For an ordered channel, the next sequence
+// to send on either chain must not be less than the next sequence to receive on the other chain.
+func CheckChannel(ctx context.Context, chain, counterparty *host.Chain, portID, channelID string) (*ConsistencyReport, error) {
+	c := newChecker(chain, counterparty)
+	if err := c.checkChannel(ctx, portID, channelID); err != nil {
+		return nil, err
+	}
+	return c.report, nil
+}
+
+// CheckAll checks every connection and channel on the chain and on the counterparty chain that is between
+// the two chains, as CheckConnection and CheckChannel do. The connections and the channels in INIT for which
+// no counterparty has been created yet are reported as unpaired.
+//
+// A connection is between the chains if its client tracks the other chain, or a connection on the other
+// chain points to it. The mock client does not hold the chain it tracks, so the connections on mock clients
+// are assumed to be between the chains, which is noted in the report; a connection to a third chain on a
+// mock client is reported as unpaired or inconsistent.
+//
+// The port of a channel is not recorded when its ID is generated, so a channel is looked up under the given
+// ports and the ports of the counterparty channels on the other chain. The channels bound to none of them
+// are noted in the report as not checked.
+func CheckAll(ctx context.Context, chain, counterparty *host.Chain, portIDs ...string) (*ConsistencyReport, error) {
+	c := newChecker(chain, counterparty)
+	rev := c.reversed()
+
+	conns, err := listConnections(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	cpConns, err := listConnections(ctx, counterparty)
+	if err != nil {
+		return nil, err
+	}
+	paired, err := c.pairedConnections(ctx, conns, cpConns)
+	if err != nil {
+		return nil, err
+	}
+	cpPaired, err := rev.pairedConnections(ctx, cpConns, conns)
+	if err != nil {
+		return nil, err
+	}
+	// the counterparty connections checked along with the connections on the chain
+	covered := make(map[string]bool)
+	for _, conn := range conns {
+		if !paired[conn.id] {
+			continue
+		}
+		if err := c.checkConnectionEnd(ctx, conn, cpConns); err != nil {
+			return nil, err
+		}
+		covered[conn.end.Counterparty.ConnectionId] = true
+	}
+	for _, conn := range cpConns {
+		if !cpPaired[conn.id] || covered[conn.id] {
+			continue
+		}
+		if err := rev.checkConnectionEnd(ctx, conn, conns); err != nil {
+			return nil, err
+		}
+	}
+
+	chs, cpChs, err := c.listChannelPair(ctx, portIDs)
+	if err != nil {
+		return nil, err
+	}
+	coveredChs := make(map[string]bool)
+	for _, ch := range chs {
+		if !isOnConnections(ch.end, paired) {
+			continue
+		}
+		if err := c.checkChannelEnd(ctx, ch, cpChs); err != nil {
+			return nil, err
+		}
+		coveredChs[channelPath(ch.end.Counterparty.PortId, ch.end.Counterparty.ChannelId)] = true
+	}
+	for _, ch := range cpChs {
+		if !isOnConnections(ch.end, cpPaired) || coveredChs[channelPath(ch.portID, ch.channelID)] {
+			continue
+		}
+		if err := rev.checkChannelEnd(ctx, ch, chs); err != nil {
+			return nil, err
+		}
+	}
+	return c.report, nil
+}
+
+// connection is a connection found on a chain.
+type connection struct {
+	id  string
+	end ibchost.ConnectionEndData
+}
+
+// channel is a channel found on a chain with the port it is bound to.
+type channel struct {
+	portID    string
+	channelID string
+	end       ibchost.ChannelData
+}
+
+func listConnections(ctx context.Context, chain *host.Chain) ([]connection, error) {
+	ids, err := chain.GetConnectionIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conns := make([]connection, 0, len(ids))
+	for _, id := range ids {
+		end, found, err := chain.IBCHost.GetConnection(chain.CallOpts(ctx), id)
+		if err != nil {
+			return nil, err
+		} else if !found {
+			return nil, fmt.Errorf("connection not found: %v", id)
+		}
+		conns = append(conns, connection{id: id, end: end})
+	}
+	return conns, nil
+}
+
+// listChannelPair returns the channels on the chain and on the counterparty chain that are bound to the
+// ports, or to the ports of the counterparty channels on the other chain.
+func (c *checker) listChannelPair(ctx context.Context, portIDs []string) ([]channel, []channel, error) {
+	ids, err := c.chain.GetChannelIDs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	cpIDs, err := c.counterparty.GetChannelIDs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	chs, rest, err := findChannels(ctx, c.chain, ids, portIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	cpPortIDs := append(append([]string{}, portIDs...), counterpartyPorts(chs)...)
+	cpChs, cpRest, err := findChannels(ctx, c.counterparty, cpIDs, cpPortIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	more, rest, err := findChannels(ctx, c.chain, rest, counterpartyPorts(cpChs))
+	if err != nil {
+		return nil, nil, err
+	}
+	chs = append(chs, more...)
+	for _, id := range rest {
+		c.note(c.chain, fmt.Sprintf("channels/%v", id), "not bound to the ports %v or those of the counterparty channels, so it is not checked", portIDs)
+	}
+	for _, id := range cpRest {
+		c.note(c.counterparty, fmt.Sprintf("channels/%v", id), "not bound to the ports %v or those of the counterparty channels, so it is not checked", portIDs)
+	}
+	return chs, cpChs, nil
+}
+
+// findChannels returns the channels with the IDs bound to one of the ports, and the IDs of the rest.
+func findChannels(ctx context.Context, chain *host.Chain, ids, portIDs []string) ([]channel, []string, error) {
+	var (
+		chs  []channel
+		rest []string
+	)
+	for _, id := range ids {
+		found := false
+		for _, portID := range portIDs {
+			end, ok, err := chain.IBCHost.GetChannel(chain.CallOpts(ctx), portID, id)
+			if err != nil {
+				return nil, nil, err
+			} else if ok {
+				chs = append(chs, channel{portID: portID, channelID: id, end: end})
+				found = true
+				break
+			}
+		}
+		if !found {
+			rest = append(rest, id)
+		}
+	}
+	return chs, rest, nil
+}
+
+func counterpartyPorts(chs []channel) []string {
+	seen := make(map[string]bool)
+	var ports []string
+	for _, ch := range chs {
+		if portID := ch.end.Counterparty.PortId; !seen[portID] {
+			seen[portID] = true
+			ports = append(ports, portID)
+		}
+	}
+	return ports
+}
+
+// pairedConnections returns the IDs of the connections on the chain that are between the chains.
+func (c *checker) pairedConnections(ctx context.Context, conns, cpConns []connection) (map[string]bool, error) {
+	paired := make(map[string]bool)
+	for _, conn := range conns {
+		if isPointedBy(conn.id, cpConns) {
+			paired[conn.id] = true
+			continue
+		}
+		tracked, known, err := tracksChain(ctx, c.chain, c.counterparty, conn.end.ClientId)
+		if err != nil {
+			return nil, err
+		}
+		if !known {
+			c.note(c.chain, connectionPath(conn.id), "client %v does not identify the chain it tracks, so the connection is assumed to be with the counterparty chain", conn.end.ClientId)
+		}
+		paired[conn.id] = tracked || !known
+	}
+	return paired, nil
+}
+
+// checkConnectionEnd checks the connection on the chain, and reports it as unpaired if it is in INIT and
+// no connection on the counterparty chain points to it.
+func (c *checker) checkConnectionEnd(ctx context.Context, conn connection, cpConns []connection) error {
+	if conn.end.Counterparty.ConnectionId == "" && !isPointedBy(conn.id, cpConns) {
+		c.unpaired(c.chain, connectionPath(conn.id), "no counterparty connection has been created in %v", connectiontypes.ConnectionEnd_State(conn.end.State))
+	}
+	return c.checkConnection(ctx, c.chain, c.counterparty, conn.id)
+}
+
+// checkChannelEnd checks the channel on the chain, and reports it as unpaired if it is in INIT and no channel
+// on the counterparty chain points to it.
+func (c *checker) checkChannelEnd(ctx context.Context, ch channel, cpChs []channel) error {
+	if ch.end.Counterparty.ChannelId == "" {
+		pointed := false
+		for _, cpCh := range cpChs {
+			if cpCh.end.Counterparty.PortId == ch.portID && cpCh.end.Counterparty.ChannelId == ch.channelID {
+				pointed = true
+				break
+			}
+		}
+		if !pointed {
+			c.unpaired(c.chain, channelPath(ch.portID, ch.channelID), "no counterparty channel has been created in %v", channeltypes.Channel_State(ch.end.State))
+		}
+	}
+	return c.checkChannel(ctx, ch.portID, ch.channelID)
+}
+
+// isPointedBy returns true if one of the connections has the connection as its counterparty.
+func isPointedBy(connectionID string, conns []connection) bool {
+	for _, conn := range conns {
+		if conn.end.Counterparty.ConnectionId == connectionID {
+			return true
+		}
+	}
+	return false
+}
+
+// isOnConnections returns true if the channel is on one of the connections. A channel with more than one
+// connection hop is included so that it is reported by the check.
+func isOnConnections(ch ibchost.ChannelData, connectionIDs map[string]bool) bool {
+	return len(ch.ConnectionHops) != 1 || connectionIDs[ch.ConnectionHops[0]]
+}
+
+// tracksChain returns whether the client on the chain tracks the counterparty chain, and false as the second
+// value if the client does not identify the chain it tracks, which is the case for the mock client.
+func tracksChain(ctx context.Context, chain, counterparty *host.Chain, clientID string) (bool, bool, error) {
+	opts := chain.CallOpts(ctx)
+	clientType, err := chain.IBCHost.GetClientType(opts, clientID)
+	if err != nil {
+		return false, false, err
+	} else if clientType != ibcclient.BesuIBFT2Client {
+		return false, false, nil
+	}
+	cs, err := chain.GetIBFT2ClientState(ctx, clientID)
+	if err != nil {
+		return false, false, err
+	}
+	tracked := cs.ChainId == counterparty.ChainIDString() && common.BytesToAddress(cs.IbcStoreAddress) == counterparty.IBCHostAddress()
+	return tracked, true, nil
+}
+
+type checker struct {
+	chain        *host.Chain
+	counterparty *host.Chain
+	report       *ConsistencyReport
+	// seen are the entries of the report, which are added once even if a state is checked more than once.
+	seen map[string]bool
+}
+
+func newChecker(chain, counterparty *host.Chain) *checker {
+	return &checker{
+		chain:        chain,
+		counterparty: counterparty,
+		report: &ConsistencyReport{
+			ChainID:             chain.ChainIDString(),
+			CounterpartyChainID: counterparty.ChainIDString(),
+		},
+		seen: make(map[string]bool),
+	}
+}
+
+// reversed returns the checker of the counterparty chain against the chain, which adds to the same report.
+func (c *checker) reversed() *checker {
+	return &checker{chain: c.counterparty, counterparty: c.chain, report: c.report, seen: c.seen}
+}
+
+// once returns true if the entry has not been added to the report yet.
+func (c *checker) once(kind string, chain *host.Chain, entry string) bool {
+	key := fmt.Sprintf("%v/%v/%v", kind, chain.ChainIDString(), entry)
+	if c.seen[key] {
+		return false
+	}
+	c.seen[key] = true
+	return true
+}
+
+func (c *checker) checked(chain *host.Chain, path string) {
+	if c.once("checked", chain, path) {
+		c.report.Checked = append(c.report.Checked, path)
+	}
+}
+
+func (c *checker) fail(chain *host.Chain, path string, format string, args ...interface{}) {
+	i := Inconsistency{
+		ChainID: chain.ChainIDString(),
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+	if c.once("inconsistency", chain, i.String()) {
+		c.report.Inconsistencies = append(c.report.Inconsistencies, i)
+	}
+}
+
+func (c *checker) unpaired(chain *host.Chain, path string, format string, args ...interface{}) {
+	i := Inconsistency{
+		ChainID: chain.ChainIDString(),
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+	if c.once("unpaired", chain, i.String()) {
+		c.report.Unpaired = append(c.report.Unpaired, i)
+	}
+}
+
+func (c *checker) note(chain *host.Chain, path string, format string, args ...interface{}) {
+	n := fmt.Sprintf("chain=%v %v: %v", chain.ChainIDString(), path, fmt.Sprintf(format, args...))
+	if c.once("note", chain, n) {
+		c.report.Notes = append(c.report.Notes, n)
+	}
+}
+
+func (c *checker) checkConnection(ctx context.Context, chain, counterparty *host.Chain, connectionID string) error {
+	conn, found, err := chain.IBCHost.GetConnection(chain.CallOpts(ctx), connectionID)
+	if err != nil {
+		return err
+	} else if !found {
+		return fmt.Errorf("connection not found: %v", connectionID)
+	}
+	path := connectionPath(connectionID)
+	c.checked(chain, path)
+	if err := c.checkClient(ctx, chain, counterparty, conn.ClientId); err != nil {
+		return err
+	}
+	prefix, err := counterparty.GetCommitmentPrefix(ctx)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(conn.Counterparty.Prefix.KeyPrefix, prefix) {
+		c.fail(chain, path, "counterparty prefix is '%s', but the counterparty chain has '%s'", conn.Counterparty.Prefix.KeyPrefix, prefix)
+	}
+
+	state := connectiontypes.ConnectionEnd_State(conn.State)
+	if conn.Counterparty.ConnectionId == "" {
+		if state != connectiontypes.ConnectionEnd_STATE_INIT {
+			c.fail(chain, path, "counterparty connection ID is empty in %v", state)
+		}
+		return nil
+	}
+
+	cpConn, found, err := counterparty.IBCHost.GetConnection(counterparty.CallOpts(ctx), conn.Counterparty.ConnectionId)
+	if err != nil {
+		return err
+	}
+	cpPath := connectionPath(conn.Counterparty.ConnectionId)
+	if !found {
+		c.fail(counterparty, cpPath, "counterparty connection of %v not found", connectionID)
+		return nil
+	}
+	c.checked(counterparty, cpPath)
+	if err := c.checkClient(ctx, counterparty, chain, cpConn.ClientId); err != nil {
+		return err
+	}
+	if conn.Counterparty.ClientId != cpConn.ClientId {
+		c.fail(chain, path, "counterparty client ID is %v, but the counterparty connection has %v", conn.Counterparty.ClientId, cpConn.ClientId)
+	}
+	if cpConn.Counterparty.ConnectionId != "" && cpConn.Counterparty.ConnectionId != connectionID {
+		c.fail(counterparty, cpPath, "counterparty connection ID is %v, but expected %v", cpConn.Counterparty.ConnectionId, connectionID)
+	}
+	if cpConn.Counterparty.ClientId != conn.ClientId {
+		c.fail(counterparty, cpPath, "counterparty client ID is %v, but the counterparty connection has %v", cpConn.Counterparty.ClientId, conn.ClientId)
+	}
+	if cpConn.DelayPeriod != conn.DelayPeriod {
+		c.fail(counterparty, cpPath, "delay period is %v, but the counterparty connection has %v", cpConn.DelayPeriod, conn.DelayPeriod)
+	}
+	if cpState := connectiontypes.ConnectionEnd_State(cpConn.State); !isValidConnectionStatePair(state, cpState) {
+		c.fail(chain, path, "state is %v, but the counterparty connection is %v", state, cpState)
+	}
+	return nil
+}
+
+// checkClient checks that the client on the chain tracks the counterparty chain. The mock client
+// does not hold the identity of the tracked chain, so only its existence is checked.
+func (c *checker) checkClient(ctx context.Context, chain, counterparty *host.Chain, clientID string) error {
+	opts := chain.CallOpts(ctx)
+	path := fmt.Sprintf("clients/%v/clientState", clientID)
+	bz, found, err := chain.IBCHost.GetClientState(opts, clientID)
+	if err != nil {
+		return err
+	} else if !found {
+		c.fail(chain, path, "client not found")
+		return nil
+	}
+	c.checked(chain, path)
+	clientType, err := chain.IBCHost.GetClientType(opts, clientID)
+	if err != nil {
+		return err
+	}
+	switch clientType {
+	case ibcclient.BesuIBFT2Client:
+		var cs ibft2clienttypes.ClientState
+		if err := convert.UnmarshalWithAny(bz, &cs); err != nil {
+			return err
+		}
+		if cs.ChainId != counterparty.ChainIDString() {
+			c.fail(chain, path, "chain ID is %v, but the counterparty chain has %v", cs.ChainId, counterparty.ChainIDString())
+		}
+		if addr := common.BytesToAddress(cs.IbcStoreAddress); addr != counterparty.IBCHostAddress() {
+			c.fail(chain, path, "IBC store address is %v, but the counterparty chain has %v", addr.Hex(), counterparty.IBCHostAddress().Hex())
+		}
+	case ibcclient.MockClient:
+		c.note(chain, path, "the mock client does not hold the chain it tracks, so its chain ID and IBC store address are not checked")
+	default:
+		c.fail(chain, path, "unknown client type: '%v'", clientType)
+	}
+	return nil
+}
+
+func (c *checker) checkChannel(ctx context.Context, portID, channelID string) error {
+	chain, counterparty := c.chain, c.counterparty
+	ch, found, err := chain.IBCHost.GetChannel(chain.CallOpts(ctx), portID, channelID)
+	if err != nil {
+		return err
+	} else if !found {
+		return fmt.Errorf("channel not found: portID=%v channelID=%v", portID, channelID)
+	}
+	path := channelPath(portID, channelID)
+	c.checked(chain, path)
+	if len(ch.ConnectionHops) != 1 {
+		c.fail(chain, path, "channel must have exactly one connection hop: %v", ch.ConnectionHops)
+		return nil
+	}
+	if err := c.checkConnection(ctx, chain, counterparty, ch.ConnectionHops[0]); err != nil {
+		return err
+	}
+
+	state := channeltypes.Channel_State(ch.State)
+	if ch.Counterparty.ChannelId == "" {
+		if state != channeltypes.INIT {
+			c.fail(chain, path, "counterparty channel ID is empty in %v", state)
+		}
+		return nil
+	}
+
+	cpCh, found, err := counterparty.IBCHost.GetChannel(counterparty.CallOpts(ctx), ch.Counterparty.PortId, ch.Counterparty.ChannelId)
+	if err != nil {
+		return err
+	}
+	cpPath := channelPath(ch.Counterparty.PortId, ch.Counterparty.ChannelId)
+	if !found {
+		c.fail(counterparty, cpPath, "counterparty channel of %v not found", path)
+		return nil
+	}
+	c.checked(counterparty, cpPath)
+	if cpCh.Counterparty.PortId != portID || (cpCh.Counterparty.ChannelId != "" && cpCh.Counterparty.ChannelId != channelID) {
+		c.fail(counterparty, cpPath, "counterparty is %v/%v, but expected %v/%v", cpCh.Counterparty.PortId, cpCh.Counterparty.ChannelId, portID, channelID)
+	}
+	if cpCh.Ordering != ch.Ordering {
+		c.fail(counterparty, cpPath, "ordering is %v, but the counterparty channel is %v", channeltypes.Channel_Order(cpCh.Ordering), channeltypes.Channel_Order(ch.Ordering))
+	}
+	cpState := channeltypes.Channel_State(cpCh.State)
+	if !isValidChannelStatePair(state, cpState) {
+		c.fail(chain, path, "state is %v, but the counterparty channel is %v", state, cpState)
+	}
+	if state == channeltypes.OPEN && cpState == channeltypes.OPEN && ch.Version != cpCh.Version {
+		c.fail(counterparty, cpPath, "version is %v, but the counterparty channel has %v", cpCh.Version, ch.Version)
+	}
+	if err := c.checkConnectionHop(ctx, ch, cpCh, cpPath); err != nil {
+		return err
+	}
+	if channeltypes.Channel_Order(ch.Ordering) == channeltypes.ORDERED {
+		if err := c.checkSequences(ctx, chain, counterparty, portID, channelID, ch.Counterparty.PortId, ch.Counterparty.ChannelId); err != nil {
+			return err
+		}
+		if err := c.checkSequences(ctx, counterparty, chain, ch.Counterparty.PortId, ch.Counterparty.ChannelId, portID, channelID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkConnectionHop checks that the connection hop of the counterparty channel is the counterparty
+// connection of the connection hop of the channel.
+func (c *checker) checkConnectionHop(ctx context.Context, ch, cpCh ibchost.ChannelData, cpPath string) error {
+	conn, _, err := c.chain.IBCHost.GetConnection(c.chain.CallOpts(ctx), ch.ConnectionHops[0])
+	if err != nil {
+		return err
+	}
+	if len(cpCh.ConnectionHops) != 1 || cpCh.ConnectionHops[0] != conn.Counterparty.ConnectionId {
+		c.fail(c.counterparty, cpPath, "connection hops are %v, but expected [%v]", cpCh.ConnectionHops, conn.Counterparty.ConnectionId)
+	}
+	return nil
+}
+
+// checkSequences checks that the packets received on the destination chain have been sent on the source chain.
+func (c *checker) checkSequences(ctx context.Context, src, dst *host.Chain, srcPortID, srcChannelID, dstPortID, dstChannelID string) error {
+	nextSequenceSend, err := src.IBCHost.GetNextSequenceSend(src.CallOpts(ctx), srcPortID, srcChannelID)
+	if err != nil {
+		return err
+	}
+	nextSequenceRecv, err := dst.IBCHost.GetNextSequenceRecv(dst.CallOpts(ctx), dstPortID, dstChannelID)
+	if err != nil {
+		return err
+	}
+	if nextSequenceSend < nextSequenceRecv {
+		c.fail(dst, fmt.Sprintf("nextSequenceRecv/ports/%v/channels/%v", dstPortID, dstChannelID),
+			"next sequence to receive is %v, but the next sequence to send on the counterparty channel is %v", nextSequenceRecv, nextSequenceSend)
+	}
+	return nil
+}
+
+// isValidConnectionStatePair returns true if the connection states of both ends are reachable by a connection handshake.
+func isValidConnectionStatePair(state, cpState connectiontypes.ConnectionEnd_State) bool {
+	switch state {
+	case connectiontypes.ConnectionEnd_STATE_INIT:
+		return cpState == connectiontypes.ConnectionEnd_STATE_TRYOPEN
+	case connectiontypes.ConnectionEnd_STATE_TRYOPEN:
+		return cpState == connectiontypes.ConnectionEnd_STATE_INIT || cpState == connectiontypes.ConnectionEnd_STATE_OPEN
+	case connectiontypes.ConnectionEnd_STATE_OPEN:
+		return cpState == connectiontypes.ConnectionEnd_STATE_TRYOPEN || cpState == connectiontypes.ConnectionEnd_STATE_OPEN
+	default:
+		return false
+	}
+}
+
+// isValidChannelStatePair returns true if the channel states of both ends are reachable by a channel handshake
+// and closing handshake. Either end may be closed alone as an ordered channel is closed on timeout.
+func isValidChannelStatePair(state, cpState channeltypes.Channel_State) bool {
+	switch state {
+	case channeltypes.INIT:
+		return cpState == channeltypes.TRYOPEN
+	case channeltypes.TRYOPEN:
+		return cpState == channeltypes.INIT || cpState == channeltypes.OPEN
+	case channeltypes.OPEN:
+		return cpState == channeltypes.TRYOPEN || cpState == channeltypes.OPEN || cpState == channeltypes.CLOSED
+	case channeltypes.CLOSED:
+		return cpState == channeltypes.OPEN || cpState == channeltypes.CLOSED
+	default:
+		return false
+	}
+}
@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchost"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	connectiontypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/connection"
+)
+
+func TestIsValidConnectionStatePair(t *testing.T) {
+	const (
+		init    = connectiontypes.ConnectionEnd_STATE_INIT
+		tryopen = connectiontypes.ConnectionEnd_STATE_TRYOPEN
+		open    = connectiontypes.ConnectionEnd_STATE_OPEN
+	)
+	var cases = []struct {
+		state, cpState connectiontypes.ConnectionEnd_State
+		expected       bool
+	}{
+		{init, tryopen, true},
+		{tryopen, init, true},
+		{tryopen, open, true},
+		{open, tryopen, true},
+		{open, open, true},
+		{init, init, false},
+		{init, open, false},
+		{open, init, false},
+		{tryopen, tryopen, false},
+		{connectiontypes.ConnectionEnd_STATE_UNINITIALIZED_UNSPECIFIED, open, false},
+	}
+	for i, c := range cases {
+		require.Equal(t, c.expected, isValidConnectionStatePair(c.state, c.cpState), "case %v", i)
+	}
+}
+
+func TestIsValidChannelStatePair(t *testing.T) {
+	var cases = []struct {
+		state, cpState channeltypes.Channel_State
+		expected       bool
+	}{
+		{channeltypes.INIT, channeltypes.TRYOPEN, true},
+		{channeltypes.TRYOPEN, channeltypes.OPEN, true},
+		{channeltypes.OPEN, channeltypes.OPEN, true},
+		{channeltypes.OPEN, channeltypes.CLOSED, true},
+		{channeltypes.CLOSED, channeltypes.OPEN, true},
+		{channeltypes.CLOSED, channeltypes.CLOSED, true},
+		{channeltypes.INIT, channeltypes.OPEN, false},
+		{channeltypes.CLOSED, channeltypes.TRYOPEN, false},
+		{channeltypes.TRYOPEN, channeltypes.CLOSED, false},
+	}
+	for i, c := range cases {
+		require.Equal(t, c.expected, isValidChannelStatePair(c.state, c.cpState), "case %v", i)
+	}
+}
+
+func TestConsistencyReportString(t *testing.T) {
+	report := ConsistencyReport{
+		ChainID:             "ibc0",
+		CounterpartyChainID: "ibc1",
+		Checked:             []string{"connections/connection-0"},
+		Unpaired:            []Inconsistency{{ChainID: "ibc0", Path: "connections/connection-1", Message: "no counterparty connection has been created in STATE_INIT"}},
+		Notes:               []string{"chain=ibc0 clients/mock-client-0/clientState: not checked"},
+	}
+	require.True(t, report.OK())
+	require.Equal(t, `chain=ibc0 counterparty=ibc1 checked=[connections/connection-0] inconsistencies=0
+unpaired=1
+  chain=ibc0 connections/connection-1: no counterparty connection has been created in STATE_INIT
+note: chain=ibc0 clients/mock-client-0/clientState: not checked`, report.String())
+}
+
+func TestIsOnConnections(t *testing.T) {
+	paired := map[string]bool{"connection-0": true}
+	require.True(t, isOnConnections(ibchost.ChannelData{ConnectionHops: []string{"connection-0"}}, paired))
+	require.False(t, isOnConnections(ibchost.ChannelData{ConnectionHops: []string{"connection-1"}}, paired))
+	// a channel with more than one hop is checked to be reported
+	require.True(t, isOnConnections(ibchost.ChannelData{ConnectionHops: []string{"connection-1", "connection-2"}}, paired))
+}
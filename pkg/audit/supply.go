@@ -0,0 +1,219 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app/denom"
+)
+
+// SupplyReport is the result of checking that the tokens escrowed on a chain for a channel back the vouchers
+// of the tokens on its counterparty chain.
+type SupplyReport struct {
+	// Denom is the denomination of the tokens native to the chain.
+	Denom string
+	// VoucherDenom is the denomination of the vouchers of the tokens on the counterparty chain.
+	VoucherDenom string
+	// Escrowed is the amount of the tokens escrowed for the channel, which is derived from the packets
+	// sent and returned through the channel.
+	Escrowed *big.Int
+	// EscrowBalance is the amount of the tokens held by the escrow of ICS20TransferBank on the chain,
+	// which is shared by all the channels.
+	EscrowBalance *big.Int
+	// Vouchers is the amount of the vouchers held by the holders on the counterparty chain.
+	Vouchers *big.Int
+	// Holders are the accounts whose vouchers are counted.
+	Holders []common.Address
+	// InFlightSent is the amount of the tokens escrowed for the packets whose vouchers have not been minted yet.
+	InFlightSent *big.Int
+	// InFlightReturned is the amount of the vouchers burned for the packets whose tokens have not been released yet.
+	InFlightReturned *big.Int
+}
+
+// Discrepancy returns the amount of the tokens escrowed for the channel not backing any voucher.
+// A negative discrepancy means that more vouchers are outstanding than the tokens escrowed.
+func (r *SupplyReport) Discrepancy() *big.Int {
+	backed := new(big.Int).Add(r.Vouchers, r.InFlightSent)
+	backed.Add(backed, r.InFlightReturned)
+	return backed.Sub(r.Escrowed, backed)
+}
+
+// OK returns true if the tokens escrowed for the channel equal the outstanding vouchers,
+// and the escrow holds at least those tokens.
+func (r *SupplyReport) OK() bool {
+	return r.Discrepancy().Sign() == 0 && r.EscrowBalance.Cmp(r.Escrowed) >= 0
+}
+
+func (r *SupplyReport) String() string {
+	return fmt.Sprintf("denom=%v voucher=%v escrowed=%v escrow-balance=%v vouchers=%v holders=%v in-flight-sent=%v in-flight-returned=%v discrepancy=%v",
+		r.Denom, r.VoucherDenom, r.Escrowed, r.EscrowBalance, r.Vouchers, len(r.Holders), r.InFlightSent, r.InFlightReturned, r.Discrepancy())
+}
+
+// CheckSupply checks that the tokens of the denomination escrowed in ICS20TransferBank on the chain for
+// the channel equal the vouchers outstanding on the counterparty chain, adjusted for the packets in flight.
+//
+// All the channels of ICS20TransferBank share the same escrow, so the tokens escrowed for the channel are
+// derived from the packets: the tokens of a packet sent through the channel are escrowed unless they are
+// refunded on an error acknowledgement or a timeout, and the tokens of a packet returned through it are
+// released once it is acknowledged successfully. The balance of the escrow is only checked to cover them.
+//
+// ICS20Bank emits no events, so the holders of the vouchers are enumerated from the transfer packets:
+// the receivers of the packets sent through the channel and the senders of the packets returned through it.
+// Vouchers moved to other accounts with ICS20Bank.transferFrom must be counted by passing those accounts
+// as extra holders.
+func CheckSupply(
+	ctx context.Context,
+	chain, counterparty *host.Chain,
+	portID, channelID string,
+	baseDenom string,
+	extraHolders ...common.Address,
+) (*SupplyReport, error) {
+	ch, found, err := chain.IBCHost.GetChannel(chain.CallOpts(ctx), portID, channelID)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, fmt.Errorf("channel not found: portID=%v channelID=%v", portID, channelID)
+	}
+	cpPortID, cpChannelID := ch.Counterparty.PortId, ch.Counterparty.ChannelId
+	if denom.IsReturning(portID, channelID, baseDenom) {
+		return nil, fmt.Errorf("denomination is a voucher of the counterparty chain: %v", baseDenom)
+	}
+	report := &SupplyReport{
+		Denom:            baseDenom,
+		VoucherDenom:     denom.ReceivedDenom(portID, channelID, cpPortID, cpChannelID, baseDenom),
+		Escrowed:         new(big.Int),
+		Vouchers:         new(big.Int),
+		InFlightSent:     new(big.Int),
+		InFlightReturned: new(big.Int),
+	}
+
+	report.EscrowBalance, err = chain.ICS20Bank.BalanceOf(chain.CallOpts(ctx), chain.ContractConfig.GetICS20TransferBankAddress(), baseDenom)
+	if err != nil {
+		return nil, err
+	}
+
+	holders := make(map[common.Address]bool)
+	addHolder := func(addr common.Address) {
+		if !holders[addr] {
+			holders[addr] = true
+			report.Holders = append(report.Holders, addr)
+		}
+	}
+	for _, addr := range extraHolders {
+		addHolder(addr)
+	}
+
+	// the tokens sent from the chain are escrowed until the packets are acknowledged with an error or timed out
+	sent, err := transferPackets(ctx, chain, counterparty, portID, channelID, baseDenom)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range sent {
+		addHolder(common.BytesToAddress(p.data.Receiver))
+		amount := new(big.Int).SetUint64(p.data.Amount)
+		if p.committed || p.succeeded {
+			report.Escrowed.Add(report.Escrowed, amount)
+		}
+		if p.inFlight() {
+			report.InFlightSent.Add(report.InFlightSent, amount)
+		}
+	}
+	// the vouchers returned from the counterparty chain are burned before the escrowed tokens are released
+	returned, err := transferPackets(ctx, counterparty, chain, cpPortID, cpChannelID, report.VoucherDenom)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range returned {
+		addHolder(common.BytesToAddress(p.data.Sender))
+		amount := new(big.Int).SetUint64(p.data.Amount)
+		if p.succeeded {
+			report.Escrowed.Sub(report.Escrowed, amount)
+		}
+		if p.inFlight() {
+			report.InFlightReturned.Add(report.InFlightReturned, amount)
+		}
+	}
+
+	for _, holder := range report.Holders {
+		balance, err := counterparty.ICS20Bank.BalanceOf(counterparty.CallOpts(ctx), holder, report.VoucherDenom)
+		if err != nil {
+			return nil, err
+		}
+		report.Vouchers.Add(report.Vouchers, balance)
+	}
+	return report, nil
+}
+
+// transferPacket is a transfer packet sent from the source chain.
+type transferPacket struct {
+	data *app.FungibleTokenPacketData
+	// committed is true if the commitment of the packet remains on the source chain, which is deleted
+	// once the packet is acknowledged or timed out.
+	committed bool
+	// succeeded is true if the destination chain has acknowledged the packet successfully.
+	succeeded bool
+}
+
+// inFlight returns true if the transfer is neither completed on the destination chain nor reverted on the source chain.
+func (p transferPacket) inFlight() bool {
+	return p.committed && !p.succeeded
+}
+
+// transferPackets returns the transfer packets of the denomination sent through the channel of the source chain.
+func transferPackets(
+	ctx context.Context,
+	src, dst *host.Chain,
+	srcPortID, srcChannelID string,
+	sendDenom string,
+) ([]transferPacket, error) {
+	sent, err := src.SentPackets(ctx, &bind.FilterOpts{Start: 0, Context: ctx})
+	if err != nil {
+		return nil, err
+	}
+
+	var packets []transferPacket
+	for _, packet := range sent {
+		if packet.SourcePort != srcPortID || packet.SourceChannel != srcChannelID {
+			continue
+		}
+		data, err := app.DecodeFungibleTokenPacketData(packet.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the packet data: sequence=%v: %w", packet.Sequence, err)
+		} else if data.Denom != sendDenom {
+			continue
+		}
+		_, committed, err := src.IBCHost.GetPacketCommitment(src.CallOpts(ctx), srcPortID, srcChannelID, packet.Sequence)
+		if err != nil {
+			return nil, err
+		}
+		succeeded, err := acknowledgedSuccessfully(ctx, dst, packet.DestinationPort, packet.DestinationChannel, packet.Sequence)
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, transferPacket{data: data, committed: committed, succeeded: succeeded})
+	}
+	return packets, nil
+}
+
+// acknowledgedSuccessfully returns true if the packet has been received on the chain with a successful acknowledgement.
+func acknowledgedSuccessfully(ctx context.Context, chain *host.Chain, portID, channelID string, sequence uint64) (bool, error) {
+	_, found, err := chain.IBCHost.GetPacketAcknowledgementCommitment(chain.CallOpts(ctx), portID, channelID, sequence)
+	if err != nil || !found {
+		return false, err
+	}
+	bz, err := chain.FindAcknowledgement(ctx, portID, channelID, sequence)
+	if err != nil {
+		return false, err
+	}
+	ack, err := app.DecodeAcknowledgement(bz)
+	if err != nil {
+		return false, err
+	}
+	return ack.Success(), nil
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupplyReportDiscrepancy(t *testing.T) {
+	var cases = []struct {
+		escrowed, vouchers, inFlightSent, inFlightReturned int64
+		discrepancy                                        int64
+	}{
+		{0, 0, 0, 0, 0},
+		{100, 100, 0, 0, 0},
+		{100, 60, 30, 10, 0},
+		{100, 90, 0, 0, 10},
+		{100, 100, 10, 0, -10},
+	}
+	for i, c := range cases {
+		report := &SupplyReport{
+			Escrowed:         big.NewInt(c.escrowed),
+			EscrowBalance:    big.NewInt(c.escrowed),
+			Vouchers:         big.NewInt(c.vouchers),
+			InFlightSent:     big.NewInt(c.inFlightSent),
+			InFlightReturned: big.NewInt(c.inFlightReturned),
+		}
+		require.Equal(t, c.discrepancy, report.Discrepancy().Int64(), "case %v", i)
+		require.Equal(t, c.discrepancy == 0, report.OK(), "case %v", i)
+		// the report must not be modified
+		require.Equal(t, c.vouchers, report.Vouchers.Int64(), "case %v", i)
+	}
+}
+
+func TestSupplyReportOK(t *testing.T) {
+	var cases = []struct {
+		escrowed, escrowBalance, vouchers int64
+		ok                                bool
+	}{
+		{100, 100, 100, true},
+		// the escrow is shared with the tokens escrowed for the other channels
+		{100, 250, 100, true},
+		{100, 250, 90, false},
+		// the escrow lacks the tokens escrowed for the channel
+		{100, 90, 100, false},
+	}
+	for i, c := range cases {
+		report := &SupplyReport{
+			Escrowed:         big.NewInt(c.escrowed),
+			EscrowBalance:    big.NewInt(c.escrowBalance),
+			Vouchers:         big.NewInt(c.vouchers),
+			InFlightSent:     new(big.Int),
+			InFlightReturned: new(big.Int),
+		}
+		require.Equal(t, c.ok, report.OK(), "case %v", i)
+	}
+}
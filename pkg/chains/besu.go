@@ -21,6 +21,26 @@ type ParsedHeader struct {
 }
 
 func ParseHeader(header *gethtypes.Header) (*ParsedHeader, error) {
+	return parseHeader(header, true)
+}
+
+// ParseSealingHeader parses the header whose extra data excludes the committed seals, which is the header
+// submitted to the IBFT2 client, and attaches the given seals to it. Empty seals are dropped since
+// the seals submitted to the client are aligned with the validators and empty for the missing ones.
+func ParseSealingHeader(header *gethtypes.Header, seals [][]byte) (*ParsedHeader, error) {
+	parsed, err := parseHeader(header, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, seal := range seals {
+		if len(seal) > 0 {
+			parsed.Seals = append(parsed.Seals, seal)
+		}
+	}
+	return parsed, nil
+}
+
+func parseHeader(header *gethtypes.Header, withSeals bool) (*ParsedHeader, error) {
 	parsed := ParsedHeader{Base: header}
 
 	r := bytes.NewReader(header.Extra)
@@ -40,8 +60,10 @@ func ParseHeader(header *gethtypes.Header) (*ParsedHeader, error) {
 	if err := stream.Decode(&parsed.Round); err != nil {
 		return nil, err
 	}
-	if err := stream.Decode(&parsed.Seals); err != nil {
-		return nil, err
+	if withSeals {
+		if err := stream.Decode(&parsed.Seals); err != nil {
+			return nil, err
+		}
 	}
 	if err := stream.ListEnd(); err != nil {
 		return nil, err
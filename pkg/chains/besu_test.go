@@ -0,0 +1,54 @@
+package chains
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+// sealedHeader returns a header sealed by the first `signers` keys of the validators.
+func sealedHeader(t *testing.T, keys []*ecdsa.PrivateKey, signers int) *gethtypes.Header {
+	header := &gethtypes.Header{Number: big.NewInt(100), Difficulty: big.NewInt(1), GasLimit: 1000, Time: 1}
+	sealed, err := IBFT2Consensus{Validators: keys, Signers: keys[:signers]}.Seal(header)
+	require.NoError(t, err)
+	return sealed
+}
+
+func TestParseSealingHeader(t *testing.T) {
+	keys, err := GenerateValidatorKeys(4)
+	require.NoError(t, err)
+
+	parsed, err := ParseHeader(sealedHeader(t, keys, 3))
+	require.NoError(t, err)
+	require.Len(t, parsed.Validators, 4)
+	require.Len(t, parsed.Seals, 3)
+	seals, err := parsed.ValidateAndGetCommitSeals()
+	require.NoError(t, err)
+	require.Len(t, seals, 4)
+	require.Nil(t, seals[3])
+
+	// parse the header submitted to the client, whose seals are aligned with the validators
+	bz, err := parsed.GetSealingHeaderBytes()
+	require.NoError(t, err)
+	var header gethtypes.Header
+	require.NoError(t, rlp.DecodeBytes(bz, &header))
+	submitted, err := ParseSealingHeader(&header, seals)
+	require.NoError(t, err)
+	require.Equal(t, parsed.Validators, submitted.Validators)
+	require.Equal(t, parsed.Seals, submitted.Seals)
+	submittedBytes, err := submitted.GetSealingHeaderBytes()
+	require.NoError(t, err)
+	require.Equal(t, bz, submittedBytes)
+	_, err = submitted.ValidateAndGetCommitSeals()
+	require.NoError(t, err)
+
+	// a header sealed by less than 2/3 of the validators is rejected
+	parsed, err = ParseHeader(sealedHeader(t, keys, 2))
+	require.NoError(t, err)
+	_, err = parsed.ValidateAndGetCommitSeals()
+	require.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package chains
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// VoteAdd is the type of the vote to add a validator, as encoded by Besu.
+	VoteAdd byte = 0xff
+	// VoteDrop is the type of the vote to drop a validator, as encoded by Besu.
+	VoteDrop byte = 0x00
+)
+
+// Vote is the vote for a validator proposed in a block.
+type Vote struct {
+	Recipient common.Address
+	Type      byte
+}
+
+// IBFT2Consensus is the result of the IBFT2 consensus on a block, which is sealed into the extra data of its
+// header with the keys of the validators. It simulates the validators of an IBFT2 chain.
+type IBFT2Consensus struct {
+	// Validators is the validator set of the block.
+	Validators []*ecdsa.PrivateKey
+	// Signers is the keys committing the block. All the validators commit it if nil, so that the missing seals
+	// are simulated by a subset of the validators. The keys out of the validators simulate unknown signers.
+	Signers []*ecdsa.PrivateKey
+	// Vote is the vote proposed in the block if it is not nil.
+	Vote *Vote
+	// Round is the round in which the block is committed.
+	Round uint32
+}
+
+// GenerateValidatorKeys generates the keys of n validators.
+func GenerateValidatorKeys(n int) ([]*ecdsa.PrivateKey, error) {
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ValidatorAddresses returns the addresses of the validators.
+func (c IBFT2Consensus) ValidatorAddresses() []common.Address {
+	var addrs []common.Address
+	for _, key := range c.Validators {
+		addrs = append(addrs, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	return addrs
+}
+
+// Seal returns a copy of the header whose extra data is replaced with the vanity, the validators, the vote,
+// the round and the commit seals of the consensus. The seals are signed over the header whose extra data
+// excludes them, as GetSealingHeaderBytes returns.
+func (c IBFT2Consensus) Seal(header *gethtypes.Header) (*gethtypes.Header, error) {
+	parsed := ParsedHeader{Base: header, Validators: c.ValidatorAddresses(), Vote: []interface{}{}}
+	if c.Vote != nil {
+		parsed.Vote = []interface{}{c.Vote.Recipient, []byte{c.Vote.Type}}
+	}
+	binary.BigEndian.PutUint32(parsed.Round[:], c.Round)
+	committed, err := parsed.GetSealingHeaderBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	signers := c.Signers
+	if signers == nil {
+		signers = c.Validators
+	}
+	seals := [][]byte{}
+	for _, key := range signers {
+		seal, err := crypto.Sign(crypto.Keccak256(committed), key)
+		if err != nil {
+			return nil, err
+		}
+		seals = append(seals, seal)
+	}
+
+	sealed := gethtypes.CopyHeader(header)
+	sealed.Extra, err = rlp.EncodeToBytes([]interface{}{
+		parsed.Vanity, parsed.Validators, parsed.Vote, parsed.Round, seals,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sealed, nil
+}
+
+// SealConflicting returns a header conflicting with the header at the same height, which is a copy of the header
+// modified by the function and sealed by the consensus. The validators committing both headers equivocate.
+// An error is returned if the function changes the height or leaves the sealed contents of the header unchanged.
+func (c IBFT2Consensus) SealConflicting(header *gethtypes.Header, modify func(header *gethtypes.Header)) (*gethtypes.Header, error) {
+	modified := gethtypes.CopyHeader(header)
+	modify(modified)
+	if modified.Number.Cmp(header.Number) != 0 {
+		return nil, fmt.Errorf("conflicting header must be at the same height: height=%v modified=%v", header.Number, modified.Number)
+	}
+	// the extra data is replaced on sealing, so the headers are compared without it
+	original := gethtypes.CopyHeader(header)
+	original.Extra, modified.Extra = nil, nil
+	if modified.Hash() == original.Hash() {
+		return nil, fmt.Errorf("conflicting header must differ from the header: height=%v", header.Number)
+	}
+	return c.Seal(modified)
+}
+
+// IBFT2Sealer seals the headers of a chain simulating IBFT2 by the consensus in effect at their heights.
+// The consensus can be scheduled to change at a height, which simulates the rotation of the validators and
+// the validators missing their seals from the height.
+type IBFT2Sealer struct {
+	mu sync.RWMutex
+	// schedule is the consensus in effect from each height, in the ascending order of the heights.
+	schedule []scheduledConsensus
+}
+
+type scheduledConsensus struct {
+	height    uint64
+	consensus IBFT2Consensus
+}
+
+// NewIBFT2Sealer creates a new sealer sealing the headers by the consensus from the genesis.
+func NewIBFT2Sealer(consensus IBFT2Consensus) *IBFT2Sealer {
+	return &IBFT2Sealer{schedule: []scheduledConsensus{{height: 0, consensus: consensus}}}
+}
+
+// Schedule makes the consensus in effect from the height until the height of the next one scheduled.
+func (s *IBFT2Sealer) Schedule(height uint64, consensus IBFT2Consensus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := sort.Search(len(s.schedule), func(i int) bool { return s.schedule[i].height >= height })
+	if i < len(s.schedule) && s.schedule[i].height == height {
+		s.schedule[i].consensus = consensus
+		return
+	}
+	s.schedule = append(s.schedule, scheduledConsensus{})
+	copy(s.schedule[i+1:], s.schedule[i:])
+	s.schedule[i] = scheduledConsensus{height: height, consensus: consensus}
+}
+
+// Consensus returns the consensus in effect at the height.
+func (s *IBFT2Sealer) Consensus(height uint64) IBFT2Consensus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := sort.Search(len(s.schedule), func(i int) bool { return s.schedule[i].height > height })
+	return s.schedule[i-1].consensus
+}
+
+// SealHeader seals the header by the consensus in effect at its height.
+func (s *IBFT2Sealer) SealHeader(header *gethtypes.Header) (*gethtypes.Header, error) {
+	return s.Consensus(header.Number.Uint64()).Seal(header)
+}
+
+// SealConflicting returns a header conflicting with the header at the same height, which is sealed by the
+// consensus in effect at the height. See IBFT2Consensus.SealConflicting.
+func (s *IBFT2Sealer) SealConflicting(header *gethtypes.Header, modify func(header *gethtypes.Header)) (*gethtypes.Header, error) {
+	return s.Consensus(header.Number.Uint64()).SealConflicting(header, modify)
+}
@@ -0,0 +1,115 @@
+package chains
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIBFT2Sealer(t *testing.T) {
+	keys, err := GenerateValidatorKeys(7)
+	require.NoError(t, err)
+	initial := IBFT2Consensus{Validators: keys[:4]}
+	sealer := NewIBFT2Sealer(initial)
+	// the validators are rotated at 10, and one of them misses its seal at 20
+	rotated := IBFT2Consensus{Validators: keys[3:7], Vote: &Vote{Recipient: crypto.PubkeyToAddress(keys[0].PublicKey), Type: VoteDrop}, Round: 2}
+	sealer.Schedule(20, IBFT2Consensus{Validators: keys[3:7], Signers: keys[3:6]})
+	sealer.Schedule(10, rotated)
+
+	seal := func(height int64) *ParsedHeader {
+		header, err := sealer.SealHeader(&gethtypes.Header{Number: big.NewInt(height), Difficulty: big.NewInt(1), Time: uint64(height)})
+		require.NoError(t, err)
+		parsed, err := ParseHeader(header)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	parsed := seal(9)
+	require.Equal(t, initial.ValidatorAddresses(), parsed.Validators)
+	require.Equal(t, []interface{}{}, parsed.Vote)
+	seals, err := parsed.ValidateAndGetCommitSeals()
+	require.NoError(t, err)
+	require.Len(t, seals, 4)
+
+	parsed = seal(10)
+	require.Equal(t, rotated.ValidatorAddresses(), parsed.Validators)
+	require.Equal(t, [4]byte{0, 0, 0, 2}, parsed.Round)
+	bz, err := rlp.EncodeToBytes(parsed.Vote)
+	require.NoError(t, err)
+	var vote struct {
+		Recipient [20]byte
+		Type      []byte
+	}
+	require.NoError(t, rlp.DecodeBytes(bz, &vote))
+	require.Equal(t, crypto.PubkeyToAddress(keys[0].PublicKey).Bytes(), vote.Recipient[:])
+	require.Equal(t, []byte{VoteDrop}, vote.Type)
+	_, err = parsed.ValidateAndGetCommitSeals()
+	require.NoError(t, err)
+
+	// 3 of 4 validators seal the header
+	parsed = seal(25)
+	seals, err = parsed.ValidateAndGetCommitSeals()
+	require.NoError(t, err)
+	require.Nil(t, seals[3])
+
+	// the header sealed by 2 of 4 validators is rejected
+	sealer.Schedule(30, IBFT2Consensus{Validators: keys[3:7], Signers: keys[3:5]})
+	_, err = seal(30).ValidateAndGetCommitSeals()
+	require.Error(t, err)
+
+	// the seals of the unknown signers are not counted
+	sealer.Schedule(40, IBFT2Consensus{Validators: keys[3:7], Signers: []*ecdsa.PrivateKey{keys[3], keys[4], keys[0], keys[1]}})
+	_, err = seal(40).ValidateAndGetCommitSeals()
+	require.Error(t, err)
+}
+
+func TestIBFT2Equivocation(t *testing.T) {
+	keys, err := GenerateValidatorKeys(4)
+	require.NoError(t, err)
+	sealer := NewIBFT2Sealer(IBFT2Consensus{Validators: keys})
+	header, err := sealer.SealHeader(&gethtypes.Header{Number: big.NewInt(100), Difficulty: big.NewInt(1), Time: 1})
+	require.NoError(t, err)
+
+	// the validators commit another block at the same height, whose timestamp differs
+	conflicting, err := sealer.SealConflicting(header, func(header *gethtypes.Header) { header.Time++ })
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), header.Time)
+	require.Equal(t, uint64(2), conflicting.Time)
+	require.Equal(t, header.Number, conflicting.Number)
+
+	signers := func(header *gethtypes.Header) (common.Hash, []common.Address) {
+		parsed, err := ParseHeader(header)
+		require.NoError(t, err)
+		_, err = parsed.ValidateAndGetCommitSeals()
+		require.NoError(t, err)
+		bz, err := parsed.GetSealingHeaderBytes()
+		require.NoError(t, err)
+		recovered, err := RecoverCommitterAddressesVals(crypto.Keccak256(bz), parsed.Seals)
+		require.NoError(t, err)
+		var addrs []common.Address
+		for _, addr := range parsed.Validators {
+			if _, ok := recovered[addr]; ok {
+				addrs = append(addrs, addr)
+			}
+		}
+		return crypto.Keccak256Hash(bz), addrs
+	}
+	// both headers are validly sealed by all the validators
+	hash, committers := signers(header)
+	conflictingHash, conflictingCommitters := signers(conflicting)
+	require.NotEqual(t, hash, conflictingHash)
+	require.Equal(t, sealer.Consensus(100).ValidatorAddresses(), committers)
+	require.Equal(t, committers, conflictingCommitters)
+
+	// the header is neither moved to another height nor left unchanged
+	_, err = sealer.SealConflicting(header, func(header *gethtypes.Header) { header.Number = big.NewInt(101) })
+	require.Error(t, err)
+	_, err = sealer.SealConflicting(header, func(header *gethtypes.Header) { header.Extra = []byte("extra") })
+	require.Error(t, err)
+}
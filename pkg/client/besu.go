@@ -15,8 +15,8 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-func NewBesuClient(endpoint string, clientType string) (*Client, error) {
-	conn, err := rpc.DialHTTP(endpoint)
+func NewBesuClient(endpoint string, clientType string, opts ...Option) (*Client, error) {
+	conn, err := rpc.DialHTTPWithClient(endpoint, newOptions(opts).httpClient())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/chains"
+)
+
+// DefaultCacheSize is the default number of the entries kept by the cache of a client.
+const DefaultCacheSize = 1024
+
+// WithCache returns the client that keeps the blocks, the parsed IBFT2 headers and the proofs of each storage slot
+// at the finalized heights in an LRU cache of the given number of entries, which are used by GetContractState
+// and GetETHProof. A height is finalized if it is deeper than finalityDepth from the latest head the client has
+// fetched, which can be 0 for IBFT2 since its blocks are final once committed. Nothing is cached until the
+// client fetches the latest head, and the data at the unfinalized heights is never cached.
+//
+// The cached values are shared between the callers, so they must not be modified.
+func (cl Client) WithCache(size int, finalityDepth uint64) Client {
+	cl.cache = newCache(size, finalityDepth)
+	return cl
+}
+
+type blockKey struct {
+	number uint64
+}
+
+type parsedHeaderKey struct {
+	number uint64
+}
+
+type accountProofKey struct {
+	address common.Address
+	number  uint64
+}
+
+type storageProofKey struct {
+	address common.Address
+	number  uint64
+	slot    string
+}
+
+type parsedHeader struct {
+	header      *chains.ParsedHeader
+	commitSeals [][]byte
+}
+
+// cache is an LRU cache of the data at the finalized heights.
+type cache struct {
+	mu            sync.Mutex
+	size          int
+	finalityDepth uint64
+	head          uint64
+	entries       *list.List
+	elements      map[interface{}]*list.Element
+}
+
+type cacheEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+func newCache(size int, finalityDepth uint64) *cache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &cache{
+		size:          size,
+		finalityDepth: finalityDepth,
+		entries:       list.New(),
+		elements:      make(map[interface{}]*list.Element),
+	}
+}
+
+// observeHead updates the latest head fetched.
+func (c *cache) observeHead(number uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if number > c.head {
+		c.head = number
+	}
+}
+
+// finalized returns true if the block of the given number is finalized. The latest block is never finalized
+// since its number is not known before fetching it.
+func (c *cache) finalized(bn *big.Int) bool {
+	if bn == nil || !bn.IsUint64() {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head >= c.finalityDepth && bn.Uint64() <= c.head-c.finalityDepth
+}
+
+func (c *cache) get(key interface{}) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.elements[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).value, true
+}
+
+func (c *cache) add(key, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.elements[key]; ok {
+		elem.Value.(*cacheEntry).value = value
+		c.entries.MoveToFront(elem)
+		return
+	}
+	c.elements[key] = c.entries.PushFront(&cacheEntry{key: key, value: value})
+	for c.entries.Len() > c.size {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.elements, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *cache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// getProof returns the proof of the storage slots at the block number if all of them are cached.
+func (c *cache) getProof(address common.Address, storageKeys [][]byte, number uint64) (*ETHProof, bool) {
+	account, ok := c.get(accountProofKey{address, number})
+	if !ok {
+		return nil, false
+	}
+	proof := &ETHProof{AccountProofRLP: account.([]byte)}
+	for _, key := range storageKeys {
+		storage, ok := c.get(storageProofKey{address, number, string(key)})
+		if !ok {
+			return nil, false
+		}
+		proof.StorageProofRLP = append(proof.StorageProofRLP, storage.([]byte))
+	}
+	return proof, true
+}
+
+func (c *cache) addProof(address common.Address, storageKeys [][]byte, number uint64, proof *ETHProof) {
+	if len(proof.StorageProofRLP) != len(storageKeys) {
+		return
+	}
+	c.add(accountProofKey{address, number}, proof.AccountProofRLP)
+	for i, key := range storageKeys {
+		c.add(storageProofKey{address, number, string(key)}, proof.StorageProofRLP[i])
+	}
+}
+
+// blockByNumber returns the block of the given number, which is the latest block if it is nil,
+// using the cache if the client has one.
+func (cl Client) blockByNumber(ctx context.Context, bn *big.Int) (*gethtypes.Block, error) {
+	if cl.cache == nil {
+		return cl.BlockByNumber(ctx, bn)
+	}
+	if cl.cache.finalized(bn) {
+		if block, ok := cl.cache.get(blockKey{bn.Uint64()}); ok {
+			return block.(*gethtypes.Block), nil
+		}
+	}
+	block, err := cl.BlockByNumber(ctx, bn)
+	if err != nil {
+		return nil, err
+	}
+	if bn == nil {
+		cl.cache.observeHead(block.NumberU64())
+	}
+	if cl.cache.finalized(block.Number()) {
+		cl.cache.add(blockKey{block.NumberU64()}, block)
+	}
+	return block, nil
+}
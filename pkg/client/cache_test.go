@@ -0,0 +1,83 @@
+package client
+
+import (
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+)
+
+func TestCacheLRU(t *testing.T) {
+	c := newCache(2, 0)
+	c.add(blockKey{1}, 1)
+	c.add(blockKey{2}, 2)
+	_, ok := c.get(blockKey{1})
+	require.True(t, ok)
+	// the least recently used entry is evicted
+	c.add(blockKey{3}, 3)
+	require.Equal(t, 2, c.len())
+	_, ok = c.get(blockKey{2})
+	require.False(t, ok)
+	v, ok := c.get(blockKey{1})
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+}
+
+func TestCacheFinalized(t *testing.T) {
+	c := newCache(0, 3)
+	require.Equal(t, DefaultCacheSize, c.size)
+	// nothing is finalized before the head is known
+	require.False(t, c.finalized(big.NewInt(0)))
+	require.False(t, c.finalized(nil))
+
+	c.observeHead(10)
+	require.True(t, c.finalized(big.NewInt(7)))
+	require.False(t, c.finalized(big.NewInt(8)))
+	// the head never goes back
+	c.observeHead(5)
+	require.True(t, c.finalized(big.NewInt(7)))
+}
+
+func TestCachedETHProof(t *testing.T) {
+	n := newFakeNode(100, common.Hash{})
+	defer n.Close()
+	base, err := NewETHClient(n.URL, ibcclient.MockClient)
+	require.NoError(t, err)
+	cl := base.WithCache(16, 2)
+	cl.cache.observeHead(100)
+
+	address := common.Address{1}
+	slots := [][]byte{[]byte("0x0000000000000000000000000000000000000000000000000000000000000001")}
+	more := append(slots, []byte("0x0000000000000000000000000000000000000000000000000000000000000002"))
+
+	// the proofs at a finalized height are fetched once per slot
+	proof, err := cl.GetETHProof(address, slots, big.NewInt(98))
+	require.NoError(t, err)
+	require.Len(t, proof.StorageProofRLP, 1)
+	cached, err := cl.GetETHProof(address, slots, big.NewInt(98))
+	require.NoError(t, err)
+	require.Equal(t, proof, cached)
+	require.EqualValues(t, 1, atomic.LoadInt32(&n.calls))
+	_, err = cl.GetETHProof(address, more, big.NewInt(98))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&n.calls))
+	_, err = cl.GetETHProof(address, more[1:], big.NewInt(98))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&n.calls))
+
+	// the proofs at an unfinalized height are never cached
+	for i := 0; i < 2; i++ {
+		_, err = cl.GetETHProof(address, slots, big.NewInt(99))
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 4, atomic.LoadInt32(&n.calls))
+
+	// the client without the cache always fetches the proofs
+	_, err = base.GetETHProof(address, slots, big.NewInt(98))
+	require.NoError(t, err)
+	require.EqualValues(t, 5, atomic.LoadInt32(&n.calls))
+}
@@ -12,15 +12,22 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	gethcrypto "github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type Client struct {
 	endpoint   string
 	clientType string
 
-	conn *rpc.Client
+	conn rpcCaller
 	ETHClient
+
+	// cache is the cache of the data at the finalized heights if it is enabled by WithCache.
+	cache *cache
+}
+
+// rpcCaller calls the JSON-RPC methods of the nodes.
+type rpcCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
 }
 
 func (cl Client) ClientType() string {
@@ -40,6 +47,7 @@ func (cl Client) WaitForReceiptAndGet(ctx context.Context, tx *gethtypes.Transac
 		},
 		retry.Delay(1*time.Second),
 		retry.Attempts(10),
+		retry.Context(ctx),
 	)
 	if err != nil {
 		return nil, err
@@ -50,6 +58,7 @@ func (cl Client) WaitForReceiptAndGet(ctx context.Context, tx *gethtypes.Transac
 type ETHClient interface {
 	bind.ContractBackend
 	BlockByNumber(ctx context.Context, bn *big.Int) (*gethtypes.Block, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, bn *big.Int) ([]byte, error)
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (Receipt, error)
 }
 
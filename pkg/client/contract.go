@@ -27,8 +27,16 @@ func (cl Client) GetContractState(ctx context.Context, address common.Address, s
 	}
 }
 
-func (cl Client) GetMockContractState(ctx context.Context, address common.Address, storageKeys [][]byte, bn *big.Int) (ContractState, error) {
-	block, err := cl.BlockByNumber(ctx, bn)
+func (cl Client) GetMockContractState(ctx context.Context, address common.Address, storageKeys [][]byte, bn *big.Int) (state ContractState, err error) {
+	err = cl.pinned(ctx, bn, func(cl Client) error {
+		state, err = cl.getMockContractState(ctx, address, storageKeys, bn)
+		return err
+	})
+	return state, err
+}
+
+func (cl Client) getMockContractState(ctx context.Context, address common.Address, storageKeys [][]byte, bn *big.Int) (ContractState, error) {
+	block, err := cl.blockByNumber(ctx, bn)
 	if err != nil {
 		return nil, err
 	}
@@ -39,25 +47,45 @@ func (cl Client) GetMockContractState(ctx context.Context, address common.Addres
 	return ETHContractState{header: block.Header(), ethProof: proof}, nil
 }
 
-func (cl Client) GetIBFT2ContractState(ctx context.Context, address common.Address, storageKeys [][]byte, bn *big.Int) (ContractState, error) {
+// GetIBFT2ContractState returns the header at the given block number and the proofs of the storage at it.
+// They come from the same node even if the client routes the calls to several nodes.
+func (cl Client) GetIBFT2ContractState(ctx context.Context, address common.Address, storageKeys [][]byte, bn *big.Int) (state ContractState, err error) {
+	err = cl.pinned(ctx, bn, func(cl Client) error {
+		state, err = cl.getIBFT2ContractState(ctx, address, storageKeys, bn)
+		return err
+	})
+	return state, err
+}
+
+func (cl Client) getIBFT2ContractState(ctx context.Context, address common.Address, storageKeys [][]byte, bn *big.Int) (ContractState, error) {
 	var state IBFT2ContractState
-	block, err := cl.BlockByNumber(ctx, bn)
-	if err != nil {
-		return nil, err
+	if cl.cache != nil && cl.cache.finalized(bn) {
+		if parsed, ok := cl.cache.get(parsedHeaderKey{bn.Uint64()}); ok {
+			state.ParsedHeader, state.CommitSeals = parsed.(*parsedHeader).header, parsed.(*parsedHeader).commitSeals
+		}
 	}
-	proof, err := cl.GetETHProof(address, storageKeys, block.Number())
-	if err != nil {
-		return nil, err
-	}
-	state.ethProof = proof
-	state.ParsedHeader, err = chains.ParseHeader(block.Header())
-	if err != nil {
-		return nil, err
+	if state.ParsedHeader == nil {
+		block, err := cl.blockByNumber(ctx, bn)
+		if err != nil {
+			return nil, err
+		}
+		state.ParsedHeader, err = chains.ParseHeader(block.Header())
+		if err != nil {
+			return nil, err
+		}
+		state.CommitSeals, err = state.ParsedHeader.ValidateAndGetCommitSeals()
+		if err != nil {
+			return nil, err
+		}
+		if cl.cache != nil && cl.cache.finalized(block.Number()) {
+			cl.cache.add(parsedHeaderKey{block.NumberU64()}, &parsedHeader{header: state.ParsedHeader, commitSeals: state.CommitSeals})
+		}
 	}
-	state.CommitSeals, err = state.ParsedHeader.ValidateAndGetCommitSeals()
+	proof, err := cl.GetETHProof(address, storageKeys, state.ParsedHeader.Base.Number)
 	if err != nil {
 		return nil, err
 	}
+	state.ethProof = proof
 	return state, nil
 }
 
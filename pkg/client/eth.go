@@ -10,8 +10,8 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-func NewETHClient(endpoint string, clientType string) (*Client, error) {
-	conn, err := rpc.DialHTTP(endpoint)
+func NewETHClient(endpoint string, clientType string, opts ...Option) (*Client, error) {
+	conn, err := rpc.DialHTTPWithClient(endpoint, newOptions(opts).httpClient())
 	if err != nil {
 		return nil, err
 	}
@@ -23,6 +23,24 @@ func NewETHClient(endpoint string, clientType string) (*Client, error) {
 	}, nil
 }
 
+// Backend is an ETHClient that also serves the JSON-RPC methods called by Client besides ETHClient,
+// such as eth_getProof.
+type Backend interface {
+	ETHClient
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// NewBackendClient creates a new client of the chain served by the backend instead of the nodes,
+// e.g. a chain simulated in process.
+func NewBackendClient(endpoint string, clientType string, backend Backend) *Client {
+	return &Client{
+		endpoint:   endpoint,
+		clientType: clientType,
+		conn:       backend,
+		ETHClient:  backend,
+	}
+}
+
 type ethReceipt struct {
 	// Consensus fields: These fields are defined by the Yellow Paper
 	PostState_         []byte       `json:"root"`
@@ -101,6 +119,15 @@ func (cl ethClient) TransactionReceipt(ctx context.Context, txHash common.Hash)
 	if err != nil {
 		return nil, err
 	}
+	return newETHReceipt(rc), nil
+}
+
+// NewReceipt returns the receipt of the transaction decoded by go-ethereum.
+func NewReceipt(rc *types.Receipt) Receipt {
+	return newETHReceipt(rc)
+}
+
+func newETHReceipt(rc *types.Receipt) ethReceipt {
 	return ethReceipt{
 		PostState_:         rc.PostState,
 		Status_:            rc.Status,
@@ -113,5 +140,5 @@ func (cl ethClient) TransactionReceipt(ctx context.Context, txHash common.Hash)
 		BlockHash_:         rc.BlockHash,
 		BlockNumber_:       rc.BlockNumber,
 		TransactionIndex_:  rc.TransactionIndex,
-	}, nil
+	}
 }
@@ -0,0 +1,438 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/metrics"
+)
+
+const (
+	// DefaultMaxLag is the default number of blocks a node may fall behind the highest head to be in sync.
+	DefaultMaxLag uint64 = 2
+	// DefaultMaxErrorRate is the default error rate above which a node is unhealthy.
+	DefaultMaxErrorRate = 0.5
+	// DefaultHealthCheckInterval is the default interval to refresh the heads of the nodes.
+	DefaultHealthCheckInterval = 5 * time.Second
+	// DefaultHealthCheckTimeout is the default timeout to get the head of each node.
+	DefaultHealthCheckTimeout = 2 * time.Second
+
+	// errorRateWeight is the weight of the latest call in the moving average of the error rate.
+	errorRateWeight = 0.2
+)
+
+// ErrNoNode is returned when no node can serve a call.
+var ErrNoNode = errors.New("no node available")
+
+// Failover is an ETHClient that routes the calls to several nodes of a chain. Reads are routed to the healthy
+// nodes in sync with the highest head, and the calls failing due to the transport, e.g. a node going down,
+// fail over to the next node. Errors returned by a node for the call itself, e.g. a reverted call, are
+// returned as they are.
+//
+// The heads of the nodes are refreshed in the background at most every HealthCheckInterval, which is started
+// by the calls without waiting for it, and the error rate of each node is the moving average of its calls and
+// head checks.
+type Failover struct {
+	// MaxLag is the number of blocks a node may fall behind the highest head to serve reads.
+	MaxLag uint64
+	// MaxErrorRate is the error rate above which a node serves calls only if no other node can.
+	MaxErrorRate float64
+	// HealthCheckInterval is the interval to refresh the heads of the nodes.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout is the timeout to get the head of each node, so that a node not responding does not
+	// hold up the health check of the others. No timeout is applied if it is zero.
+	HealthCheckTimeout time.Duration
+
+	nodes []*node
+
+	mu          sync.Mutex
+	lastChecked time.Time
+	checking    bool
+}
+
+var _ ETHClient = (*Failover)(nil)
+
+type node struct {
+	endpoint string
+	conn     *rpc.Client
+	eth      ETHClient
+
+	mu        sync.Mutex
+	head      uint64
+	errorRate float64
+	lastErr   error
+}
+
+// NodeStatus is the status of a node tracked by Failover.
+type NodeStatus struct {
+	Endpoint  string
+	Head      uint64
+	ErrorRate float64
+	Healthy   bool
+	InSync    bool
+	LastError error
+}
+
+func (s NodeStatus) String() string {
+	return fmt.Sprintf("endpoint=%v head=%v error-rate=%.2f healthy=%v in-sync=%v last-error=%v", s.Endpoint, s.Head, s.ErrorRate, s.Healthy, s.InSync, s.LastError)
+}
+
+// NewFailoverBesuClient creates a new client of the Besu nodes of a chain. See Failover for the routing.
+func NewFailoverBesuClient(endpoints []string, clientType string) (*Client, error) {
+	return newFailoverClient(endpoints, clientType, func(conn *rpc.Client) ETHClient {
+		return besuClient{Client: ethclient.NewClient(conn), rpcClient: conn}
+	})
+}
+
+// NewFailoverETHClient creates a new client of the Ethereum nodes of a chain. See Failover for the routing.
+func NewFailoverETHClient(endpoints []string, clientType string) (*Client, error) {
+	return newFailoverClient(endpoints, clientType, func(conn *rpc.Client) ETHClient {
+		return ethClient{Client: ethclient.NewClient(conn)}
+	})
+}
+
+func newFailoverClient(endpoints []string, clientType string, newETHClient func(conn *rpc.Client) ETHClient) (*Client, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no endpoint given")
+	}
+	f := &Failover{
+		MaxLag:              DefaultMaxLag,
+		MaxErrorRate:        DefaultMaxErrorRate,
+		HealthCheckInterval: DefaultHealthCheckInterval,
+		HealthCheckTimeout:  DefaultHealthCheckTimeout,
+	}
+	for _, endpoint := range endpoints {
+		conn, err := rpc.DialHTTPWithClient(endpoint, metrics.NewHTTPClient())
+		if err != nil {
+			return nil, err
+		}
+		f.nodes = append(f.nodes, &node{endpoint: endpoint, conn: conn, eth: newETHClient(conn)})
+	}
+	return &Client{
+		endpoint:   strings.Join(endpoints, ","),
+		clientType: clientType,
+		conn:       f,
+		ETHClient:  f,
+	}, nil
+}
+
+// Failover returns the Failover routing the calls of the client if it is created with several endpoints.
+func (cl Client) Failover() (*Failover, bool) {
+	f, ok := cl.ETHClient.(*Failover)
+	return f, ok
+}
+
+// Statuses returns the status of each node.
+func (f *Failover) Statuses() []NodeStatus {
+	highest := f.highestHead()
+	var statuses []NodeStatus
+	for _, n := range f.nodes {
+		n.mu.Lock()
+		statuses = append(statuses, NodeStatus{
+			Endpoint:  n.endpoint,
+			Head:      n.head,
+			ErrorRate: n.errorRate,
+			Healthy:   n.errorRate <= f.MaxErrorRate,
+			InSync:    n.head+f.MaxLag >= highest,
+			LastError: n.lastErr,
+		})
+		n.mu.Unlock()
+	}
+	return statuses
+}
+
+// CheckHealth refreshes the heads of the nodes, waiting for each node at most HealthCheckTimeout.
+func (f *Failover) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, n := range f.nodes {
+		wg.Add(1)
+		go func(n *node) {
+			defer wg.Done()
+			ctx := ctx
+			if f.HealthCheckTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, f.HealthCheckTimeout)
+				defer cancel()
+			}
+			var head hexutil.Uint64
+			err := n.conn.CallContext(ctx, &head, "eth_blockNumber")
+			n.mu.Lock()
+			if err == nil {
+				n.head = uint64(head)
+			}
+			n.mu.Unlock()
+			n.observe(err)
+		}(n)
+	}
+	wg.Wait()
+	f.mu.Lock()
+	f.lastChecked = time.Now()
+	f.mu.Unlock()
+}
+
+// checkHealthIfStale starts refreshing the heads of the nodes in the background if they have not been
+// refreshed for HealthCheckInterval and no refresh is running. The health check is not bound to the context
+// of the call starting it, but is bounded by HealthCheckTimeout.
+func (f *Failover) checkHealthIfStale() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.checking || time.Since(f.lastChecked) < f.HealthCheckInterval {
+		return
+	}
+	f.checking = true
+	go func() {
+		f.CheckHealth(context.Background())
+		f.mu.Lock()
+		f.checking = false
+		f.mu.Unlock()
+	}()
+}
+
+// candidates returns the nodes to try in order for a call that needs the block of the given number,
+// which is the latest block if it is nil. The healthy nodes in sync come first in the order of their
+// error rates, then the other nodes having the block, and then the rest in the order of their heads.
+// The nodes are ranked by their last known heads, which are refreshed without waiting.
+func (f *Failover) candidates(bn *big.Int) []*node {
+	f.checkHealthIfStale()
+
+	highest := f.highestHead()
+	type ranked struct {
+		n         *node
+		rank      int
+		head      uint64
+		errorRate float64
+	}
+	var rs []ranked
+	for _, n := range f.nodes {
+		n.mu.Lock()
+		r := ranked{n: n, head: n.head, errorRate: n.errorRate}
+		n.mu.Unlock()
+		hasBlock := bn == nil || (bn.IsUint64() && r.head >= bn.Uint64())
+		switch {
+		case hasBlock && r.errorRate <= f.MaxErrorRate && r.head+f.MaxLag >= highest:
+			r.rank = 0
+		case hasBlock:
+			r.rank = 1
+		default:
+			r.rank = 2
+		}
+		rs = append(rs, r)
+	}
+	sort.SliceStable(rs, func(i, j int) bool {
+		if rs[i].rank != rs[j].rank {
+			return rs[i].rank < rs[j].rank
+		} else if rs[i].rank == 0 && rs[i].errorRate != rs[j].errorRate {
+			return rs[i].errorRate < rs[j].errorRate
+		}
+		return rs[i].head > rs[j].head
+	})
+	nodes := make([]*node, len(rs))
+	for i, r := range rs {
+		nodes[i] = r.n
+	}
+	return nodes
+}
+
+func (f *Failover) highestHead() uint64 {
+	var highest uint64
+	for _, n := range f.nodes {
+		n.mu.Lock()
+		if n.head > highest {
+			highest = n.head
+		}
+		n.mu.Unlock()
+	}
+	return highest
+}
+
+// do calls fn with the candidate nodes in order until it succeeds or fails for a reason other than the transport.
+func (f *Failover) do(ctx context.Context, bn *big.Int, fn func(n *node) error) error {
+	var errs []string
+	for _, n := range f.candidates(bn) {
+		err := fn(n)
+		if !isTransportError(err) {
+			n.observe(nil)
+			return err
+		}
+		n.observe(err)
+		errs = append(errs, fmt.Sprintf("%v: %v", n.endpoint, err))
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrNoNode, strings.Join(errs, "; "))
+}
+
+// pinned calls fn with the client bound to a single node that has the block of the given number, failing over
+// to the next node only if fn fails due to the transport. It is used to get the headers and the proofs at a height
+// from the same node. fn is called with the client itself if it is not routed by Failover.
+func (cl Client) pinned(ctx context.Context, bn *big.Int, fn func(cl Client) error) error {
+	f, ok := cl.Failover()
+	if !ok {
+		return fn(cl)
+	}
+	return f.do(ctx, bn, func(n *node) error {
+		return fn(Client{endpoint: n.endpoint, clientType: cl.clientType, conn: n.conn, ETHClient: n.eth, cache: cl.cache})
+	})
+}
+
+// observe updates the error rate of the node with the result of a call.
+func (n *node) observe(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	sample := 0.0
+	if err != nil {
+		sample = 1.0
+		n.lastErr = err
+	}
+	n.errorRate = n.errorRate*(1-errorRateWeight) + sample*errorRateWeight
+}
+
+// isTransportError returns true if the call failed without the node processing it.
+func isTransportError(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+func (f *Failover) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return f.do(ctx, nil, func(n *node) error {
+		return n.conn.CallContext(ctx, result, method, args...)
+	})
+}
+
+func (f *Failover) CodeAt(ctx context.Context, contract common.Address, bn *big.Int) (code []byte, err error) {
+	err = f.do(ctx, bn, func(n *node) (err error) {
+		code, err = n.eth.CodeAt(ctx, contract, bn)
+		return err
+	})
+	return code, err
+}
+
+func (f *Failover) CallContract(ctx context.Context, call ethereum.CallMsg, bn *big.Int) (res []byte, err error) {
+	err = f.do(ctx, bn, func(n *node) (err error) {
+		res, err = n.eth.CallContract(ctx, call, bn)
+		return err
+	})
+	return res, err
+}
+
+func (f *Failover) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = f.do(ctx, nil, func(n *node) (err error) {
+		code, err = n.eth.PendingCodeAt(ctx, account)
+		return err
+	})
+	return code, err
+}
+
+func (f *Failover) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = f.do(ctx, nil, func(n *node) (err error) {
+		nonce, err = n.eth.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+func (f *Failover) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = f.do(ctx, nil, func(n *node) (err error) {
+		price, err = n.eth.SuggestGasPrice(ctx)
+		return err
+	})
+	return price, err
+}
+
+func (f *Failover) EstimateGas(ctx context.Context, call ethereum.CallMsg) (gas uint64, err error) {
+	err = f.do(ctx, nil, func(n *node) (err error) {
+		gas, err = n.eth.EstimateGas(ctx, call)
+		return err
+	})
+	return gas, err
+}
+
+// SendTransaction sends the transaction to the first node accepting it. Once sending to a node has failed
+// due to the transport, the node may have received the transaction and propagated it, so the next node
+// rejecting the transaction as already sent is treated as accepting it.
+func (f *Failover) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	var resent bool
+	return f.do(ctx, nil, func(n *node) error {
+		err := n.eth.SendTransaction(ctx, tx)
+		if resent && n.alreadySent(ctx, tx, err) {
+			return nil
+		}
+		resent = resent || isTransportError(err)
+		return err
+	})
+}
+
+// alreadySent returns true if the node has rejected the transaction because it already has the transaction
+// of the same hash in its pool, or has included it in a block, which is why its nonce is too low.
+func (n *node) alreadySent(ctx context.Context, tx *gethtypes.Transaction, err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "already known"), strings.Contains(msg, "known transaction"):
+		return true
+	case strings.Contains(msg, "nonce too low"):
+		rc, err := n.eth.TransactionReceipt(ctx, tx.Hash())
+		return err == nil && rc != nil
+	default:
+		return false
+	}
+}
+
+func (f *Failover) FilterLogs(ctx context.Context, query ethereum.FilterQuery) (logs []gethtypes.Log, err error) {
+	err = f.do(ctx, query.ToBlock, func(n *node) (err error) {
+		logs, err = n.eth.FilterLogs(ctx, query)
+		return err
+	})
+	return logs, err
+}
+
+func (f *Failover) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- gethtypes.Log) (sub ethereum.Subscription, err error) {
+	err = f.do(ctx, nil, func(n *node) (err error) {
+		sub, err = n.eth.SubscribeFilterLogs(ctx, query, ch)
+		return err
+	})
+	return sub, err
+}
+
+func (f *Failover) BlockByNumber(ctx context.Context, bn *big.Int) (block *gethtypes.Block, err error) {
+	err = f.do(ctx, bn, func(n *node) (err error) {
+		block, err = n.eth.BlockByNumber(ctx, bn)
+		return err
+	})
+	return block, err
+}
+
+func (f *Failover) StorageAt(ctx context.Context, account common.Address, key common.Hash, bn *big.Int) (value []byte, err error) {
+	err = f.do(ctx, bn, func(n *node) (err error) {
+		value, err = n.eth.StorageAt(ctx, account, key, bn)
+		return err
+	})
+	return value, err
+}
+
+func (f *Failover) TransactionReceipt(ctx context.Context, txHash common.Hash) (receipt Receipt, err error) {
+	err = f.do(ctx, nil, func(n *node) (err error) {
+		receipt, err = n.eth.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return receipt, err
+}
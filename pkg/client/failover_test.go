@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+)
+
+// fakeNode is a JSON-RPC server serving the head and the proofs of a node.
+type fakeNode struct {
+	*httptest.Server
+	head        uint64
+	storageHash common.Hash
+	calls       int32
+
+	// blockHead makes the node respond to the head check only once it is closed or the check is canceled.
+	blockHead chan struct{}
+	// dropSend makes the node drop the connection once it receives a transaction.
+	dropSend bool
+	// sendError is the error returned for a transaction received by the node.
+	sendError string
+	// mined makes the node return a receipt for any transaction.
+	mined bool
+	sent  int32
+}
+
+func newFakeNode(head uint64, storageHash common.Hash) *fakeNode {
+	n := &fakeNode{head: head, storageHash: storageHash}
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&n.calls, 1)
+		var msg struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var result interface{}
+		switch msg.Method {
+		case "eth_blockNumber":
+			if n.blockHead != nil {
+				select {
+				case <-n.blockHead:
+				case <-req.Context().Done():
+					return
+				}
+			}
+			result = fmt.Sprintf("0x%x", n.head)
+		case "eth_sendRawTransaction":
+			atomic.AddInt32(&n.sent, 1)
+			if n.dropSend {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			} else if n.sendError != "" {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":%q}}`, msg.ID, n.sendError)
+				return
+			}
+			result = common.Hash{}
+		case "eth_getTransactionReceipt":
+			if n.mined {
+				var txHash common.Hash
+				json.Unmarshal(msg.Params[0], &txHash)
+				result = &gethtypes.Receipt{Status: gethtypes.ReceiptStatusSuccessful, TxHash: txHash, Logs: []*gethtypes.Log{}}
+			}
+		case "eth_getProof":
+			var keys []string
+			if len(msg.Params) > 1 {
+				json.Unmarshal(msg.Params[1], &keys)
+			}
+			storageProof := make([]map[string][]string, len(keys))
+			for i := range keys {
+				storageProof[i] = map[string][]string{"proof": {fmt.Sprintf("0xc1%02x", i)}}
+			}
+			result = map[string]interface{}{"storageHash": n.storageHash, "accountProof": []string{"0xc101"}, "storageProof": storageProof}
+		default:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"method not found"}}`, msg.ID)
+			return
+		}
+		bz, _ := json.Marshal(result)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, msg.ID, bz)
+	}))
+	return n
+}
+
+func TestFailover(t *testing.T) {
+	ctx := context.Background()
+	down := newFakeNode(100, common.Hash{1})
+	lagging := newFakeNode(90, common.Hash{2})
+	synced := newFakeNode(100, common.Hash{3})
+	defer lagging.Close()
+	defer synced.Close()
+
+	cl, err := NewFailoverETHClient([]string{down.URL, lagging.URL, synced.URL}, ibcclient.MockClient)
+	require.NoError(t, err)
+	f, ok := cl.Failover()
+	require.True(t, ok)
+	f.CheckHealth(ctx)
+	down.Close()
+
+	// the node that is down fails over to the node in sync
+	root, err := cl.GetStorageRoot(ctx, common.Address{}, big.NewInt(100))
+	require.NoError(t, err)
+	require.Equal(t, synced.storageHash, root)
+	require.Zero(t, atomic.LoadInt32(&lagging.calls)-1, "the lagging node must serve the health check only")
+
+	// an error returned by the node is not failed over
+	_, err = cl.CodeAt(ctx, common.Address{}, nil)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNoNode)
+
+	// the lagging node serves the heights it has once the other nodes are unavailable
+	synced.Close()
+	root, err = cl.GetStorageRoot(ctx, common.Address{}, big.NewInt(80))
+	require.NoError(t, err)
+	require.Equal(t, lagging.storageHash, root)
+
+	statuses := f.Statuses()
+	require.Len(t, statuses, 3)
+	require.Equal(t, down.URL, statuses[0].Endpoint)
+	require.Error(t, statuses[0].LastError)
+	require.False(t, statuses[1].InSync)
+	require.True(t, statuses[2].InSync)
+}
+
+func TestFailoverNoNode(t *testing.T) {
+	n := newFakeNode(1, common.Hash{})
+	n.Close()
+	cl, err := NewFailoverETHClient([]string{n.URL}, ibcclient.MockClient)
+	require.NoError(t, err)
+	_, err = cl.GetStorageRoot(context.Background(), common.Address{}, big.NewInt(1))
+	require.ErrorIs(t, err, ErrNoNode)
+
+	_, err = NewFailoverETHClient(nil, ibcclient.MockClient)
+	require.Error(t, err)
+}
+
+func TestFailoverHealthCheck(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	newClient := func(timeout time.Duration) (*Client, *Failover, *fakeNode, *fakeNode) {
+		stuck := newFakeNode(100, common.Hash{1})
+		stuck.blockHead = make(chan struct{})
+		synced := newFakeNode(110, common.Hash{2})
+		t.Cleanup(func() {
+			close(stuck.blockHead)
+			stuck.Close()
+			synced.Close()
+		})
+		cl, err := NewFailoverETHClient([]string{stuck.URL, synced.URL}, ibcclient.MockClient)
+		require.NoError(t, err)
+		f, _ := cl.Failover()
+		f.HealthCheckTimeout = timeout
+		return cl, f, stuck, synced
+	}
+
+	// the call does not wait for the health check it starts
+	cl, f, stuck, _ := newClient(time.Minute)
+	root, err := cl.GetStorageRoot(ctx, common.Address{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, stuck.storageHash, root)
+	require.Eventually(t, func() bool { return f.Statuses()[1].Head == 110 }, 5*time.Second, 10*time.Millisecond)
+	require.Zero(t, f.Statuses()[0].Head)
+
+	// the node not responding in time does not hold up the health check
+	_, f, _, _ = newClient(50 * time.Millisecond)
+	f.CheckHealth(ctx)
+	statuses := f.Statuses()
+	require.Zero(t, statuses[0].Head)
+	require.Error(t, statuses[0].LastError)
+	require.Equal(t, uint64(110), statuses[1].Head)
+	require.NoError(t, ctx.Err())
+}
+
+func TestFailoverSendTransaction(t *testing.T) {
+	ctx := context.Background()
+	tx := gethtypes.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+	send := func(first, second *fakeNode) error {
+		defer first.Close()
+		defer second.Close()
+		cl, err := NewFailoverETHClient([]string{first.URL, second.URL}, ibcclient.MockClient)
+		require.NoError(t, err)
+		f, _ := cl.Failover()
+		f.CheckHealth(ctx)
+		return cl.SendTransaction(ctx, tx)
+	}
+	node := func(dropSend bool, sendError string, mined bool) *fakeNode {
+		n := newFakeNode(1, common.Hash{})
+		n.dropSend, n.sendError, n.mined = dropSend, sendError, mined
+		return n
+	}
+
+	// the transaction possibly sent to the node dropping the connection is known to the next node
+	for _, msg := range []string{"already known", "Known transaction"} {
+		first, second := node(true, "", false), node(false, msg, false)
+		require.NoError(t, send(first, second), msg)
+		require.Equal(t, int32(1), atomic.LoadInt32(&first.sent))
+		require.Equal(t, int32(1), atomic.LoadInt32(&second.sent))
+	}
+	// the transaction has been included in a block if its receipt is found
+	require.NoError(t, send(node(true, "", false), node(false, "nonce too low", true)))
+	// another transaction of the same nonce has been included otherwise
+	err := send(node(true, "", false), node(false, "nonce too low", false))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNoNode)
+	// the rejection is returned unless sending has failed due to the transport
+	require.Error(t, send(node(false, "already known", false), node(false, "", false)))
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/metrics"
+)
+
+// Option configures the connection of a client to its nodes.
+type Option func(*options)
+
+type options struct {
+	transport http.RoundTripper
+}
+
+// WithTransport sets the transport sending the JSON-RPC calls of the client, e.g. a rpcreplay.Recorder
+// recording the calls to fixture files or a rpcreplay.Replayer replaying them with no network.
+// The calls are recorded by the metrics package on top of the transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o options) httpClient() *http.Client {
+	return &http.Client{Transport: &metrics.Transport{Base: o.transport}}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"math/big"
@@ -15,8 +16,17 @@ type ETHProof struct {
 }
 
 func (cl Client) GetETHProof(address common.Address, storageKeys [][]byte, blockNumber *big.Int) (*ETHProof, error) {
-	bz, err := cl.getProof(address, storageKeys, "0x"+blockNumber.Text(16))
-	if err != nil {
+	finalized := cl.cache != nil && cl.cache.finalized(blockNumber)
+	if finalized {
+		if proof, ok := cl.cache.getProof(address, storageKeys, blockNumber.Uint64()); ok {
+			return proof, nil
+		}
+	}
+	var bz []byte
+	if err := cl.pinned(context.Background(), blockNumber, func(cl Client) (err error) {
+		bz, err = cl.getProof(address, storageKeys, "0x"+blockNumber.Text(16))
+		return err
+	}); err != nil {
 		return nil, err
 	}
 	var proof struct {
@@ -29,7 +39,10 @@ func (cl Client) GetETHProof(address common.Address, storageKeys [][]byte, block
 		return nil, err
 	}
 
-	var encodedProof ETHProof
+	var (
+		encodedProof ETHProof
+		err          error
+	)
 	encodedProof.AccountProofRLP, err = encodeRLP(proof.AccountProof)
 	if err != nil {
 		return nil, err
@@ -41,10 +54,27 @@ func (cl Client) GetETHProof(address common.Address, storageKeys [][]byte, block
 		}
 		encodedProof.StorageProofRLP = append(encodedProof.StorageProofRLP, bz)
 	}
+	if finalized {
+		cl.cache.addProof(address, storageKeys, blockNumber.Uint64(), &encodedProof)
+	}
 
 	return &encodedProof, nil
 }
 
+// GetStorageRoot returns the storage root of the account at the given block number, which is the root
+// the IBFT2 client stores in its consensus state for the IBC store account.
+func (cl Client) GetStorageRoot(ctx context.Context, address common.Address, blockNumber *big.Int) (common.Hash, error) {
+	var proof struct {
+		StorageHash common.Hash `json:"storageHash"`
+	}
+	if err := cl.pinned(ctx, blockNumber, func(cl Client) error {
+		return cl.conn.CallContext(ctx, &proof, "eth_getProof", address, []common.Hash{}, "0x"+blockNumber.Text(16))
+	}); err != nil {
+		return common.Hash{}, err
+	}
+	return proof.StorageHash, nil
+}
+
 func (cl Client) getProof(address common.Address, storageKeys [][]byte, blockNumber string) ([]byte, error) {
 	hashes := []common.Hash{}
 	for _, k := range storageKeys {
@@ -55,7 +85,7 @@ func (cl Client) getProof(address common.Address, storageKeys [][]byte, blockNum
 		hashes = append(hashes, h)
 	}
 	var msg json.RawMessage
-	if err := cl.conn.Call(&msg, "eth_getProof", address, hashes, blockNumber); err != nil {
+	if err := cl.conn.CallContext(context.Background(), &msg, "eth_getProof", address, hashes, blockNumber); err != nil {
 		return nil, err
 	}
 	return msg, nil
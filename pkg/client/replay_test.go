@@ -0,0 +1,481 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/rpcreplay"
+)
+
+var (
+	recordEndpoint = flag.String("record", "", "record the fixture of TestReplayBesu from the Besu node of the endpoint, e.g. http://127.0.0.1:8645")
+	updateFixture  = flag.Bool("update", false, "record the fixtures of the synthetic replay tests from the synthetic Besu node")
+)
+
+const (
+	// besuFixture is the fixture recorded from a Besu node with -record.
+	besuFixture = "testdata/besu.json"
+	// syntheticBesuFixture is the fixture recorded from the synthetic Besu node by the Besu client with -update.
+	syntheticBesuFixture = "testdata/besu_synthetic.json"
+	// syntheticETHFixture is the fixture recorded from the synthetic Besu node by the ETH client with -update.
+	syntheticETHFixture = "testdata/eth_synthetic.json"
+)
+
+// TestReplayBesu checks the client against the calls recorded from a Besu node, which is done by running
+// the test with -record=<endpoint>. The test is skipped until the fixture is recorded.
+func TestReplayBesu(t *testing.T) {
+	var transport http.RoundTripper
+	endpoint := "http://replay"
+	var recorder *rpcreplay.Recorder
+	if *recordEndpoint != "" {
+		endpoint = *recordEndpoint
+		recorder = rpcreplay.NewRecorder(nil)
+		transport = recorder
+	} else if _, err := os.Stat(besuFixture); os.IsNotExist(err) {
+		t.Skipf("%v has not been recorded; record it from a Besu node with -record=<endpoint>", besuFixture)
+	} else {
+		replayer, err := rpcreplay.NewReplayerFromFile(besuFixture)
+		require.NoError(t, err)
+		transport = replayer
+	}
+	cl, err := NewBesuClient(endpoint, ibcclient.BesuIBFT2Client, WithTransport(transport))
+	require.NoError(t, err)
+	checkBesuClient(t, cl)
+	if recorder != nil {
+		require.NoError(t, recorder.Save(besuFixture))
+	}
+}
+
+// TestReplaySyntheticBesu checks the client against the calls recorded from the synthetic Besu node, which
+// is done by running the test with -update. Unlike TestReplayBesu, it covers a failed transaction.
+func TestReplaySyntheticBesu(t *testing.T) {
+	endpoint, transport, save := syntheticTransport(t, syntheticBesuFixture)
+	cl, err := NewBesuClient(endpoint, ibcclient.BesuIBFT2Client, WithTransport(transport))
+	require.NoError(t, err)
+	receipts := checkBesuClient(t, cl)
+	require.Len(t, receipts, 2)
+	require.Equal(t, gethtypes.ReceiptStatusSuccessful, receipts[0].Status())
+	require.Len(t, receipts[0].Logs(), 1)
+	require.Equal(t, gethtypes.ReceiptStatusFailed, receipts[1].Status())
+	require.Equal(t, syntheticRevertReason, receipts[1].RevertReason())
+	save()
+}
+
+// TestReplaySyntheticETH checks the client created by NewETHClient against the calls recorded from the
+// synthetic Besu node, which serves the standard methods an Ethereum node does. The receipts are decoded by
+// go-ethereum, which drops the revert reason of the failed transaction.
+func TestReplaySyntheticETH(t *testing.T) {
+	endpoint, transport, save := syntheticTransport(t, syntheticETHFixture)
+	cl, err := NewETHClient(endpoint, ibcclient.MockClient, WithTransport(transport))
+	require.NoError(t, err)
+	block, receipts := checkClient(t, cl)
+	require.Len(t, receipts, 2)
+	require.Equal(t, gethtypes.ReceiptStatusSuccessful, receipts[0].Status())
+	require.Len(t, receipts[0].Logs(), 1)
+	require.Equal(t, gethtypes.ReceiptStatusFailed, receipts[1].Status())
+	require.Empty(t, receipts[1].RevertReason())
+
+	// the mock client needs the header only
+	cs, err := cl.GetContractState(context.Background(), *block.Transactions()[0].To(), [][]byte{[]byte(common.Hash{}.Hex())}, block.Number())
+	require.NoError(t, err)
+	require.Equal(t, block.Hash(), cs.Header().Hash())
+	save()
+}
+
+// syntheticTransport returns the endpoint and the transport replaying the fixture, or recording it from the
+// synthetic Besu node with -update, in which case the returned function saves the fixture.
+func syntheticTransport(t *testing.T, fixture string) (string, http.RoundTripper, func()) {
+	if !*updateFixture {
+		replayer, err := rpcreplay.NewReplayerFromFile(fixture)
+		require.NoError(t, err)
+		return "http://replay", replayer, func() {}
+	}
+	node := newSyntheticBesuNode(t)
+	t.Cleanup(node.Close)
+	recorder := rpcreplay.NewRecorder(nil)
+	return node.URL, recorder, func() {
+		require.NoError(t, recorder.Save(fixture))
+	}
+}
+
+// checkBesuClient checks the client as checkClient does, and the IBFT2 header of the block and the revert
+// reasons of the receipts returned by Besu.
+func checkBesuClient(t *testing.T, cl *Client) []Receipt {
+	block, receipts := checkClient(t, cl)
+
+	// the header and the proof of a slot of the contract called by the first transaction
+	address, slot := *block.Transactions()[0].To(), common.Hash{}
+	cs, err := cl.GetIBFT2ContractState(context.Background(), address, [][]byte{[]byte(slot.Hex())}, block.Number())
+	require.NoError(t, err)
+	ibft2 := cs.(IBFT2ContractState)
+	require.Equal(t, block.Root(), ibft2.Header().Root)
+	require.Len(t, ibft2.GetCommitSeals(), len(ibft2.Validators()))
+	proof, err := cl.GetETHProof(address, [][]byte{[]byte(slot.Hex())}, block.Number())
+	require.NoError(t, err)
+	require.Equal(t, proof, cs.ETHProof())
+
+	for _, rc := range receipts {
+		_, err = parseRevertReason(rc.(*besuReceipt).RevertReason_)
+		require.NoError(t, err)
+	}
+	return receipts
+}
+
+// checkClient parses the header, encodes the proofs and decodes the receipts of the latest block with
+// transactions, and returns the block and the receipts. The proofs are verified against the state root of the
+// header, and the receipts against its receipts root, so that they are checked independently of how they are
+// decoded.
+func checkClient(t *testing.T, cl *Client) (*gethtypes.Block, []Receipt) {
+	ctx := context.Background()
+	block, err := cl.BlockByNumber(ctx, nil)
+	require.NoError(t, err)
+	for len(block.Transactions()) == 0 {
+		require.NotZero(t, block.NumberU64(), "no block with transactions")
+		block, err = cl.BlockByNumber(ctx, new(big.Int).Sub(block.Number(), big.NewInt(1)))
+		require.NoError(t, err)
+	}
+
+	// the proof of a slot of the contract called by the first transaction
+	address, slot := *block.Transactions()[0].To(), common.Hash{}
+	proof, err := cl.GetETHProof(address, [][]byte{[]byte(slot.Hex())}, block.Number())
+	require.NoError(t, err)
+
+	accountRLP := verifyProof(t, block.Root(), crypto.Keccak256(address.Bytes()), proof.AccountProofRLP)
+	require.NotNil(t, accountRLP)
+	var account state.Account
+	require.NoError(t, rlp.DecodeBytes(accountRLP, &account))
+	storageRoot, err := cl.GetStorageRoot(ctx, address, block.Number())
+	require.NoError(t, err)
+	require.Equal(t, account.Root, storageRoot)
+
+	require.Len(t, proof.StorageProofRLP, 1)
+	var value []byte
+	if valueRLP := verifyProof(t, account.Root, crypto.Keccak256(slot.Bytes()), proof.StorageProofRLP[0]); valueRLP != nil {
+		require.NoError(t, rlp.DecodeBytes(valueRLP, &value))
+	}
+	stored, err := cl.StorageAt(ctx, address, slot, block.Number())
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToHash(stored), common.BytesToHash(value))
+
+	var (
+		receipts []Receipt
+		encoded  gethtypes.Receipts
+	)
+	for i, tx := range block.Transactions() {
+		rc, err := cl.TransactionReceipt(ctx, tx.Hash())
+		require.NoError(t, err)
+		require.Equal(t, tx.Hash(), rc.TxHash())
+		require.Equal(t, block.Hash(), rc.BlockHash())
+		require.Equal(t, block.Number(), rc.BlockNumber())
+		require.EqualValues(t, i, rc.TransactionIndex())
+		for _, log := range rc.Logs() {
+			require.Equal(t, tx.Hash(), log.TxHash)
+		}
+		receipts = append(receipts, rc)
+		encoded = append(encoded, &gethtypes.Receipt{
+			PostState:         rc.PostState(),
+			Status:            rc.Status(),
+			CumulativeGasUsed: rc.CumulativeGasUsed(),
+			Bloom:             rc.Bloom(),
+			Logs:              rc.Logs(),
+		})
+	}
+	require.Equal(t, block.ReceiptHash(), gethtypes.DeriveSha(encoded, new(trie.Trie)))
+	return block, receipts
+}
+
+// verifyProof returns the value of the key proven by the RLP-encoded proof, which is nil if the key is absent.
+func verifyProof(t *testing.T, root common.Hash, key []byte, proofRLP []byte) []byte {
+	var nodes []rlp.RawValue
+	require.NoError(t, rlp.DecodeBytes(proofRLP, &nodes))
+	db := memorydb.New()
+	for _, node := range nodes {
+		require.NoError(t, db.Put(crypto.Keccak256(node), node))
+	}
+	value, err := trie.VerifyProof(root, key, db)
+	require.NoError(t, err)
+	return value
+}
+
+// syntheticRevertReason is the revert reason of the failed transaction in the block of the synthetic node.
+const syntheticRevertReason = "insufficient balance"
+
+// newSyntheticBesuNode returns a JSON-RPC server serving a block sealed by IBFT2 validators, the state
+// of a contract called by the transactions in the block, and their receipts, one of which is failed.
+func newSyntheticBesuNode(t *testing.T) *httptest.Server {
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < 4; i++ {
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("validator%v", i))))
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	sender, err := crypto.ToECDSA(crypto.Keccak256([]byte("sender")))
+	require.NoError(t, err)
+	chainID := big.NewInt(2018)
+	contract := common.HexToAddress("0x702E40245797c5a2108A566b3CE2Bf14Bc6aF841")
+
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	statedb.SetNonce(contract, 1)
+	statedb.SetCode(contract, []byte{0x60, 0x00})
+	for i := int64(0); i < 16; i++ {
+		statedb.SetState(contract, common.BigToHash(big.NewInt(i)), common.BigToHash(big.NewInt(i+1)))
+	}
+	root, err := statedb.Commit(false)
+	require.NoError(t, err)
+
+	number := big.NewInt(100)
+	signer := gethtypes.NewEIP155Signer(chainID)
+	reason, err := abi.Arguments{{Type: mustNewType(t, "string")}}.Pack(syntheticRevertReason)
+	require.NoError(t, err)
+	var (
+		txs      []*gethtypes.Transaction
+		receipts []*gethtypes.Receipt
+		reasons  [][]byte
+	)
+	for i := uint64(0); i < 2; i++ {
+		tx, err := gethtypes.SignTx(gethtypes.NewTransaction(i, contract, big.NewInt(0), 100000, big.NewInt(0), []byte{byte(i)}), signer, sender)
+		require.NoError(t, err)
+		rc := &gethtypes.Receipt{Status: gethtypes.ReceiptStatusSuccessful, Logs: []*gethtypes.Log{}, CumulativeGasUsed: 50000 * (i + 1), TxHash: tx.Hash(), GasUsed: 50000, TransactionIndex: uint(i)}
+		if i == 0 {
+			rc.Logs = []*gethtypes.Log{{Address: contract, Topics: []common.Hash{crypto.Keccak256Hash([]byte("Sent()"))}, Data: []byte{1}, TxHash: tx.Hash()}}
+			reasons = append(reasons, nil)
+		} else {
+			rc.Status = gethtypes.ReceiptStatusFailed
+			reasons = append(reasons, append(common.FromHex("0x08c379a0"), reason...))
+		}
+		rc.Bloom = gethtypes.CreateBloom(gethtypes.Receipts{rc})
+		txs = append(txs, tx)
+		receipts = append(receipts, rc)
+	}
+
+	header := &gethtypes.Header{ParentHash: common.Hash{1}, Root: root, Number: number, Difficulty: big.NewInt(1), GasLimit: 10000000, GasUsed: 100000, Time: 1600000000}
+	block := gethtypes.NewBlock(header, txs, nil, receipts, new(trie.Trie))
+	header = block.Header()
+	var validators []common.Address
+	for _, key := range keys {
+		validators = append(validators, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	var (
+		vanity [32]byte
+		round  [4]byte
+	)
+	vote := []interface{}{}
+	header.Extra, err = rlp.EncodeToBytes([]interface{}{vanity, validators, vote, round})
+	require.NoError(t, err)
+	bz, err := rlp.EncodeToBytes(header)
+	require.NoError(t, err)
+	var seals [][]byte
+	for _, key := range keys {
+		seal, err := crypto.Sign(crypto.Keccak256(bz), key)
+		require.NoError(t, err)
+		seals = append(seals, seal)
+	}
+	header.Extra, err = rlp.EncodeToBytes([]interface{}{vanity, validators, vote, round, seals})
+	require.NoError(t, err)
+	block = gethtypes.NewBlockWithHeader(header).WithBody(txs, nil)
+	for i, rc := range receipts {
+		rc.BlockHash, rc.BlockNumber = block.Hash(), number
+		for _, log := range rc.Logs {
+			log.BlockHash, log.BlockNumber, log.TxIndex = block.Hash(), number.Uint64(), uint(i)
+		}
+	}
+
+	rpcBlock := func() (interface{}, error) {
+		var fields map[string]interface{}
+		bz, err := json.Marshal(block.Header())
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(bz, &fields); err != nil {
+			return nil, err
+		}
+		var rpcTxs []interface{}
+		for i, tx := range txs {
+			var fields map[string]interface{}
+			bz, err := json.Marshal(tx)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(bz, &fields); err != nil {
+				return nil, err
+			}
+			fields["blockHash"] = block.Hash()
+			fields["blockNumber"] = (*hexutil.Big)(number)
+			fields["transactionIndex"] = hexutil.Uint(i)
+			fields["from"] = crypto.PubkeyToAddress(sender.PublicKey)
+			rpcTxs = append(rpcTxs, fields)
+		}
+		fields["transactions"] = rpcTxs
+		fields["uncles"] = []common.Hash{}
+		return fields, nil
+	}
+	// the receipts are written in the fields of the receipts of Besu, where the revert reason is the raw
+	// return data of the transaction, and not with the types decoding them
+	rpcReceipt := func(i int) interface{} {
+		rc := receipts[i]
+		var logs []interface{}
+		for j, log := range rc.Logs {
+			logs = append(logs, map[string]interface{}{
+				"address":          log.Address,
+				"topics":           log.Topics,
+				"data":             hexutil.Bytes(log.Data),
+				"blockNumber":      hexutil.EncodeBig(number),
+				"blockHash":        block.Hash(),
+				"transactionHash":  rc.TxHash,
+				"transactionIndex": hexutil.EncodeUint64(uint64(i)),
+				"logIndex":         hexutil.EncodeUint64(uint64(j)),
+				"removed":          false,
+			})
+		}
+		fields := map[string]interface{}{
+			"blockHash":         block.Hash(),
+			"blockNumber":       hexutil.EncodeBig(number),
+			"contractAddress":   nil,
+			"cumulativeGasUsed": hexutil.EncodeUint64(rc.CumulativeGasUsed),
+			"from":              crypto.PubkeyToAddress(sender.PublicKey),
+			"gasUsed":           hexutil.EncodeUint64(rc.GasUsed),
+			"logs":              append([]interface{}{}, logs...),
+			"logsBloom":         hexutil.Bytes(rc.Bloom.Bytes()),
+			"status":            hexutil.EncodeUint64(rc.Status),
+			"to":                contract,
+			"transactionHash":   rc.TxHash,
+			"transactionIndex":  hexutil.EncodeUint64(uint64(i)),
+		}
+		if reasons[i] != nil {
+			fields["revertReason"] = hexutil.Bytes(reasons[i])
+		}
+		return fields
+	}
+	getProof := func(keys []common.Hash) (interface{}, error) {
+		accountProof, err := statedb.GetProof(contract)
+		if err != nil {
+			return nil, err
+		}
+		var storageProof []interface{}
+		for _, key := range keys {
+			proof, err := statedb.GetStorageProof(contract, key)
+			if err != nil {
+				return nil, err
+			}
+			storageProof = append(storageProof, map[string]interface{}{
+				"key":   key,
+				"value": (*hexutil.Big)(statedb.GetState(contract, key).Big()),
+				"proof": encodeProof(proof),
+			})
+		}
+		return map[string]interface{}{
+			"address":      contract,
+			"balance":      (*hexutil.Big)(statedb.GetBalance(contract)),
+			"codeHash":     statedb.GetCodeHash(contract),
+			"nonce":        hexutil.Uint64(statedb.GetNonce(contract)),
+			"storageHash":  statedb.StorageTrie(contract).Hash(),
+			"accountProof": encodeProof(accountProof),
+			"storageProof": storageProof,
+		}, nil
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var msg struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		param := func(i int, v interface{}) error {
+			if i >= len(msg.Params) {
+				return fmt.Errorf("missing params[%v]", i)
+			}
+			return json.Unmarshal(msg.Params[i], v)
+		}
+		var (
+			result interface{}
+			err    error
+		)
+		switch msg.Method {
+		case "eth_getBlockByNumber":
+			var tag string
+			if err = param(0, &tag); err == nil && tag != "latest" && tag != hexutil.EncodeBig(number) {
+				err = fmt.Errorf("unknown block: %v", tag)
+			}
+			if err == nil {
+				result, err = rpcBlock()
+			}
+		case "eth_getProof":
+			var (
+				address common.Address
+				keys    []common.Hash
+			)
+			if err = param(0, &address); err == nil && address != contract {
+				err = fmt.Errorf("unknown account: %v", address.Hex())
+			}
+			if err == nil {
+				err = param(1, &keys)
+			}
+			if err == nil {
+				result, err = getProof(keys)
+			}
+		case "eth_getStorageAt":
+			var key common.Hash
+			if err = param(1, &key); err == nil {
+				result = statedb.GetState(contract, key)
+			}
+		case "eth_getTransactionReceipt":
+			var hash common.Hash
+			if err = param(0, &hash); err == nil {
+				for i, tx := range txs {
+					if tx.Hash() == hash {
+						result = rpcReceipt(i)
+					}
+				}
+			}
+		default:
+			err = fmt.Errorf("the method %v does not exist/is not available", msg.Method)
+		}
+		if err != nil {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":%q}}`, msg.ID, err.Error())
+			return
+		}
+		bz, _ := json.Marshal(result)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, msg.ID, bz)
+	}))
+}
+
+// encodeProof encodes the nodes of a proof as the hex strings returned by eth_getProof.
+func encodeProof(proof [][]byte) []string {
+	hexes := []string{}
+	for _, node := range proof {
+		hexes = append(hexes, hexutil.Encode(node))
+	}
+	return hexes
+}
+
+func mustNewType(t *testing.T, typ string) abi.Type {
+	ty, err := abi.NewType(typ, "", nil)
+	require.NoError(t, err)
+	return ty
+}
@@ -0,0 +1,269 @@
+// Package simulated serves the clients of the chains simulated in process by the simulated backend of
+// go-ethereum, for the tests that need no node running. It is kept apart from pkg/client so that the
+// tools built on the client do not link the simulated backend.
+package simulated
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+)
+
+// NewClient creates a new client of a chain simulated in process by the simulated backend of go-ethereum,
+// whose genesis has the accounts in alloc. A block is mined for each transaction sent by the client, like the
+// automining of ganache, and for each query of the latest block, so that the chain seems to produce blocks
+// continuously. The time of a block is the current time lagged by simulatedTimeLag, or a second after its parent
+// if the blocks are mined faster. eth_getProof is served from the state of the backend, so the proofs can be queried as from a node.
+func NewClient(alloc core.GenesisAlloc, gasLimit uint64, clientType string) *client.Client {
+	return newClient(alloc, gasLimit, clientType, nil)
+}
+
+// NewIBFT2Client creates a new client of a chain simulated as NewClient, whose headers are
+// returned sealed by the sealer as the headers of an IBFT2 chain. The headers keep the state roots of the
+// simulated blocks, so that the proofs served by the client are verified against them.
+func NewIBFT2Client(alloc core.GenesisAlloc, gasLimit uint64, sealer HeaderSealer) *client.Client {
+	return newClient(alloc, gasLimit, ibcclient.BesuIBFT2Client, sealer)
+}
+
+// HeaderSealer seals the headers of a simulated chain.
+type HeaderSealer interface {
+	SealHeader(header *gethtypes.Header) (*gethtypes.Header, error)
+}
+
+func newClient(alloc core.GenesisAlloc, gasLimit uint64, clientType string, sealer HeaderSealer) *client.Client {
+	db := rawdb.NewMemoryDatabase()
+	sim := simulatedClient{
+		SimulatedBackend: backends.NewSimulatedBackendWithDatabase(db, alloc, gasLimit),
+		db:               db,
+		sealer:           sealer,
+		mu:               new(sync.Mutex),
+	}
+	return client.NewBackendClient("simulated", clientType, sim)
+}
+
+// simulatedTimeLag is the lag of the time of the simulated blocks behind the current time. The consensus engine
+// defers the blocks more than 15 seconds ahead of the current time, so the blocks mined faster than one per second
+// must start behind it.
+const simulatedTimeLag = 24 * time.Hour
+
+type simulatedClient struct {
+	*backends.SimulatedBackend
+	db     ethdb.Database
+	sealer HeaderSealer
+
+	// mu serializes the mining of the blocks
+	mu *sync.Mutex
+}
+
+var _ client.Backend = simulatedClient{}
+
+// mine mines a block including the transaction if it is not nil. The block is generated here instead of
+// committing the pending block of the backend, whose time cannot be adjusted once it has a transaction.
+func (cl simulatedClient) mine(ctx context.Context, tx *gethtypes.Transaction) error {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	chain := cl.Blockchain()
+	parent := chain.CurrentBlock()
+	// a block is generated 10 seconds after its parent unless its time is offset
+	next := uint64(time.Now().Add(-simulatedTimeLag).Unix())
+	if next <= parent.Time() {
+		next = parent.Time() + 1
+	}
+	var txErr error
+	blocks, _ := core.GenerateChain(chain.Config(), parent, ethash.NewFaker(), cl.db, 1, func(_ int, b *core.BlockGen) {
+		b.OffsetTime(int64(next) - int64(parent.Time()+10))
+		if tx == nil {
+			return
+		}
+		// the transaction that cannot be applied panics
+		defer func() {
+			if r := recover(); r != nil {
+				txErr = fmt.Errorf("invalid transaction: %v", r)
+			}
+		}()
+		b.AddTxWithChain(chain, tx)
+	})
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		return err
+	}
+	// reset the pending block and state on the new block
+	cl.Rollback()
+	return nil
+}
+
+// SendTransaction sends the transaction and mines a block including it.
+func (cl simulatedClient) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	return cl.mine(ctx, tx)
+}
+
+// BlockByNumber returns the block of the given number. A new block is mined if the number is nil.
+func (cl simulatedClient) BlockByNumber(ctx context.Context, bn *big.Int) (*gethtypes.Block, error) {
+	if bn == nil {
+		if err := cl.mine(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	block, err := cl.SimulatedBackend.BlockByNumber(ctx, bn)
+	if err != nil || cl.sealer == nil {
+		return block, err
+	}
+	header, err := cl.sealer.SealHeader(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	return block.WithSeal(header), nil
+}
+
+// HeaderByNumber returns the header of the given number. A new block is mined if the number is nil.
+func (cl simulatedClient) HeaderByNumber(ctx context.Context, bn *big.Int) (*gethtypes.Header, error) {
+	if bn == nil {
+		if err := cl.mine(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	header, err := cl.SimulatedBackend.HeaderByNumber(ctx, bn)
+	if err != nil || cl.sealer == nil {
+		return header, err
+	}
+	return cl.sealer.SealHeader(header)
+}
+
+func (cl simulatedClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (client.Receipt, error) {
+	rc, err := cl.SimulatedBackend.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	} else if rc == nil {
+		return nil, ethereum.NotFound
+	}
+	return client.NewReceipt(rc), nil
+}
+
+// CallContext serves eth_getProof, which is the only JSON-RPC method called by client.Client besides client.ETHClient.
+func (cl simulatedClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var (
+		res interface{}
+		err error
+	)
+	switch method {
+	case "eth_getProof":
+		res, err = cl.getProof(args...)
+	default:
+		err = fmt.Errorf("the method %v is not supported by the simulated backend", method)
+	}
+	if err != nil {
+		return err
+	}
+	bz, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bz, result)
+}
+
+type accountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []string        `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []storageResult `json:"storageProof"`
+}
+
+type storageResult struct {
+	Key   common.Hash  `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []string     `json:"proof"`
+}
+
+// getProof returns the proof of the account and its storage as eth_getProof(address, storageKeys, blockNumber).
+func (cl simulatedClient) getProof(args ...interface{}) (*accountResult, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("eth_getProof takes 3 arguments, but got %v", len(args))
+	}
+	address, ok := args[0].(common.Address)
+	if !ok {
+		return nil, fmt.Errorf("invalid address: %v", args[0])
+	}
+	keys, ok := args[1].([]common.Hash)
+	if !ok {
+		return nil, fmt.Errorf("invalid storage keys: %v", args[1])
+	}
+	blockNumber, ok := args[2].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid block number: %v", args[2])
+	}
+	bn, err := hexutil.DecodeBig(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	header := cl.Blockchain().GetHeaderByNumber(bn.Uint64())
+	if header == nil {
+		return nil, ethereum.NotFound
+	}
+	statedb, err := cl.Blockchain().StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	accountProof, err := statedb.GetProof(address)
+	if err != nil {
+		return nil, err
+	}
+	res := &accountResult{
+		Address:      address,
+		AccountProof: encodeProof(accountProof),
+		Balance:      (*hexutil.Big)(statedb.GetBalance(address)),
+		CodeHash:     statedb.GetCodeHash(address),
+		Nonce:        hexutil.Uint64(statedb.GetNonce(address)),
+		StorageProof: []storageResult{},
+	}
+	storageTrie := statedb.StorageTrie(address)
+	if storageTrie != nil {
+		res.StorageHash = storageTrie.Hash()
+	} else {
+		res.StorageHash = gethtypes.EmptyRootHash
+	}
+	for _, key := range keys {
+		var proof [][]byte
+		if storageTrie != nil {
+			if proof, err = statedb.GetStorageProof(address, key); err != nil {
+				return nil, err
+			}
+		}
+		res.StorageProof = append(res.StorageProof, storageResult{
+			Key:   key,
+			Value: (*hexutil.Big)(statedb.GetState(address, key).Big()),
+			Proof: encodeProof(proof),
+		})
+	}
+	return res, nil
+}
+
+// encodeProof encodes the nodes of a proof as the hex strings returned by eth_getProof.
+func encodeProof(proof [][]byte) []string {
+	hexes := []string{}
+	for _, node := range proof {
+		hexes = append(hexes, hexutil.Encode(node))
+	}
+	return hexes
+}
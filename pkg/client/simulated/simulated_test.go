@@ -0,0 +1,133 @@
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/chains"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+)
+
+func TestSimulatedClient(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	contract := common.HexToAddress("0x702E40245797c5a2108A566b3CE2Bf14Bc6aF841")
+	slot := common.BigToHash(big.NewInt(1))
+	cl := NewClient(core.GenesisAlloc{
+		sender:   {Balance: big.NewInt(1000000000)},
+		contract: {Code: []byte{0x60, 0x00}, Storage: map[common.Hash]common.Hash{slot: common.BigToHash(big.NewInt(100))}, Balance: big.NewInt(0)},
+	}, 10000000, ibcclient.MockClient)
+
+	// a block is mined for each query of the latest block at the lagged current time
+	start := uint64(time.Now().Add(-simulatedTimeLag).Unix())
+	first, err := cl.BlockByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, first.NumberU64())
+	require.GreaterOrEqual(t, first.Time(), start)
+	// the blocks mined faster than one per second are not deferred
+	for i := uint64(2); i <= 30; i++ {
+		block, err := cl.BlockByNumber(ctx, nil)
+		require.NoError(t, err)
+		require.Equal(t, i, block.NumberU64())
+	}
+
+	// a block is mined for each transaction
+	signer := gethtypes.NewEIP155Signer(big.NewInt(1337))
+	tx, err := gethtypes.SignTx(gethtypes.NewTransaction(0, contract, big.NewInt(1), 30000, big.NewInt(1), nil), signer, key)
+	require.NoError(t, err)
+	require.NoError(t, cl.SendTransaction(ctx, tx))
+	rc, err := cl.WaitForReceiptAndGet(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, gethtypes.ReceiptStatusSuccessful, rc.Status())
+	require.EqualValues(t, 31, rc.BlockNumber().Uint64())
+	block, err := cl.BlockByNumber(ctx, rc.BlockNumber())
+	require.NoError(t, err)
+	require.Greater(t, block.Time(), first.Time())
+	// the transaction that cannot be applied is rejected
+	require.Error(t, cl.SendTransaction(ctx, tx))
+
+	// the proofs are served from the state
+	cs, err := cl.GetMockContractState(ctx, contract, nil, nil)
+	require.NoError(t, err)
+	header := cs.Header()
+	proof, err := cl.GetETHProof(contract, [][]byte{[]byte(slot.Hex())}, header.Number)
+	require.NoError(t, err)
+	accountRLP := verifyProof(t, header.Root, crypto.Keccak256(contract.Bytes()), proof.AccountProofRLP)
+	var account state.Account
+	require.NoError(t, rlp.DecodeBytes(accountRLP, &account))
+	require.Equal(t, big.NewInt(1), account.Balance)
+	storageRoot, err := cl.GetStorageRoot(ctx, contract, header.Number)
+	require.NoError(t, err)
+	require.Equal(t, account.Root, storageRoot)
+	var value []byte
+	require.NoError(t, rlp.DecodeBytes(verifyProof(t, account.Root, crypto.Keccak256(slot.Bytes()), proof.StorageProofRLP[0]), &value))
+	require.Equal(t, big.NewInt(100), new(big.Int).SetBytes(value))
+}
+
+func TestSimulatedIBFT2Client(t *testing.T) {
+	ctx := context.Background()
+	contract := common.HexToAddress("0x702E40245797c5a2108A566b3CE2Bf14Bc6aF841")
+	slot := common.BigToHash(big.NewInt(1))
+	keys, err := chains.GenerateValidatorKeys(4)
+	require.NoError(t, err)
+	sealer := chains.NewIBFT2Sealer(chains.IBFT2Consensus{Validators: keys})
+	cl := NewIBFT2Client(core.GenesisAlloc{
+		contract: {Code: []byte{0x60, 0x00}, Storage: map[common.Hash]common.Hash{slot: common.BigToHash(big.NewInt(100))}, Balance: big.NewInt(0)},
+	}, 10000000, sealer)
+
+	// the headers are sealed by the validators, keeping the state roots of the blocks
+	cs, err := cl.GetContractState(ctx, contract, [][]byte{[]byte(slot.Hex())}, nil)
+	require.NoError(t, err)
+	state := cs.(client.IBFT2ContractState)
+	require.Len(t, state.Validators(), 4)
+	require.Len(t, state.CommitSeals, 4)
+	storageRoot, err := cl.GetStorageRoot(ctx, contract, state.Header().Number)
+	require.NoError(t, err)
+	verifyProof(t, state.Header().Root, crypto.Keccak256(contract.Bytes()), state.ETHProof().AccountProofRLP)
+	verifyProof(t, storageRoot, crypto.Keccak256(slot.Bytes()), state.ETHProof().StorageProofRLP[0])
+
+	// the validators are rotated at the next block
+	next := state.Header().Number.Uint64() + 1
+	sealer.Schedule(next, chains.IBFT2Consensus{Validators: keys[1:]})
+	cs, err = cl.GetContractState(ctx, contract, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, next, cs.Header().Number.Uint64())
+	require.Len(t, cs.(client.IBFT2ContractState).Validators(), 3)
+
+	// the header missing the seals is rejected
+	sealer.Schedule(next+1, chains.IBFT2Consensus{Validators: keys[1:], Signers: keys[1:3]})
+	_, err = cl.GetContractState(ctx, contract, nil, nil)
+	require.Error(t, err)
+	// the headers sealed before are not changed
+	cs, err = cl.GetContractState(ctx, contract, nil, new(big.Int).SetUint64(next))
+	require.NoError(t, err)
+	require.Len(t, cs.(client.IBFT2ContractState).Validators(), 3)
+}
+
+// verifyProof returns the value of the key proven by the RLP-encoded proof, which is nil if the key is absent.
+func verifyProof(t *testing.T, root common.Hash, key []byte, proofRLP []byte) []byte {
+	var nodes []rlp.RawValue
+	require.NoError(t, rlp.DecodeBytes(proofRLP, &nodes))
+	db := memorydb.New()
+	for _, node := range nodes {
+		require.NoError(t, db.Put(crypto.Keccak256(node), node))
+	}
+	value, err := trie.VerifyProof(root, key, db)
+	require.NoError(t, err)
+	return value
+}
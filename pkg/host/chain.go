@@ -0,0 +1,241 @@
+// Package host binds the IBC contracts deployed on a running chain, for the tools that inspect and
+// maintain the IBC state of the chain, such as the audit, the monitors and the CLI. Unlike the testing
+// framework, it holds no keys, tracks no test connections, and returns every error to the caller.
+package host
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchost"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibcidentifier"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ics20bank"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ics20transferbank"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+)
+
+// DefaultPrefix is the commitment prefix IBCHost is deployed with, which can be changed by IBCHandler
+// until a connection is created.
+const DefaultPrefix = "ibc"
+
+// ErrAcknowledgementNotFound is returned if no acknowledgement has been written for the packet.
+var ErrAcknowledgementNotFound = errors.New("acknowledgement not found")
+
+var abiSendPacket abi.Event
+
+func init() {
+	parsedHandlerABI, err := abi.JSON(strings.NewReader(ibchandler.IbchandlerABI))
+	if err != nil {
+		panic(err)
+	}
+	abiSendPacket = parsedHandlerABI.Events["SendPacket"]
+}
+
+// ContractConfig is the addresses of the contracts bound to a chain.
+type ContractConfig interface {
+	GetIBCHostAddress() common.Address
+	GetIBCHandlerAddress() common.Address
+	GetIBCIdentifierAddress() common.Address
+	GetICS20TransferBankAddress() common.Address
+	GetICS20BankAddress() common.Address
+}
+
+// Chain is a chain whose IBC contracts are bound to its client.
+type Chain struct {
+	client client.Client
+	// chainID is the chain ID used by IBC, which the clients tracking the chain hold.
+	chainID string
+
+	IBCHost       ibchost.Ibchost
+	IBCHandler    ibchandler.Ibchandler
+	IBCIdentifier ibcidentifier.Ibcidentifier
+	ICS20Transfer ics20transferbank.Ics20transferbank
+	ICS20Bank     ics20bank.Ics20bank
+
+	ContractConfig ContractConfig
+}
+
+// NewChain binds the contracts of the config to the client. chainID is the chain ID used by IBC.
+func NewChain(cl client.Client, chainID string, config ContractConfig) (*Chain, error) {
+	ibcHost, err := ibchost.NewIbchost(config.GetIBCHostAddress(), cl)
+	if err != nil {
+		return nil, err
+	}
+	ibcHandler, err := ibchandler.NewIbchandler(config.GetIBCHandlerAddress(), cl)
+	if err != nil {
+		return nil, err
+	}
+	ibcIdentifier, err := ibcidentifier.NewIbcidentifier(config.GetIBCIdentifierAddress(), cl)
+	if err != nil {
+		return nil, err
+	}
+	ics20Transfer, err := ics20transferbank.NewIcs20transferbank(config.GetICS20TransferBankAddress(), cl)
+	if err != nil {
+		return nil, err
+	}
+	ics20Bank, err := ics20bank.NewIcs20bank(config.GetICS20BankAddress(), cl)
+	if err != nil {
+		return nil, err
+	}
+	return &Chain{
+		client:         cl,
+		chainID:        chainID,
+		IBCHost:        *ibcHost,
+		IBCHandler:     *ibcHandler,
+		IBCIdentifier:  *ibcIdentifier,
+		ICS20Transfer:  *ics20Transfer,
+		ICS20Bank:      *ics20Bank,
+		ContractConfig: config,
+	}, nil
+}
+
+func (chain *Chain) Client() client.Client {
+	return chain.client
+}
+
+func (chain *Chain) ClientType() string {
+	return chain.client.ClientType()
+}
+
+// ChainIDString returns the chain ID used by IBC.
+func (chain *Chain) ChainIDString() string {
+	return chain.chainID
+}
+
+func (chain *Chain) IBCHostAddress() common.Address {
+	return chain.ContractConfig.GetIBCHostAddress()
+}
+
+// GetCommitmentPrefix returns the prefix of the commitments stored in IBCHost, which the counterparty
+// chains keep in their connections to the chain.
+func (chain *Chain) GetCommitmentPrefix(ctx context.Context) ([]byte, error) {
+	return chain.IBCHost.GetCommitmentPrefix(chain.CallOpts(ctx))
+}
+
+// CallOpts returns the options to call the view functions of the contracts at the latest block.
+func (chain *Chain) CallOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx}
+}
+
+// GetClientIDs returns the IDs of the clients created on the chain in the order of their creation.
+func (chain *Chain) GetClientIDs(ctx context.Context) ([]string, error) {
+	it, err := chain.IBCHost.FilterGeneratedClientIdentifier(&bind.FilterOpts{Start: 0, Context: ctx})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Event.Arg0)
+	}
+	return ids, it.Error()
+}
+
+// GetConnectionIDs returns the IDs of the connections created on the chain in the order of their creation.
+func (chain *Chain) GetConnectionIDs(ctx context.Context) ([]string, error) {
+	it, err := chain.IBCHost.FilterGeneratedConnectionIdentifier(&bind.FilterOpts{Start: 0, Context: ctx})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Event.Arg0)
+	}
+	return ids, it.Error()
+}
+
+// GetChannelIDs returns the IDs of the channels created on the chain in the order of their creation.
+// The IDs are unique across the ports.
+func (chain *Chain) GetChannelIDs(ctx context.Context) ([]string, error) {
+	it, err := chain.IBCHost.FilterGeneratedChannelIdentifier(&bind.FilterOpts{Start: 0, Context: ctx})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Event.Arg0)
+	}
+	return ids, it.Error()
+}
+
+// FindAcknowledgement returns the acknowledgement written for the packet received on the chain.
+// ErrAcknowledgementNotFound is returned if no acknowledgement has been written yet.
+func (chain *Chain) FindAcknowledgement(ctx context.Context, destinationPortID, destinationChannel string, sequence uint64) ([]byte, error) {
+	it, err := chain.IBCHandler.FilterWriteAcknowledgement(&bind.FilterOpts{Start: 0, Context: ctx})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	for it.Next() {
+		if ev := it.Event; ev.DestinationPortId == destinationPortID && ev.DestinationChannel == destinationChannel && ev.Sequence == sequence {
+			return ev.Acknowledgement, nil
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: destinationPortID=%v destinationChannel=%v sequence=%v", ErrAcknowledgementNotFound, destinationPortID, destinationChannel, sequence)
+}
+
+// SentPackets returns the packets sent on the chain in the range of the blocks. The logs are unpacked
+// without the bindings, whose iterator fails to unpack the SendPacket event.
+func (chain *Chain) SentPackets(ctx context.Context, opts *bind.FilterOpts) ([]channeltypes.Packet, error) {
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(opts.Start),
+		Addresses: []common.Address{chain.ContractConfig.GetIBCHandlerAddress()},
+		Topics:    [][]common.Hash{{abiSendPacket.ID}},
+	}
+	if opts.End != nil {
+		query.ToBlock = new(big.Int).SetUint64(*opts.End)
+	}
+	logs, err := chain.client.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	packets := make([]channeltypes.Packet, 0, len(logs))
+	for _, log := range logs {
+		packet, err := unpackPacket(abiSendPacket, log.Data)
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, convert.PacketFromCallData(packet))
+	}
+	return packets, nil
+}
+
+// unpackPacket unpacks the packet passed as the first argument of the event. The event is not parsed with
+// the bindings, which fail to unpack the events whose only argument is a tuple.
+func unpackPacket(event abi.Event, data []byte) (ibchandler.PacketData, error) {
+	values, err := event.Inputs.Unpack(data)
+	if err != nil {
+		return ibchandler.PacketData{}, err
+	} else if len(values) == 0 {
+		return ibchandler.PacketData{}, fmt.Errorf("no packet in the event: %v", event.Name)
+	}
+	return *abi.ConvertType(values[0], new(ibchandler.PacketData)).(*ibchandler.PacketData), nil
+}
+
+// WaitForSuccess waits for the receipt of the transaction, and returns an error if the transaction failed.
+func (chain *Chain) WaitForSuccess(ctx context.Context, tx *gethtypes.Transaction) error {
+	rc, err := chain.WaitForReceipt(ctx, tx)
+	if err != nil {
+		return err
+	} else if rc.Status() != gethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("failed to call transaction: tx=%v reason='%v'", tx.Hash().Hex(), rc.RevertReason())
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package host
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client/simulated"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+)
+
+type testContractConfig struct {
+	handler common.Address
+}
+
+func (c testContractConfig) GetIBCHostAddress() common.Address           { return common.Address{} }
+func (c testContractConfig) GetIBCHandlerAddress() common.Address        { return c.handler }
+func (c testContractConfig) GetIBCIdentifierAddress() common.Address     { return common.Address{} }
+func (c testContractConfig) GetICS20TransferBankAddress() common.Address { return common.Address{} }
+func (c testContractConfig) GetICS20BankAddress() common.Address         { return common.Address{} }
+
+// newSendPacketChain returns the chain whose IBC handler is stubbed by the code emitting the SendPacket
+// event with the arguments of the call, and reverting if any value is sent with the call.
+func newSendPacketChain(t *testing.T) (*Chain, client.GenTxOpts) {
+	// CALLVALUE PUSH1 dest JUMPI
+	// PUSH1 4 CALLDATASIZE SUB PUSH1 4 PUSH1 0 CALLDATACOPY PUSH32 topic PUSH1 4 CALLDATASIZE SUB PUSH1 0 LOG1 STOP
+	// dest: JUMPDEST PUSH1 0 DUP1 REVERT
+	code := []byte{0x34, 0x60, 0x36, 0x57, 0x60, 0x04, 0x36, 0x03, 0x60, 0x04, 0x60, 0x00, 0x37, 0x7f}
+	code = append(code, abiSendPacket.ID.Bytes()...)
+	code = append(code, 0x60, 0x04, 0x36, 0x03, 0x60, 0x00, 0xa1, 0x00, 0x5b, 0x60, 0x00, 0x80, 0xfd)
+	require.Equal(t, byte(0x5b), code[0x36])
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	handler := common.HexToAddress("0x12")
+	cl := simulated.NewClient(core.GenesisAlloc{
+		from:    {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+		handler: {Code: code, Balance: new(big.Int)},
+	}, 10_000_000, ibcclient.MockClient)
+	chain, err := NewChain(*cl, "1337", testContractConfig{handler: handler})
+	require.NoError(t, err)
+	return chain, client.MakeGenTxOpts(big.NewInt(1337), key)
+}
+
+func testPacket(sequence uint64) ibchandler.PacketData {
+	return ibchandler.PacketData{
+		Sequence:           sequence,
+		SourcePort:         "transfer",
+		SourceChannel:      "channel-0",
+		DestinationPort:    "transfer",
+		DestinationChannel: "channel-1",
+		Data:               []byte("data"),
+		TimeoutHeight:      ibchandler.HeightData{RevisionNumber: 1, RevisionHeight: 100},
+	}
+}
+
+func TestSentPackets(t *testing.T) {
+	chain, txOpts := newSendPacketChain(t)
+	ctx := context.Background()
+	for seq := uint64(1); seq <= 2; seq++ {
+		tx, err := chain.IBCHandler.SendPacket(txOpts(ctx), testPacket(seq))
+		require.NoError(t, err)
+		require.NoError(t, chain.WaitForSuccess(ctx, tx))
+	}
+
+	packets, err := chain.SentPackets(ctx, &bind.FilterOpts{Start: 0, Context: ctx})
+	require.NoError(t, err)
+	require.Len(t, packets, 2)
+	for i, packet := range packets {
+		require.Equal(t, convert.PacketFromCallData(testPacket(uint64(i+1))), packet)
+	}
+
+	// the packets sent after the end of the range are not returned
+	end := uint64(1)
+	packets, err = chain.SentPackets(ctx, &bind.FilterOpts{Start: 0, End: &end, Context: ctx})
+	require.NoError(t, err)
+	require.Len(t, packets, 1)
+}
+
+func TestFindPacket(t *testing.T) {
+	chain, txOpts := newSendPacketChain(t)
+	ctx := context.Background()
+	for seq := uint64(1); seq <= 2; seq++ {
+		tx, err := chain.IBCHandler.SendPacket(txOpts(ctx), testPacket(seq))
+		require.NoError(t, err)
+		require.NoError(t, chain.WaitForSuccess(ctx, tx))
+	}
+
+	packet, err := chain.FindPacket(ctx, "transfer", "channel-0", 2)
+	require.NoError(t, err)
+	require.Equal(t, convert.PacketFromCallData(testPacket(2)), *packet)
+	_, err = chain.FindPacket(ctx, "transfer", "channel-1", 2)
+	require.Error(t, err)
+	_, err = chain.FindPacket(ctx, "transfer", "channel-0", 3)
+	require.Error(t, err)
+}
+
+func TestCheckPacketTimeout(t *testing.T) {
+	chain, _ := newSendPacketChain(t)
+	ctx := context.Background()
+	// the simulated chain mines a block whenever the latest block is queried, as CheckPacketTimeout does
+	block, err := chain.Client().BlockByNumber(ctx, nil)
+	require.NoError(t, err)
+	next := block.NumberU64() + 2
+
+	// the packet is received in the block following the latest one at the earliest
+	packet := convert.PacketFromCallData(testPacket(1))
+	packet.TimeoutHeight.RevisionHeight = next + 1
+	require.NoError(t, chain.CheckPacketTimeout(ctx, packet))
+	next++
+	packet.TimeoutHeight.RevisionHeight = next
+	require.ErrorIs(t, chain.CheckPacketTimeout(ctx, packet), ErrPacketTimedOut)
+}
@@ -0,0 +1,108 @@
+package host
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	ibft2clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/ibft2"
+	mockclienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/mock"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+)
+
+// GetIBFT2ClientState returns the state of the IBFT2 client on the chain.
+func (chain *Chain) GetIBFT2ClientState(ctx context.Context, clientID string) (*ibft2clienttypes.ClientState, error) {
+	var cs ibft2clienttypes.ClientState
+	if err := chain.getClientState(ctx, clientID, &cs); err != nil {
+		return nil, err
+	}
+	return &cs, nil
+}
+
+// GetMockClientState returns the state of the mock client on the chain.
+func (chain *Chain) GetMockClientState(ctx context.Context, clientID string) (*mockclienttypes.ClientState, error) {
+	var cs mockclienttypes.ClientState
+	if err := chain.getClientState(ctx, clientID, &cs); err != nil {
+		return nil, err
+	}
+	return &cs, nil
+}
+
+func (chain *Chain) getClientState(ctx context.Context, clientID string, cs proto.Message) error {
+	bz, found, err := chain.IBCHost.GetClientState(chain.CallOpts(ctx), clientID)
+	if err != nil {
+		return err
+	} else if !found {
+		return fmt.Errorf("client not found: %v", clientID)
+	}
+	return convert.UnmarshalWithAny(bz, cs)
+}
+
+// ConstructMsgUpdateClient constructs the message updating the client on the chain to the block of the
+// counterparty chain at the given number, or to the latest block if bn is nil. The header is built for
+// the type of the client, and the IBFT2 header is verified against the latest height of the client.
+func (chain *Chain) ConstructMsgUpdateClient(ctx context.Context, counterparty *Chain, clientID string, bn *big.Int) (ibchandler.IBCMsgsMsgUpdateClient, error) {
+	var msg ibchandler.IBCMsgsMsgUpdateClient
+	clientType, err := chain.IBCHost.GetClientType(chain.CallOpts(ctx), clientID)
+	if err != nil {
+		return msg, err
+	}
+	var header []byte
+	switch clientType {
+	case ibcclient.BesuIBFT2Client:
+		cs, err := chain.GetIBFT2ClientState(ctx, clientID)
+		if err != nil {
+			return msg, err
+		}
+		state, err := counterparty.Client().GetIBFT2ContractState(ctx, counterparty.IBCHostAddress(), nil, bn)
+		if err != nil {
+			return msg, err
+		}
+		ibft2State := state.(client.IBFT2ContractState)
+		header, err = convert.MarshalWithAny(&ibft2clienttypes.Header{
+			BesuHeaderRlp:     ibft2State.SealingHeaderRLP(),
+			Seals:             ibft2State.CommitSeals,
+			TrustedHeight:     cs.LatestHeight,
+			AccountStateProof: ibft2State.ETHProof().AccountProofRLP,
+		})
+		if err != nil {
+			return msg, err
+		}
+	case ibcclient.MockClient:
+		state, err := counterparty.Client().GetMockContractState(ctx, counterparty.IBCHostAddress(), nil, bn)
+		if err != nil {
+			return msg, err
+		}
+		header, err = convert.MarshalWithAny(&mockclienttypes.Header{
+			Height:    state.Header().Number.Uint64(),
+			Timestamp: state.Header().Time,
+		})
+		if err != nil {
+			return msg, err
+		}
+	default:
+		return msg, fmt.Errorf("unknown client type: '%v'", clientType)
+	}
+	return ibchandler.IBCMsgsMsgUpdateClient{ClientId: clientID, Header: header}, nil
+}
+
+// UpdateClient updates the client on the chain to the block of the counterparty chain at the given number,
+// or to the latest block if bn is nil. The transaction is sent with the options, and an error is returned
+// if it fails.
+func (chain *Chain) UpdateClient(ctx context.Context, opts *bind.TransactOpts, counterparty *Chain, clientID string, bn *big.Int) error {
+	msg, err := chain.ConstructMsgUpdateClient(ctx, counterparty, clientID, bn)
+	if err != nil {
+		return err
+	}
+	tx, err := chain.IBCHandler.UpdateClient(opts, msg)
+	if err != nil {
+		return err
+	}
+	return chain.WaitForSuccess(ctx, tx)
+}
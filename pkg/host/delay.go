@@ -0,0 +1,83 @@
+package host
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// delayPollInterval is the interval to poll the latest block while waiting for a delay period.
+const delayPollInterval = 500 * time.Millisecond
+
+// QueryDelayPeriodEnd returns the time (in nanoseconds) and the block height of the chain from which a proof
+// at the given height of the counterparty chain is accepted on the channel.
+//
+// These mirror validateDelayPeriod of IBFT2Client: the processed time and height of the consensus state
+// plus the connection's delay period and its block delay derived from the expected time per block. The time
+// is compared with the block timestamp in nanoseconds, but the client adds the delay period in nanoseconds
+// to the processed time as is, which is the block timestamp in seconds.
+func (chain *Chain) QueryDelayPeriodEnd(ctx context.Context, portID, channelID string, proofHeight uint64) (uint64, uint64, error) {
+	channel, err := chain.getChannel(ctx, portID, channelID)
+	if err != nil {
+		return 0, 0, err
+	}
+	opts := chain.CallOpts(ctx)
+	conn, found, err := chain.IBCHost.GetConnection(opts, channel.ConnectionHops[0])
+	if err != nil {
+		return 0, 0, err
+	} else if !found {
+		return 0, 0, fmt.Errorf("connection not found: %v", channel.ConnectionHops[0])
+	}
+
+	processedTime, found, err := chain.IBCHost.GetProcessedTime(opts, conn.ClientId, proofHeight)
+	if err != nil {
+		return 0, 0, err
+	} else if !found {
+		return 0, 0, fmt.Errorf("processed time not found: clientID=%v height=%v", conn.ClientId, proofHeight)
+	}
+	processedHeight, found, err := chain.IBCHost.GetProcessedHeight(opts, conn.ClientId, proofHeight)
+	if err != nil {
+		return 0, 0, err
+	} else if !found {
+		return 0, 0, fmt.Errorf("processed height not found: clientID=%v height=%v", conn.ClientId, proofHeight)
+	}
+	expectedTimePerBlock, err := chain.IBCHost.GetExpectedTimePerBlock(opts)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var blockDelay uint64
+	if expectedTimePerBlock != 0 {
+		blockDelay = (conn.DelayPeriod + expectedTimePerBlock - 1) / expectedTimePerBlock
+	}
+	validTime := processedTime.Uint64() + conn.DelayPeriod
+	validHeight := processedHeight.Uint64() + blockDelay
+	return validTime, validHeight, nil
+}
+
+// WaitForDelayPeriod waits until the latest block of the chain reaches both the time and the height
+// returned by QueryDelayPeriodEnd, so that a proof at the given height is accepted by any subsequent block.
+func (chain *Chain) WaitForDelayPeriod(ctx context.Context, portID, channelID string, proofHeight uint64) error {
+	validTime, validHeight, err := chain.QueryDelayPeriodEnd(ctx, portID, channelID, proofHeight)
+	if err != nil {
+		return err
+	}
+	for {
+		block, err := chain.client.BlockByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if block.Time()*uint64(time.Second) >= validTime && block.NumberU64() >= validHeight {
+			return nil
+		}
+		wait := delayPollInterval
+		if now := uint64(time.Now().UnixNano()); now < validTime {
+			wait = time.Duration(validTime - now)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
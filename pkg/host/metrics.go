@@ -0,0 +1,122 @@
+package host
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ics20bank"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ics20transferbank"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/simpletoken"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/metrics"
+)
+
+// unknownMsgType is the message type of the transactions calling no method of the contracts.
+const unknownMsgType = "unknown"
+
+var (
+	// contractABIs are the ABIs to find the method called by a transaction.
+	contractABIs []abi.ABI
+
+	abiRecvPacket,
+	abiAcknowledgePacket abi.Event
+)
+
+func init() {
+	for _, def := range []string{
+		ibchandler.IbchandlerABI,
+		ics20transferbank.Ics20transferbankABI,
+		ics20bank.Ics20bankABI,
+		simpletoken.SimpletokenABI,
+	} {
+		parsed, err := abi.JSON(strings.NewReader(def))
+		if err != nil {
+			panic(err)
+		}
+		contractABIs = append(contractABIs, parsed)
+	}
+	handlerABI := contractABIs[0]
+	abiRecvPacket = handlerABI.Events["RecvPacket"]
+	abiAcknowledgePacket = handlerABI.Events["AcknowledgePacket"]
+}
+
+// messageType returns the name of the contract method called by the transaction.
+func messageType(tx *gethtypes.Transaction) string {
+	data := tx.Data()
+	if len(data) < 4 {
+		return unknownMsgType
+	}
+	for _, parsed := range contractABIs {
+		if method, err := parsed.MethodById(data[:4]); err == nil {
+			return method.Name
+		}
+	}
+	return unknownMsgType
+}
+
+// WaitForReceipt waits for the receipt of the transaction, and records the metrics of the transaction and
+// the packet events in its logs. The confirmation time is measured from the start of waiting for the receipt,
+// which is expected to immediately follow sending the transaction.
+func (chain *Chain) WaitForReceipt(ctx context.Context, tx *gethtypes.Transaction) (client.Receipt, error) {
+	start := time.Now()
+	rc, err := chain.client.WaitForReceiptAndGet(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	r := metrics.GetRecorder()
+	succeeded := rc.Status() == gethtypes.ReceiptStatusSuccessful
+	r.ObserveTx(chain.chainID, messageType(tx), rc.GasUsed(), time.Since(start), succeeded)
+	if succeeded {
+		for _, ev := range chain.PacketEvents(rc.Logs()) {
+			switch ev.Event {
+			case metrics.PacketReceived:
+				r.IncPacket(chain.chainID, ev.Event, ev.Packet.DestinationPort, ev.Packet.DestinationChannel)
+			default:
+				r.IncPacket(chain.chainID, ev.Event, ev.Packet.SourcePort, ev.Packet.SourceChannel)
+			}
+		}
+	}
+	return rc, nil
+}
+
+// PacketEvent is an event of a packet emitted by IBCHandler.
+type PacketEvent struct {
+	Event  metrics.PacketEvent
+	Packet channeltypes.Packet
+}
+
+// PacketEvents returns the packet events in the logs of IBCHandler.
+func (chain *Chain) PacketEvents(logs []*gethtypes.Log) []PacketEvent {
+	handler := chain.ContractConfig.GetIBCHandlerAddress()
+	var events []PacketEvent
+	for _, log := range logs {
+		if log.Address != handler || len(log.Topics) == 0 {
+			continue
+		}
+		var (
+			event    metrics.PacketEvent
+			abiEvent abi.Event
+		)
+		switch log.Topics[0] {
+		case abiSendPacket.ID:
+			event, abiEvent = metrics.PacketSent, abiSendPacket
+		case abiRecvPacket.ID:
+			event, abiEvent = metrics.PacketReceived, abiRecvPacket
+		case abiAcknowledgePacket.ID:
+			event, abiEvent = metrics.PacketAcknowledged, abiAcknowledgePacket
+		default:
+			continue
+		}
+		if packet, err := unpackPacket(abiEvent, log.Data); err == nil {
+			events = append(events, PacketEvent{event, convert.PacketFromCallData(packet)})
+		}
+	}
+	return events
+}
@@ -0,0 +1,90 @@
+package host
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/metrics"
+)
+
+type txObservation struct {
+	chainID   string
+	msgType   string
+	gasUsed   uint64
+	succeeded bool
+}
+
+type packetObservation struct {
+	chainID   string
+	event     metrics.PacketEvent
+	portID    string
+	channelID string
+}
+
+type testRecorder struct {
+	metrics.NopRecorder
+	mu      sync.Mutex
+	txs     []txObservation
+	packets []packetObservation
+}
+
+func (r *testRecorder) ObserveTx(chainID, msgType string, gasUsed uint64, _ time.Duration, succeeded bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.txs = append(r.txs, txObservation{chainID, msgType, gasUsed, succeeded})
+}
+
+func (r *testRecorder) IncPacket(chainID string, event metrics.PacketEvent, portID, channelID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.packets = append(r.packets, packetObservation{chainID, event, portID, channelID})
+}
+
+func TestWaitForReceipt(t *testing.T) {
+	chain, txOpts := newSendPacketChain(t)
+	cl := chain.Client()
+	handler := chain.ContractConfig.GetIBCHandlerAddress()
+	r := &testRecorder{}
+	metrics.SetRecorder(r)
+	defer metrics.SetRecorder(nil)
+
+	ctx := context.Background()
+	packet := testPacket(1)
+	send := func(value int64) (*gethtypes.Transaction, client.Receipt) {
+		opts := txOpts(ctx)
+		opts.Value = big.NewInt(value)
+		tx, err := chain.IBCHandler.SendPacket(opts, packet)
+		require.NoError(t, err)
+		rc, err := chain.WaitForReceipt(ctx, tx)
+		require.NoError(t, err)
+		return tx, rc
+	}
+
+	// the packet sent by a successful transaction is counted
+	_, rc := send(0)
+	require.Equal(t, gethtypes.ReceiptStatusSuccessful, rc.Status())
+	require.Equal(t, []txObservation{{"1337", "sendPacket", rc.GasUsed(), true}}, r.txs)
+	require.Equal(t, []packetObservation{{"1337", metrics.PacketSent, "transfer", "channel-0"}}, r.packets)
+
+	// the failed transaction is observed, but no packet is counted
+	_, rc = send(1)
+	require.Equal(t, gethtypes.ReceiptStatusFailed, rc.Status())
+	require.Equal(t, txObservation{"1337", "sendPacket", rc.GasUsed(), false}, r.txs[1])
+	require.Len(t, r.packets, 1)
+
+	// the transaction calling no method of the contracts is observed with the unknown type
+	tx, err := bind.NewBoundContract(handler, abi.ABI{}, cl, cl, cl).RawTransact(txOpts(ctx), []byte{0x01})
+	require.NoError(t, err)
+	_, err = chain.WaitForReceipt(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, unknownMsgType, r.txs[2].msgType)
+}
@@ -0,0 +1,369 @@
+package host
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchost"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+)
+
+// ErrPacketTimedOut is returned when a packet can no longer be received on its destination chain.
+var ErrPacketTimedOut = errors.New("packet timed out")
+
+// UnrelayedSequences keeps track of the packet sequences on a channel that
+// still need to be relayed to the counterparty chain or back from it.
+type UnrelayedSequences struct {
+	// Packets are the sequences that have a packet commitment on the source
+	// chain but have not been received on the destination chain yet.
+	Packets []uint64
+	// Acknowledgements are the sequences whose acknowledgement has been written
+	// on the destination chain but not acknowledged on the source chain yet.
+	Acknowledgements []uint64
+}
+
+// QueryUnrelayedSequences returns the packets sent from the channel on the chain and the acknowledgements
+// written on its counterparty channel that are pending.
+func (chain *Chain) QueryUnrelayedSequences(ctx context.Context, counterparty *Chain, portID, channelID string) (*UnrelayedSequences, error) {
+	channel, err := chain.getChannel(ctx, portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	counterpartyPortID, counterpartyChannelID := channel.Counterparty.PortId, channel.Counterparty.ChannelId
+	opts := chain.CallOpts(ctx)
+	counterpartyOpts := counterparty.CallOpts(ctx)
+
+	nextSequenceSend, err := chain.IBCHost.GetNextSequenceSend(opts, portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	nextSequenceRecv, err := counterparty.IBCHost.GetNextSequenceRecv(counterpartyOpts, counterpartyPortID, counterpartyChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var seqs UnrelayedSequences
+	for seq := uint64(1); seq < nextSequenceSend; seq++ {
+		// the commitment is deleted once the packet has been acknowledged
+		if _, found, err := chain.IBCHost.GetPacketCommitment(opts, portID, channelID, seq); err != nil {
+			return nil, err
+		} else if !found {
+			continue
+		}
+
+		var received bool
+		switch channeltypes.Channel_Order(channel.Ordering) {
+		case channeltypes.ORDERED:
+			received = seq < nextSequenceRecv
+		case channeltypes.UNORDERED:
+			if received, err = counterparty.IBCHost.HasPacketReceipt(counterpartyOpts, counterpartyPortID, counterpartyChannelID, seq); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown channel ordering: %v", channel.Ordering)
+		}
+		if !received {
+			seqs.Packets = append(seqs.Packets, seq)
+			continue
+		}
+
+		if _, found, err := counterparty.IBCHost.GetPacketAcknowledgementCommitment(counterpartyOpts, counterpartyPortID, counterpartyChannelID, seq); err != nil {
+			return nil, err
+		} else if found {
+			seqs.Acknowledgements = append(seqs.Acknowledgements, seq)
+		}
+	}
+	return &seqs, nil
+}
+
+// FindPacket returns the packet sent from the channel on the chain with the sequence.
+func (chain *Chain) FindPacket(ctx context.Context, sourcePortID, sourceChannel string, sequence uint64) (*channeltypes.Packet, error) {
+	packets, err := chain.SentPackets(ctx, &bind.FilterOpts{Start: 0, Context: ctx})
+	if err != nil {
+		return nil, err
+	}
+	for _, packet := range packets {
+		if packet.SourcePort == sourcePortID && packet.SourceChannel == sourceChannel && packet.Sequence == sequence {
+			return &packet, nil
+		}
+	}
+	return nil, fmt.Errorf("packet not found: sourcePortID=%v sourceChannel=%v sequence=%v", sourcePortID, sourceChannel, sequence)
+}
+
+// CheckPacketTimeout returns ErrPacketTimedOut if the packet can no longer be received on the chain.
+// The packet is received in the next block at the earliest, so its timeout is checked against the height
+// of that block, and the timestamp of the latest block as the lower bound of the next one.
+func (chain *Chain) CheckPacketTimeout(ctx context.Context, packet channeltypes.Packet) error {
+	block, err := chain.client.BlockByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	header := block.Header()
+	height := channeltypes.NewHeight(channeltypes.ParseChainID(chain.chainID), header.Number.Uint64()+1)
+	if packet.IsTimedOut(height, header.Time) {
+		return fmt.Errorf("%w: sequence=%v timeoutHeight=%v timeoutTimestamp=%v height=%v timestamp=%v",
+			ErrPacketTimedOut, packet.Sequence, packet.TimeoutHeight.Format(), packet.TimeoutTimestamp, height.Format(), header.Time)
+	}
+	return nil
+}
+
+// Proof is the proof of a commitment stored in IBCHost, which is verified by the client of the counterparty
+// chain at the height.
+type Proof struct {
+	Height uint64
+	Data   []byte
+}
+
+// QueryProof returns the proof of the storage slot of IBCHost at the latest height of the client tracking the
+// chain on the counterparty chain, so that the client has the consensus state to verify the proof with.
+func (chain *Chain) QueryProof(ctx context.Context, counterparty *Chain, counterpartyClientID string, slot [32]byte) (*Proof, error) {
+	height, err := counterparty.clientLatestHeight(ctx, counterpartyClientID)
+	if err != nil {
+		return nil, err
+	}
+	key := []byte("0x" + hex.EncodeToString(slot[:]))
+	state, err := chain.client.GetContractState(ctx, chain.IBCHostAddress(), [][]byte{key}, new(big.Int).SetUint64(height))
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{Height: height, Data: state.ETHProof().StorageProofRLP[0]}, nil
+}
+
+// clientLatestHeight returns the latest height of the client on the chain.
+func (chain *Chain) clientLatestHeight(ctx context.Context, clientID string) (uint64, error) {
+	clientType, err := chain.IBCHost.GetClientType(chain.CallOpts(ctx), clientID)
+	if err != nil {
+		return 0, err
+	}
+	switch clientType {
+	case ibcclient.BesuIBFT2Client:
+		cs, err := chain.GetIBFT2ClientState(ctx, clientID)
+		if err != nil {
+			return 0, err
+		}
+		return cs.LatestHeight, nil
+	case ibcclient.MockClient:
+		cs, err := chain.GetMockClientState(ctx, clientID)
+		if err != nil {
+			return 0, err
+		}
+		return cs.LatestHeight, nil
+	default:
+		return 0, fmt.Errorf("unknown client type: '%v'", clientType)
+	}
+}
+
+// RecvPacket receives the packet sent from the counterparty chain on the chain. The packet commitment is
+// proven to the client of the channel, which is expected to have been updated after the packet was sent,
+// and the delay period of the channel's connection is waited for if the client validates it.
+// ErrPacketTimedOut is returned if the packet can no longer be received.
+func (chain *Chain) RecvPacket(ctx context.Context, opts *bind.TransactOpts, counterparty *Chain, packet channeltypes.Packet) error {
+	if err := chain.CheckPacketTimeout(ctx, packet); err != nil {
+		return err
+	}
+	clientID, clientType, err := chain.ChannelClient(ctx, packet.DestinationPort, packet.DestinationChannel)
+	if err != nil {
+		return err
+	}
+	slot, err := counterparty.IBCIdentifier.PacketCommitmentSlot(counterparty.CallOpts(ctx), packet.SourcePort, packet.SourceChannel, packet.Sequence)
+	if err != nil {
+		return err
+	}
+	proof, err := counterparty.QueryProof(ctx, chain, clientID, slot)
+	if err != nil {
+		return err
+	}
+	switch clientType {
+	case ibcclient.MockClient:
+		// the mock client verifies the commitment itself as the proof
+		proof.Data = channeltypes.CommitPacket(packet)
+	case ibcclient.BesuIBFT2Client:
+		if err := chain.WaitForDelayPeriod(ctx, packet.DestinationPort, packet.DestinationChannel, proof.Height); err != nil {
+			return err
+		}
+	}
+	tx, err := chain.IBCHandler.RecvPacket(opts, ibchandler.IBCMsgsMsgPacketRecv{
+		Packet:      convert.PacketToCallData(packet),
+		Proof:       proof.Data,
+		ProofHeight: proof.Height,
+	})
+	if err != nil {
+		return err
+	}
+	if err := chain.WaitForSuccess(ctx, tx); err != nil {
+		return fmt.Errorf("failed to receive packet: sequence=%v: %w", packet.Sequence, err)
+	}
+	return nil
+}
+
+// AcknowledgePacket acknowledges the packet sent from the chain with the acknowledgement written on the
+// counterparty chain. The acknowledgement commitment is proven to the client of the channel, which is
+// expected to have been updated after the acknowledgement was written, and the delay period of the
+// channel's connection is waited for if the client validates it.
+func (chain *Chain) AcknowledgePacket(ctx context.Context, opts *bind.TransactOpts, counterparty *Chain, packet channeltypes.Packet, acknowledgement []byte) error {
+	clientID, clientType, err := chain.ChannelClient(ctx, packet.SourcePort, packet.SourceChannel)
+	if err != nil {
+		return err
+	}
+	slot, err := counterparty.IBCIdentifier.PacketAcknowledgementCommitmentSlot(counterparty.CallOpts(ctx), packet.DestinationPort, packet.DestinationChannel, packet.Sequence)
+	if err != nil {
+		return err
+	}
+	proof, err := counterparty.QueryProof(ctx, chain, clientID, slot)
+	if err != nil {
+		return err
+	}
+	switch clientType {
+	case ibcclient.MockClient:
+		proof.Data = channeltypes.CommitAcknowledgement(acknowledgement)
+	case ibcclient.BesuIBFT2Client:
+		if err := chain.WaitForDelayPeriod(ctx, packet.SourcePort, packet.SourceChannel, proof.Height); err != nil {
+			return err
+		}
+	}
+	tx, err := chain.IBCHandler.AcknowledgePacket(opts, ibchandler.IBCMsgsMsgPacketAcknowledgement{
+		Packet:          convert.PacketToCallData(packet),
+		Acknowledgement: acknowledgement,
+		Proof:           proof.Data,
+		ProofHeight:     proof.Height,
+	})
+	if err != nil {
+		return err
+	}
+	if err := chain.WaitForSuccess(ctx, tx); err != nil {
+		return fmt.Errorf("failed to acknowledge packet: sequence=%v: %w", packet.Sequence, err)
+	}
+	return nil
+}
+
+// ClearedPackets is the result of clearing the packets of a channel.
+type ClearedPackets struct {
+	// Received are the sequences of the packets received on the counterparty chain.
+	Received []uint64
+	// Acknowledged are the sequences of the packets acknowledged on the chain.
+	Acknowledged []uint64
+	// TimedOut are the sequences of the packets skipped since they can no longer be received,
+	// which can only be timed out on the chain.
+	TimedOut []uint64
+}
+
+func (c ClearedPackets) String() string {
+	return fmt.Sprintf("received=%v acknowledged=%v timedOut=%v", c.Received, c.Acknowledged, c.TimedOut)
+}
+
+// ClearPackets relays the packets sent from the channel on the chain that have not been received on the
+// counterparty chain yet except timed-out ones, and then the acknowledgements written on the counterparty
+// chain that have not been acknowledged on the chain. Both clients of the channel are updated first so that
+// the proofs of the pending commitments can be verified. The transactions on the chain are sent with opts,
+// and the ones on the counterparty chain with counterpartyOpts.
+func (chain *Chain) ClearPackets(
+	ctx context.Context,
+	opts *bind.TransactOpts,
+	counterparty *Chain,
+	counterpartyOpts *bind.TransactOpts,
+	portID, channelID string,
+) (*ClearedPackets, error) {
+	channel, err := chain.getChannel(ctx, portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	clientID, _, err := chain.ChannelClient(ctx, portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	counterpartyClientID, _, err := counterparty.ChannelClient(ctx, channel.Counterparty.PortId, channel.Counterparty.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	if err := counterparty.UpdateClient(ctx, counterpartyOpts, chain, counterpartyClientID, nil); err != nil {
+		return nil, err
+	}
+	if err := chain.UpdateClient(ctx, opts, counterparty, clientID, nil); err != nil {
+		return nil, err
+	}
+
+	var cleared ClearedPackets
+	seqs, err := chain.QueryUnrelayedSequences(ctx, counterparty, portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	for _, seq := range seqs.Packets {
+		packet, err := chain.FindPacket(ctx, portID, channelID, seq)
+		if err != nil {
+			return nil, err
+		}
+		if err := counterparty.RecvPacket(ctx, counterpartyOpts, chain, *packet); errors.Is(err, ErrPacketTimedOut) {
+			cleared.TimedOut = append(cleared.TimedOut, seq)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		cleared.Received = append(cleared.Received, seq)
+	}
+
+	// query again to include the acknowledgements written by the above packets
+	seqs, err = chain.QueryUnrelayedSequences(ctx, counterparty, portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if len(seqs.Acknowledgements) > 0 && len(cleared.Received) > 0 {
+		// the acknowledgements written by the above packets are proven at the latest block of the counterparty chain
+		if err := chain.UpdateClient(ctx, opts, counterparty, clientID, nil); err != nil {
+			return nil, err
+		}
+	}
+	for _, seq := range seqs.Acknowledgements {
+		packet, err := chain.FindPacket(ctx, portID, channelID, seq)
+		if err != nil {
+			return nil, err
+		}
+		ack, err := counterparty.FindAcknowledgement(ctx, packet.DestinationPort, packet.DestinationChannel, seq)
+		if err != nil {
+			return nil, err
+		}
+		if err := chain.AcknowledgePacket(ctx, opts, counterparty, *packet, ack); err != nil {
+			return nil, err
+		}
+		cleared.Acknowledged = append(cleared.Acknowledged, seq)
+	}
+	return &cleared, nil
+}
+
+func (chain *Chain) getChannel(ctx context.Context, portID, channelID string) (ibchost.ChannelData, error) {
+	channel, found, err := chain.IBCHost.GetChannel(chain.CallOpts(ctx), portID, channelID)
+	if err != nil {
+		return channel, err
+	} else if !found {
+		return channel, fmt.Errorf("channel not found: portID=%v channelID=%v", portID, channelID)
+	}
+	return channel, nil
+}
+
+// ChannelClient returns the ID and the type of the client of the connection the channel is on.
+func (chain *Chain) ChannelClient(ctx context.Context, portID, channelID string) (string, string, error) {
+	channel, err := chain.getChannel(ctx, portID, channelID)
+	if err != nil {
+		return "", "", err
+	} else if len(channel.ConnectionHops) != 1 {
+		return "", "", fmt.Errorf("channel must have exactly one connection hop: portID=%v channelID=%v hops=%v", portID, channelID, channel.ConnectionHops)
+	}
+	conn, found, err := chain.IBCHost.GetConnection(chain.CallOpts(ctx), channel.ConnectionHops[0])
+	if err != nil {
+		return "", "", err
+	} else if !found {
+		return "", "", fmt.Errorf("connection not found: %v", channel.ConnectionHops[0])
+	}
+	clientType, err := chain.IBCHost.GetClientType(chain.CallOpts(ctx), conn.ClientId)
+	if err != nil {
+		return "", "", err
+	}
+	return conn.ClientId, clientType, nil
+}
@@ -0,0 +1,151 @@
+// Package denom provides the ICS-20 denomination trace utilities.
+//
+// A token sent through a channel to a chain which is not the source of the token is received as a
+// voucher whose denomination is prefixed with the destination port and channel, such as
+// `{port}/{channel}/{baseDenom}`. The prefix is removed when the voucher is sent back through the
+// same channel. A denomination therefore records the path the token has travelled, most recent hop first.
+package denom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IBCDenomPrefix is the prefix of the hashed denominations used by Cosmos chains.
+const IBCDenomPrefix = "ibc"
+
+// channelIDRegex matches a channel identifier generated by IBCHost or ibc-go.
+var channelIDRegex = regexp.MustCompile(`^channel-[0-9]+$`)
+
+// Hop is a port and channel through which a token has been received.
+type Hop struct {
+	PortID    string
+	ChannelID string
+}
+
+// String returns the hop as `{port}/{channel}`.
+func (h Hop) String() string {
+	return fmt.Sprintf("%v/%v", h.PortID, h.ChannelID)
+}
+
+// Trace is a denomination split into the path of hops and the base denomination.
+type Trace struct {
+	// Path is the chain of `{port}/{channel}` hops separated by "/". It is empty for a native token.
+	Path string
+	// BaseDenom is the denomination on the chain the token is native to.
+	BaseDenom string
+}
+
+// Prefix returns the prefix added to the denomination of a token received through the port and channel.
+func Prefix(portID, channelID string) string {
+	return fmt.Sprintf("%v/%v/", portID, channelID)
+}
+
+// ParseTrace parses a denomination such as `{port}/{channel}/.../{baseDenom}`. Leading pairs of
+// port and channel identifiers are taken as the path, so a base denomination may contain "/".
+func ParseTrace(rawDenom string) Trace {
+	parts := strings.Split(rawDenom, "/")
+	if len(parts) <= 2 {
+		return Trace{BaseDenom: rawDenom}
+	}
+	var i int
+	for ; i+2 < len(parts); i += 2 {
+		if !channelIDRegex.MatchString(parts[i+1]) {
+			break
+		}
+	}
+	return Trace{
+		Path:      strings.Join(parts[:i], "/"),
+		BaseDenom: strings.Join(parts[i:], "/"),
+	}
+}
+
+// FullPath returns the full denomination `{path}/{baseDenom}`, which is the denomination in ICS20Bank.
+func (t Trace) FullPath() string {
+	if t.Path == "" {
+		return t.BaseDenom
+	}
+	return t.Path + "/" + t.BaseDenom
+}
+
+// IsNative returns true if the token is native to the chain holding it.
+func (t Trace) IsNative() bool {
+	return t.Path == ""
+}
+
+// Hops returns the hops of the path, the most recent one first.
+func (t Trace) Hops() []Hop {
+	if t.Path == "" {
+		return nil
+	}
+	parts := strings.Split(t.Path, "/")
+	hops := make([]Hop, 0, len(parts)/2)
+	for i := 0; i+1 < len(parts); i += 2 {
+		hops = append(hops, Hop{PortID: parts[i], ChannelID: parts[i+1]})
+	}
+	return hops
+}
+
+// Wrap returns the trace of the token after it is received through the port and channel.
+func (t Trace) Wrap(portID, channelID string) Trace {
+	path := Hop{PortID: portID, ChannelID: channelID}.String()
+	if t.Path != "" {
+		path = path + "/" + t.Path
+	}
+	return Trace{Path: path, BaseDenom: t.BaseDenom}
+}
+
+// Unwind returns the trace of the token after it is sent back through the port and channel it was
+// most recently received through. false is returned if the token was not received through them.
+func (t Trace) Unwind(portID, channelID string) (Trace, bool) {
+	hops := t.Hops()
+	if len(hops) == 0 || hops[0].PortID != portID || hops[0].ChannelID != channelID {
+		return t, false
+	}
+	path := strings.TrimPrefix(t.Path, hops[0].String())
+	return Trace{Path: strings.TrimPrefix(path, "/"), BaseDenom: t.BaseDenom}, true
+}
+
+// UnwindAll returns the traces of the token while it is sent back hop by hop to the chain it is native to.
+// The first element is the trace itself and the last one is the native token.
+func (t Trace) UnwindAll() []Trace {
+	traces := []Trace{t}
+	for _, hop := range t.Hops() {
+		t, _ = t.Unwind(hop.PortID, hop.ChannelID)
+		traces = append(traces, t)
+	}
+	return traces
+}
+
+// Hash returns the SHA-256 hash of the full denomination.
+func (t Trace) Hash() []byte {
+	h := sha256.Sum256([]byte(t.FullPath()))
+	return h[:]
+}
+
+// IBCDenom returns the denomination `ibc/{hash}` used by Cosmos chains for a voucher,
+// or the base denomination for a native token.
+func (t Trace) IBCDenom() string {
+	if t.IsNative() {
+		return t.BaseDenom
+	}
+	return fmt.Sprintf("%s/%s", IBCDenomPrefix, strings.ToUpper(hex.EncodeToString(t.Hash())))
+}
+
+// IsReturning returns true if the denomination is sent back to the chain it came from when it is sent
+// from the source port and channel. The voucher is burned rather than escrowed in that case.
+func IsReturning(sourcePortID, sourceChannelID, denom string) bool {
+	return strings.HasPrefix(denom, Prefix(sourcePortID, sourceChannelID))
+}
+
+// ReceivedDenom returns the denomination of a token sent from the source port and channel
+// as it is credited on the chain of the destination port and channel.
+func ReceivedDenom(sourcePortID, sourceChannelID, destinationPortID, destinationChannelID, denom string) string {
+	if IsReturning(sourcePortID, sourceChannelID, denom) {
+		return strings.TrimPrefix(denom, Prefix(sourcePortID, sourceChannelID))
+	}
+	return Prefix(destinationPortID, destinationChannelID) + denom
+}
@@ -0,0 +1,64 @@
+package denom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrace(t *testing.T) {
+	var cases = []struct {
+		denom    string
+		expected Trace
+	}{
+		{"uatom", Trace{BaseDenom: "uatom"}},
+		{"0xabcd", Trace{BaseDenom: "0xabcd"}},
+		{"transfer/channel-0/uatom", Trace{Path: "transfer/channel-0", BaseDenom: "uatom"}},
+		{"transfer/channel-1/transfer/channel-0/uatom", Trace{Path: "transfer/channel-1/transfer/channel-0", BaseDenom: "uatom"}},
+		{"transfer/channel-0/gamm/pool/1", Trace{Path: "transfer/channel-0", BaseDenom: "gamm/pool/1"}},
+		{"gamm/pool/1", Trace{BaseDenom: "gamm/pool/1"}},
+		{"transfer/channel-0", Trace{BaseDenom: "transfer/channel-0"}},
+	}
+
+	for i, c := range cases {
+		trace := ParseTrace(c.denom)
+		require.Equal(t, c.expected, trace, "case %v", i)
+		require.Equal(t, c.denom, trace.FullPath(), "case %v", i)
+		require.Equal(t, c.expected.Path == "", trace.IsNative(), "case %v", i)
+	}
+}
+
+func TestTraceHops(t *testing.T) {
+	trace := ParseTrace("uatom").Wrap("transfer", "channel-0").Wrap("transfer", "channel-1")
+	require.Equal(t, "transfer/channel-1/transfer/channel-0/uatom", trace.FullPath())
+	require.Equal(t, []Hop{{"transfer", "channel-1"}, {"transfer", "channel-0"}}, trace.Hops())
+
+	_, ok := trace.Unwind("transfer", "channel-0")
+	require.False(t, ok)
+	unwound, ok := trace.Unwind("transfer", "channel-1")
+	require.True(t, ok)
+	require.Equal(t, "transfer/channel-0/uatom", unwound.FullPath())
+
+	traces := trace.UnwindAll()
+	require.Len(t, traces, 3)
+	require.Equal(t, trace, traces[0])
+	require.Equal(t, unwound, traces[1])
+	require.Equal(t, Trace{BaseDenom: "uatom"}, traces[2])
+}
+
+func TestIBCDenom(t *testing.T) {
+	require.Equal(t, "uatom", ParseTrace("uatom").IBCDenom())
+	require.Equal(t, "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2", ParseTrace("transfer/channel-0/uatom").IBCDenom())
+}
+
+func TestReceivedDenom(t *testing.T) {
+	// the token is sent from its source chain
+	require.False(t, IsReturning("transfer", "channel-0", "0xabcd"))
+	require.Equal(t, "transfer/channel-1/0xabcd", ReceivedDenom("transfer", "channel-0", "transfer", "channel-1", "0xabcd"))
+	// the voucher is sent back to the source chain
+	require.True(t, IsReturning("transfer", "channel-1", "transfer/channel-1/0xabcd"))
+	require.Equal(t, "0xabcd", ReceivedDenom("transfer", "channel-1", "transfer", "channel-0", "transfer/channel-1/0xabcd"))
+	// the voucher is forwarded to another chain
+	require.False(t, IsReturning("transfer", "channel-2", "transfer/channel-1/0xabcd"))
+	require.Equal(t, "transfer/channel-3/transfer/channel-1/0xabcd", ReceivedDenom("transfer", "channel-2", "transfer", "channel-3", "transfer/channel-1/0xabcd"))
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The acknowledgements written by ICS20Transfer.
+var (
+	ics20SuccessAcknowledgement = []byte{1}
+	ics20FailureAcknowledgement = []byte{0}
+)
+
+// FungibleTokenPacketDataJSON is the ICS-20 packet data encoded in JSON by ibc-go.
+// The fields are declared in alphabetical order, which is the order ibc-go sorts the keys in.
+type FungibleTokenPacketDataJSON struct {
+	// the token amount to be transferred as a decimal string
+	Amount string `json:"amount"`
+	// the token denomination to be transferred
+	Denom string `json:"denom"`
+	// optional memo
+	Memo string `json:"memo,omitempty"`
+	// the recipient address on the destination chain
+	Receiver string `json:"receiver"`
+	// the sender address
+	Sender string `json:"sender"`
+}
+
+func (d FungibleTokenPacketDataJSON) String() string {
+	s := fmt.Sprintf("denom=%v amount=%v sender=%v receiver=%v", d.Denom, d.Amount, d.Sender, d.Receiver)
+	if d.Memo != "" {
+		s += fmt.Sprintf(" memo=%v", d.Memo)
+	}
+	return s
+}
+
+// EncodeFungibleTokenPacketData encodes the packet data in protobuf as ICS20Transfer does.
+func EncodeFungibleTokenPacketData(data *FungibleTokenPacketData) ([]byte, error) {
+	return data.Marshal()
+}
+
+// DecodeFungibleTokenPacketData decodes the packet data encoded in protobuf.
+func DecodeFungibleTokenPacketData(bz []byte) (*FungibleTokenPacketData, error) {
+	var data FungibleTokenPacketData
+	if err := data.Unmarshal(bz); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// EncodeFungibleTokenPacketDataJSON encodes the packet data in JSON as ibc-go does.
+func EncodeFungibleTokenPacketDataJSON(data *FungibleTokenPacketDataJSON) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+// DecodeFungibleTokenPacketDataJSON decodes the packet data encoded in JSON.
+func DecodeFungibleTokenPacketDataJSON(bz []byte) (*FungibleTokenPacketDataJSON, error) {
+	var data FungibleTokenPacketDataJSON
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// ToJSON converts the packet data to the JSON representation. An address of 20 bytes is
+// converted to a hex string with the 0x prefix, and any other address is taken as a string.
+func (m *FungibleTokenPacketData) ToJSON() *FungibleTokenPacketDataJSON {
+	return &FungibleTokenPacketDataJSON{
+		Amount:   strconv.FormatUint(m.Amount, 10),
+		Denom:    m.Denom,
+		Receiver: addressToString(m.Receiver),
+		Sender:   addressToString(m.Sender),
+	}
+}
+
+// ToProto converts the packet data to the protobuf representation. An address of a hex string with
+// the 0x prefix is decoded, and any other address is taken as bytes. An error is returned if
+// the amount does not fit in uint64 or the memo is not empty as it cannot be represented.
+func (d *FungibleTokenPacketDataJSON) ToProto() (*FungibleTokenPacketData, error) {
+	amount, err := strconv.ParseUint(d.Amount, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount '%v': %w", d.Amount, err)
+	}
+	if d.Memo != "" {
+		return nil, fmt.Errorf("memo is not supported: %v", d.Memo)
+	}
+	receiver, err := stringToAddress(d.Receiver)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receiver '%v': %w", d.Receiver, err)
+	}
+	sender, err := stringToAddress(d.Sender)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender '%v': %w", d.Sender, err)
+	}
+	return &FungibleTokenPacketData{
+		Denom:    d.Denom,
+		Amount:   amount,
+		Sender:   sender,
+		Receiver: receiver,
+	}, nil
+}
+
+func addressToString(addr []byte) string {
+	if len(addr) == common.AddressLength {
+		return strings.ToLower(common.BytesToAddress(addr).Hex())
+	}
+	return string(addr)
+}
+
+func stringToAddress(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "0x") {
+		return hex.DecodeString(s[2:])
+	}
+	return []byte(s), nil
+}
+
+// Acknowledgement is the acknowledgement of a packet, which is either a success with a result
+// or an error with a message.
+type Acknowledgement struct {
+	success bool
+	result  []byte
+	err     string
+}
+
+// NewResultAcknowledgement returns a successful acknowledgement with the result.
+func NewResultAcknowledgement(result []byte) Acknowledgement {
+	return Acknowledgement{success: true, result: result}
+}
+
+// NewErrorAcknowledgement returns a failed acknowledgement with the error message.
+func NewErrorAcknowledgement(err string) Acknowledgement {
+	return Acknowledgement{err: err}
+}
+
+// Success returns true if the acknowledgement is successful.
+func (a Acknowledgement) Success() bool {
+	return a.success
+}
+
+// Result returns the result of a successful acknowledgement.
+func (a Acknowledgement) Result() []byte {
+	return a.result
+}
+
+// ErrorMessage returns the error message of a failed acknowledgement.
+func (a Acknowledgement) ErrorMessage() string {
+	return a.err
+}
+
+func (a Acknowledgement) String() string {
+	if a.success {
+		return fmt.Sprintf("result:%x", a.result)
+	}
+	return fmt.Sprintf("error:%v", a.err)
+}
+
+// acknowledgementJSON is the acknowledgement encoded in JSON by ibc-go.
+type acknowledgementJSON struct {
+	Result []byte  `json:"result,omitempty"`
+	Error  *string `json:"error,omitempty"`
+}
+
+// EncodeICS20Acknowledgement encodes the acknowledgement as ICS20Transfer does,
+// which is a single byte of 1 on success or 0 on error. The result and the error message are dropped.
+func EncodeICS20Acknowledgement(ack Acknowledgement) []byte {
+	if ack.success {
+		return ics20SuccessAcknowledgement
+	}
+	return ics20FailureAcknowledgement
+}
+
+// EncodeAcknowledgementJSON encodes the acknowledgement in JSON as ibc-go does,
+// which is `{"result":"<base64>"}` on success or `{"error":"<message>"}` on error.
+func EncodeAcknowledgementJSON(ack Acknowledgement) ([]byte, error) {
+	if ack.success {
+		if len(ack.result) == 0 {
+			return nil, errors.New("result of a successful acknowledgement must not be empty")
+		}
+		return json.Marshal(acknowledgementJSON{Result: ack.result})
+	}
+	if ack.err == "" {
+		return nil, errors.New("error of a failed acknowledgement must not be empty")
+	}
+	return json.Marshal(acknowledgementJSON{Error: &ack.err})
+}
+
+// DecodeAcknowledgement decodes the acknowledgement encoded by either ICS20Transfer or ibc-go.
+// A failed acknowledgement of ICS20Transfer has no message, so a generic message is set.
+func DecodeAcknowledgement(bz []byte) (Acknowledgement, error) {
+	switch {
+	case bytes.Equal(bz, ics20SuccessAcknowledgement):
+		return NewResultAcknowledgement(bz), nil
+	case bytes.Equal(bz, ics20FailureAcknowledgement):
+		return NewErrorAcknowledgement("failed to receive the packet"), nil
+	}
+	var ack acknowledgementJSON
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&ack); err != nil {
+		return Acknowledgement{}, fmt.Errorf("invalid acknowledgement %x: %w", bz, err)
+	}
+	switch {
+	case len(ack.Result) > 0 && ack.Error == nil:
+		return NewResultAcknowledgement(ack.Result), nil
+	case len(ack.Result) == 0 && ack.Error != nil && *ack.Error != "":
+		return NewErrorAcknowledgement(*ack.Error), nil
+	default:
+		return Acknowledgement{}, fmt.Errorf("invalid acknowledgement: %s", bz)
+	}
+}
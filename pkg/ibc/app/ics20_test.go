@@ -0,0 +1,85 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFungibleTokenPacketDataConversion(t *testing.T) {
+	sender := common.HexToAddress("0xcb07e6bd2b9a4a6e5cbd2b4cdd8ae9eb7b8a5aa4")
+	data := &FungibleTokenPacketData{
+		Denom:    "transfer/channel-0/0xabcd",
+		Amount:   100,
+		Sender:   sender.Bytes(),
+		Receiver: []byte("cosmos1vqpjljwsynsn58dugz0w8ut7kun7t8ls2qkmsq"),
+	}
+
+	bz, err := EncodeFungibleTokenPacketData(data)
+	require.NoError(t, err)
+	decoded, err := DecodeFungibleTokenPacketData(bz)
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+
+	jsonData := data.ToJSON()
+	bz, err = EncodeFungibleTokenPacketDataJSON(jsonData)
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"amount":"100","denom":"transfer/channel-0/0xabcd","receiver":"cosmos1vqpjljwsynsn58dugz0w8ut7kun7t8ls2qkmsq","sender":"0xcb07e6bd2b9a4a6e5cbd2b4cdd8ae9eb7b8a5aa4"}`,
+		string(bz),
+	)
+	decodedJSON, err := DecodeFungibleTokenPacketDataJSON(bz)
+	require.NoError(t, err)
+	require.Equal(t, jsonData, decodedJSON)
+
+	converted, err := decodedJSON.ToProto()
+	require.NoError(t, err)
+	require.Equal(t, data, converted)
+
+	_, err = (&FungibleTokenPacketDataJSON{Amount: "18446744073709551616", Denom: "uatom"}).ToProto()
+	require.Error(t, err)
+	_, err = (&FungibleTokenPacketDataJSON{Amount: "1", Denom: "uatom", Memo: "memo"}).ToProto()
+	require.Error(t, err)
+	_, err = DecodeFungibleTokenPacketDataJSON([]byte(`{"amount":"1","unknown":"x"}`))
+	require.Error(t, err)
+}
+
+func TestAcknowledgement(t *testing.T) {
+	var cases = []struct {
+		bz      string
+		success bool
+		result  []byte
+		err     string
+	}{
+		{"\x01", true, []byte{1}, ""},
+		{"\x00", false, nil, "failed to receive the packet"},
+		{`{"result":"AQ=="}`, true, []byte{1}, ""},
+		{`{"error":"ABCI code: 1: error handling packet: see events for details"}`, false, nil, "ABCI code: 1: error handling packet: see events for details"},
+	}
+
+	for i, c := range cases {
+		ack, err := DecodeAcknowledgement([]byte(c.bz))
+		require.NoError(t, err, "case %v", i)
+		require.Equal(t, c.success, ack.Success(), "case %v", i)
+		require.Equal(t, c.result, ack.Result(), "case %v", i)
+		require.Equal(t, c.err, ack.ErrorMessage(), "case %v", i)
+	}
+
+	for i, bz := range []string{"", "\x02", `{}`, `{"result":""}`, `{"error":""}`, `{"result":"AQ==","error":"x"}`} {
+		_, err := DecodeAcknowledgement([]byte(bz))
+		require.Error(t, err, "case %v", i)
+	}
+
+	bz, err := EncodeAcknowledgementJSON(NewResultAcknowledgement([]byte{1}))
+	require.NoError(t, err)
+	require.Equal(t, `{"result":"AQ=="}`, string(bz))
+	bz, err = EncodeAcknowledgementJSON(NewErrorAcknowledgement("insufficient funds"))
+	require.NoError(t, err)
+	require.Equal(t, `{"error":"insufficient funds"}`, string(bz))
+	_, err = EncodeAcknowledgementJSON(NewErrorAcknowledgement(""))
+	require.Error(t, err)
+
+	require.Equal(t, []byte{1}, EncodeICS20Acknowledgement(NewResultAcknowledgement([]byte{1})))
+	require.Equal(t, []byte{0}, EncodeICS20Acknowledgement(NewErrorAcknowledgement("insufficient funds")))
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ICS20PortID is the port ID the ICS-20 transfer app is bound to.
+const ICS20PortID = "transfer"
+
+// Decoder decodes the packet data and the acknowledgements of an app into values that are rendered
+// with fmt. A nil function leaves the corresponding bytes undecoded.
+type Decoder struct {
+	PacketData      func(data []byte) (interface{}, error)
+	Acknowledgement func(ack []byte) (interface{}, error)
+}
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = make(map[string]Decoder)
+)
+
+func init() {
+	RegisterDecoder(ICS20PortID, Decoder{
+		PacketData:      decodeICS20PacketData,
+		Acknowledgement: decodeICS20Acknowledgement,
+	})
+}
+
+// RegisterDecoder registers the decoder for the app bound to the port, replacing any decoder
+// registered for the port before.
+func RegisterDecoder(portID string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[portID] = decoder
+}
+
+// GetDecoder returns the decoder registered for the port.
+func GetDecoder(portID string) (Decoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	decoder, ok := decoders[portID]
+	return decoder, ok
+}
+
+// DecodePacketDataByPort decodes the packet data sent from or to the port with the registered decoder.
+func DecodePacketDataByPort(portID string, data []byte) (interface{}, error) {
+	decoder, ok := GetDecoder(portID)
+	if !ok || decoder.PacketData == nil {
+		return nil, fmt.Errorf("packet data decoder not found: portID=%v", portID)
+	}
+	return decoder.PacketData(data)
+}
+
+// DecodeAcknowledgementByPort decodes the acknowledgement written by the app bound to the port with the registered decoder.
+func DecodeAcknowledgementByPort(portID string, ack []byte) (interface{}, error) {
+	decoder, ok := GetDecoder(portID)
+	if !ok || decoder.Acknowledgement == nil {
+		return nil, fmt.Errorf("acknowledgement decoder not found: portID=%v", portID)
+	}
+	return decoder.Acknowledgement(ack)
+}
+
+// FormatPacketData returns the human-readable form of the packet data, or its hex if it cannot be decoded.
+func FormatPacketData(portID string, data []byte) string {
+	if v, err := DecodePacketDataByPort(portID, data); err == nil {
+		return fmt.Sprint(v)
+	}
+	return fmt.Sprintf("0x%x", data)
+}
+
+// FormatAcknowledgement returns the human-readable form of the acknowledgement, or its hex if it cannot be decoded.
+func FormatAcknowledgement(portID string, ack []byte) string {
+	if v, err := DecodeAcknowledgementByPort(portID, ack); err == nil {
+		return fmt.Sprint(v)
+	}
+	return fmt.Sprintf("0x%x", ack)
+}
+
+// decodeICS20PacketData decodes the packet data encoded in either protobuf or JSON into its JSON representation.
+func decodeICS20PacketData(data []byte) (interface{}, error) {
+	if len(data) > 0 && data[0] == '{' {
+		return DecodeFungibleTokenPacketDataJSON(data)
+	}
+	d, err := DecodeFungibleTokenPacketData(data)
+	if err != nil {
+		return nil, err
+	}
+	return d.ToJSON(), nil
+}
+
+func decodeICS20Acknowledgement(ack []byte) (interface{}, error) {
+	return DecodeAcknowledgement(ack)
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestICS20Decoder(t *testing.T) {
+	data := &FungibleTokenPacketData{
+		Denom:    "0xabcd",
+		Amount:   100,
+		Sender:   common.HexToAddress("0x01").Bytes(),
+		Receiver: common.HexToAddress("0x02").Bytes(),
+	}
+	bz, err := EncodeFungibleTokenPacketData(data)
+	require.NoError(t, err)
+	expected := "denom=0xabcd amount=100 sender=0x0000000000000000000000000000000000000001 receiver=0x0000000000000000000000000000000000000002"
+	require.Equal(t, expected, FormatPacketData(ICS20PortID, bz))
+
+	bz, err = EncodeFungibleTokenPacketDataJSON(data.ToJSON())
+	require.NoError(t, err)
+	require.Equal(t, expected, FormatPacketData(ICS20PortID, bz))
+
+	require.Equal(t, "result:01", FormatAcknowledgement(ICS20PortID, []byte{1}))
+	require.Equal(t, `error:insufficient funds`, FormatAcknowledgement(ICS20PortID, []byte(`{"error":"insufficient funds"}`)))
+	require.Equal(t, "0x02", FormatAcknowledgement(ICS20PortID, []byte{2}))
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	const portID = "test-registry"
+	require.Equal(t, "0x0102", FormatPacketData(portID, []byte{1, 2}))
+	_, err := DecodePacketDataByPort(portID, []byte{1, 2})
+	require.Error(t, err)
+
+	RegisterDecoder(portID, Decoder{
+		PacketData: func(data []byte) (interface{}, error) {
+			if len(data) == 0 {
+				return nil, errors.New("empty data")
+			}
+			return string(data), nil
+		},
+	})
+	require.Equal(t, "hello", FormatPacketData(portID, []byte("hello")))
+	require.Equal(t, "0x", FormatPacketData(portID, nil))
+	require.Equal(t, "0x01", FormatAcknowledgement(portID, []byte{1}))
+}
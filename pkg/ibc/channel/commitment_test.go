@@ -0,0 +1,24 @@
+package channel
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitPacket(t *testing.T) {
+	packet := NewPacket([]byte("packet data"), 1, "port", "channel-0", "port", "channel-1", NewHeight(1, 100), 1234)
+	require.Equal(t, "26c20843b1c900d2c6acaf285bfcf0b59081e1f2a8f4d3ca47079efa71742746", hex.EncodeToString(CommitPacket(packet)))
+
+	// the identifiers and the sequence of the packet are not committed
+	other := NewPacket([]byte("packet data"), 2, "other", "channel-1", "other", "channel-2", NewHeight(1, 100), 1234)
+	require.Equal(t, CommitPacket(packet), CommitPacket(other))
+
+	other = NewPacket([]byte("packet data"), 1, "port", "channel-0", "port", "channel-1", NewHeight(0, 100), 1234)
+	require.NotEqual(t, CommitPacket(packet), CommitPacket(other))
+}
+
+func TestCommitAcknowledgement(t *testing.T) {
+	require.Equal(t, "4bf5122f344554c53bde2ebb8cd2b7e3d1600ad631c385a5d7cce23c7785459a", hex.EncodeToString(CommitAcknowledgement([]byte{1})))
+}
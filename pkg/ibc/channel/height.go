@@ -0,0 +1,44 @@
+package channel
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// chainIDRegex matches a chain ID formatted as `{chainID}-{revision}`.
+// The revision must be a positive integer without leading zeros.
+var chainIDRegex = regexp.MustCompile(`^.*[^\n-]-{1}[1-9][0-9]*$`)
+
+// NewHeight creates a new Height instance.
+func NewHeight(revisionNumber, revisionHeight uint64) Height {
+	return Height{
+		RevisionNumber: revisionNumber,
+		RevisionHeight: revisionHeight,
+	}
+}
+
+// IsRevisionFormat returns true if the chain ID is formatted as `{chainID}-{revision}`.
+func IsRevisionFormat(chainID string) bool {
+	return chainIDRegex.MatchString(chainID)
+}
+
+// ParseChainID returns the revision number of the chain ID.
+// 0 is returned if the chain ID is not formatted as `{chainID}-{revision}`.
+func ParseChainID(chainID string) uint64 {
+	if !IsRevisionFormat(chainID) {
+		return 0
+	}
+	splitStr := strings.Split(chainID, "-")
+	revision, err := strconv.ParseUint(splitStr[len(splitStr)-1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// Format returns the height as `{revisionNumber}-{revisionHeight}`.
+func (h Height) Format() string {
+	return fmt.Sprintf("%d-%d", h.RevisionNumber, h.RevisionHeight)
+}
@@ -0,0 +1,33 @@
+package channel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChainID(t *testing.T) {
+	var cases = []struct {
+		chainID  string
+		revision uint64
+		format   bool
+	}{
+		{"foo-1", 1, true},
+		{"foo-bar-10", 10, true},
+		{"foo--1", 0, false},
+		{"foo-0", 0, false},
+		{"foo-01", 0, false},
+		{"foo", 0, false},
+		{"2018", 0, false},
+		{"-1", 0, false},
+	}
+
+	for i, c := range cases {
+		require.Equal(t, c.format, IsRevisionFormat(c.chainID), "case %v", i)
+		require.Equal(t, c.revision, ParseChainID(c.chainID), "case %v", i)
+	}
+}
+
+func TestHeightFormat(t *testing.T) {
+	require.Equal(t, "1-10", NewHeight(1, 10).Format())
+}
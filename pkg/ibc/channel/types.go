@@ -18,3 +18,17 @@ func NewPacket(
 		TimeoutTimestamp:   timeoutTimestamp,
 	}
 }
+
+// IsTimedOut returns true if the packet can no longer be received on the
+// destination chain whose latest block has the given height and timestamp.
+// A zero timeout height or timestamp disables the corresponding check.
+// As in the contracts, only the revision heights are compared.
+func (p Packet) IsTimedOut(height Height, timestamp uint64) bool {
+	if p.TimeoutHeight.RevisionHeight != 0 && height.RevisionHeight >= p.TimeoutHeight.RevisionHeight {
+		return true
+	}
+	if p.TimeoutTimestamp != 0 && timestamp >= p.TimeoutTimestamp {
+		return true
+	}
+	return false
+}
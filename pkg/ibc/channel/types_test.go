@@ -0,0 +1,33 @@
+package channel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketIsTimedOut(t *testing.T) {
+	var cases = []struct {
+		timeoutHeight    Height
+		timeoutTimestamp uint64
+		height           Height
+		timestamp        uint64
+		expected         bool
+	}{
+		{NewHeight(0, 0), 0, NewHeight(0, 100), 100, false},
+		{NewHeight(0, 101), 0, NewHeight(0, 100), 100, false},
+		{NewHeight(0, 100), 0, NewHeight(0, 100), 100, true},
+		{NewHeight(0, 0), 101, NewHeight(0, 100), 100, false},
+		{NewHeight(0, 0), 100, NewHeight(0, 100), 100, true},
+		{NewHeight(0, 101), 100, NewHeight(0, 100), 100, true},
+		{NewHeight(0, 100), 101, NewHeight(0, 100), 100, true},
+		{NewHeight(1, 10), 0, NewHeight(0, 100), 100, true},
+		{NewHeight(0, 200), 0, NewHeight(1, 10), 100, false},
+		{NewHeight(1, 0), 0, NewHeight(0, 100), 100, false},
+	}
+
+	for i, c := range cases {
+		packet := NewPacket(nil, 1, "port", "channel-0", "port", "channel-1", c.timeoutHeight, c.timeoutTimestamp)
+		require.Equal(t, c.expected, packet.IsTimedOut(c.height, c.timestamp), "case %v", i)
+	}
+}
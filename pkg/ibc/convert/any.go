@@ -0,0 +1,53 @@
+package convert
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+// PackAny packs the message into Any with the type URL of the message, as the contracts expect
+// the client states, consensus states and headers to be packed.
+func PackAny(msg proto.Message) (*types.Any, error) {
+	var any types.Any
+	any.TypeUrl = "/" + proto.MessageName(msg)
+
+	bz, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	any.Value = bz
+	return &any, nil
+}
+
+// UnpackAny decodes the bytes into Any.
+func UnpackAny(bz []byte) (*types.Any, error) {
+	var any types.Any
+	if err := proto.Unmarshal(bz, &any); err != nil {
+		return nil, err
+	}
+	return &any, nil
+}
+
+// MarshalWithAny packs the message into Any and encodes it.
+func MarshalWithAny(msg proto.Message) ([]byte, error) {
+	any, err := PackAny(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(any)
+}
+
+// UnmarshalWithAny decodes the bytes into Any, and unpacks the message from it.
+// An error is returned if the type URL differs from the one of the message.
+func UnmarshalWithAny(bz []byte, msg proto.Message) error {
+	any, err := UnpackAny(bz)
+	if err != nil {
+		return err
+	}
+	if t := "/" + proto.MessageName(msg); any.TypeUrl != t {
+		return fmt.Errorf("expected %v, but got %v", t, any.TypeUrl)
+	}
+	return proto.Unmarshal(any.Value, msg)
+}
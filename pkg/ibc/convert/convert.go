@@ -0,0 +1,262 @@
+// Package convert converts the ABI structs of the IBC contracts from and to the protobuf IBC types.
+//
+// The ABI structs generated for IBCHost, which are returned by its getters, are converted with
+// the ToPB and FromPB functions. The ABI structs generated for IBCHandler, which are passed
+// in its messages, are converted with the ToCallData and FromCallData functions.
+//
+// The protobuf types returned by the conversions are encoded byte for byte as the contracts
+// encode them, so that commitments computed from them match the ones stored on-chain.
+// In particular, nested messages are always set since the contracts always encode them.
+package convert
+
+import (
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchost"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	connectiontypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/connection"
+)
+
+// ConnectionEndToPB converts the connection end returned by IBCHost to protobuf.
+func ConnectionEndToPB(conn ibchost.ConnectionEndData) *connectiontypes.ConnectionEnd {
+	versions := make([]*connectiontypes.Version, 0, len(conn.Versions))
+	for _, v := range conn.Versions {
+		versions = append(versions, VersionToPB(v))
+	}
+	return &connectiontypes.ConnectionEnd{
+		ClientId:     conn.ClientId,
+		Versions:     versions,
+		State:        connectiontypes.ConnectionEnd_State(conn.State),
+		Counterparty: CounterpartyToPB(conn.Counterparty),
+		DelayPeriod:  conn.DelayPeriod,
+	}
+}
+
+// ConnectionEndFromPB converts the connection end in protobuf to the one of IBCHost.
+func ConnectionEndFromPB(conn *connectiontypes.ConnectionEnd) ibchost.ConnectionEndData {
+	versions := make([]ibchost.VersionData, 0, len(conn.Versions))
+	for _, v := range conn.Versions {
+		versions = append(versions, VersionFromPB(v))
+	}
+	return ibchost.ConnectionEndData{
+		ClientId:     conn.ClientId,
+		Versions:     versions,
+		State:        uint8(conn.State),
+		Counterparty: CounterpartyFromPB(conn.Counterparty),
+		DelayPeriod:  conn.DelayPeriod,
+	}
+}
+
+// CounterpartyToPB converts the connection counterparty returned by IBCHost to protobuf.
+func CounterpartyToPB(counterparty ibchost.CounterpartyData) *connectiontypes.Counterparty {
+	return &connectiontypes.Counterparty{
+		ClientId:     counterparty.ClientId,
+		ConnectionId: counterparty.ConnectionId,
+		Prefix:       &connectiontypes.MerklePrefix{KeyPrefix: counterparty.Prefix.KeyPrefix},
+	}
+}
+
+// CounterpartyFromPB converts the connection counterparty in protobuf to the one of IBCHost.
+func CounterpartyFromPB(counterparty *connectiontypes.Counterparty) ibchost.CounterpartyData {
+	if counterparty == nil {
+		return ibchost.CounterpartyData{}
+	}
+	var prefix ibchost.MerklePrefixData
+	if counterparty.Prefix != nil {
+		prefix.KeyPrefix = counterparty.Prefix.KeyPrefix
+	}
+	return ibchost.CounterpartyData{
+		ClientId:     counterparty.ClientId,
+		ConnectionId: counterparty.ConnectionId,
+		Prefix:       prefix,
+	}
+}
+
+// CounterpartyToCallData converts the connection counterparty in protobuf to the one of IBCHandler.
+func CounterpartyToCallData(counterparty *connectiontypes.Counterparty) ibchandler.CounterpartyData {
+	c := CounterpartyFromPB(counterparty)
+	return ibchandler.CounterpartyData{
+		ClientId:     c.ClientId,
+		ConnectionId: c.ConnectionId,
+		Prefix:       ibchandler.MerklePrefixData(c.Prefix),
+	}
+}
+
+// CounterpartyFromCallData converts the connection counterparty of IBCHandler to protobuf.
+func CounterpartyFromCallData(counterparty ibchandler.CounterpartyData) *connectiontypes.Counterparty {
+	return CounterpartyToPB(ibchost.CounterpartyData{
+		ClientId:     counterparty.ClientId,
+		ConnectionId: counterparty.ConnectionId,
+		Prefix:       ibchost.MerklePrefixData(counterparty.Prefix),
+	})
+}
+
+// VersionToPB converts the connection version returned by IBCHost to protobuf.
+func VersionToPB(version ibchost.VersionData) *connectiontypes.Version {
+	return &connectiontypes.Version{
+		Identifier: version.Identifier,
+		Features:   version.Features,
+	}
+}
+
+// VersionFromPB converts the connection version in protobuf to the one of IBCHost.
+func VersionFromPB(version *connectiontypes.Version) ibchost.VersionData {
+	if version == nil {
+		return ibchost.VersionData{}
+	}
+	return ibchost.VersionData{
+		Identifier: version.Identifier,
+		Features:   version.Features,
+	}
+}
+
+// VersionToCallData converts the connection version in protobuf to the one of IBCHandler.
+func VersionToCallData(version *connectiontypes.Version) ibchandler.VersionData {
+	return ibchandler.VersionData(VersionFromPB(version))
+}
+
+// VersionFromCallData converts the connection version of IBCHandler to protobuf.
+func VersionFromCallData(version ibchandler.VersionData) *connectiontypes.Version {
+	return VersionToPB(ibchost.VersionData(version))
+}
+
+// ChannelToPB converts the channel returned by IBCHost to protobuf.
+func ChannelToPB(ch ibchost.ChannelData) *channeltypes.Channel {
+	return &channeltypes.Channel{
+		State:          channeltypes.Channel_State(ch.State),
+		Ordering:       channeltypes.Channel_Order(ch.Ordering),
+		Counterparty:   channeltypes.Channel_Counterparty(ch.Counterparty),
+		ConnectionHops: ch.ConnectionHops,
+		Version:        ch.Version,
+	}
+}
+
+// ChannelFromPB converts the channel in protobuf to the one of IBCHost.
+func ChannelFromPB(ch *channeltypes.Channel) ibchost.ChannelData {
+	return ibchost.ChannelData{
+		State:          uint8(ch.State),
+		Ordering:       uint8(ch.Ordering),
+		Counterparty:   ibchost.ChannelCounterpartyData(ch.Counterparty),
+		ConnectionHops: ch.ConnectionHops,
+		Version:        ch.Version,
+	}
+}
+
+// ChannelToCallData converts the channel in protobuf to the one of IBCHandler.
+func ChannelToCallData(ch *channeltypes.Channel) ibchandler.ChannelData {
+	return ibchandler.ChannelData{
+		State:          uint8(ch.State),
+		Ordering:       uint8(ch.Ordering),
+		Counterparty:   ibchandler.ChannelCounterpartyData(ch.Counterparty),
+		ConnectionHops: ch.ConnectionHops,
+		Version:        ch.Version,
+	}
+}
+
+// ChannelFromCallData converts the channel of IBCHandler to protobuf.
+func ChannelFromCallData(ch ibchandler.ChannelData) *channeltypes.Channel {
+	return &channeltypes.Channel{
+		State:          channeltypes.Channel_State(ch.State),
+		Ordering:       channeltypes.Channel_Order(ch.Ordering),
+		Counterparty:   channeltypes.Channel_Counterparty(ch.Counterparty),
+		ConnectionHops: ch.ConnectionHops,
+		Version:        ch.Version,
+	}
+}
+
+// PacketToPB converts the packet of IBCHost to protobuf.
+func PacketToPB(packet ibchost.PacketData) channeltypes.Packet {
+	return channeltypes.Packet{
+		Sequence:           packet.Sequence,
+		SourcePort:         packet.SourcePort,
+		SourceChannel:      packet.SourceChannel,
+		DestinationPort:    packet.DestinationPort,
+		DestinationChannel: packet.DestinationChannel,
+		Data:               packet.Data,
+		TimeoutHeight:      HeightToPB(packet.TimeoutHeight),
+		TimeoutTimestamp:   packet.TimeoutTimestamp,
+	}
+}
+
+// PacketFromPB converts the packet in protobuf to the one of IBCHost.
+func PacketFromPB(packet channeltypes.Packet) ibchost.PacketData {
+	return ibchost.PacketData{
+		Sequence:           packet.Sequence,
+		SourcePort:         packet.SourcePort,
+		SourceChannel:      packet.SourceChannel,
+		DestinationPort:    packet.DestinationPort,
+		DestinationChannel: packet.DestinationChannel,
+		Data:               packet.Data,
+		TimeoutHeight:      HeightFromPB(packet.TimeoutHeight),
+		TimeoutTimestamp:   packet.TimeoutTimestamp,
+	}
+}
+
+// PacketToCallData converts the packet in protobuf to the one of IBCHandler.
+func PacketToCallData(packet channeltypes.Packet) ibchandler.PacketData {
+	return ibchandler.PacketData{
+		Sequence:           packet.Sequence,
+		SourcePort:         packet.SourcePort,
+		SourceChannel:      packet.SourceChannel,
+		DestinationPort:    packet.DestinationPort,
+		DestinationChannel: packet.DestinationChannel,
+		Data:               packet.Data,
+		TimeoutHeight:      HeightToCallData(packet.TimeoutHeight),
+		TimeoutTimestamp:   packet.TimeoutTimestamp,
+	}
+}
+
+// PacketFromCallData converts the packet of IBCHandler to protobuf.
+func PacketFromCallData(packet ibchandler.PacketData) channeltypes.Packet {
+	return channeltypes.Packet{
+		Sequence:           packet.Sequence,
+		SourcePort:         packet.SourcePort,
+		SourceChannel:      packet.SourceChannel,
+		DestinationPort:    packet.DestinationPort,
+		DestinationChannel: packet.DestinationChannel,
+		Data:               packet.Data,
+		TimeoutHeight:      HeightFromCallData(packet.TimeoutHeight),
+		TimeoutTimestamp:   packet.TimeoutTimestamp,
+	}
+}
+
+// HeightToPB converts the height of IBCHost to protobuf.
+func HeightToPB(height ibchost.HeightData) channeltypes.Height {
+	return channeltypes.Height(height)
+}
+
+// HeightFromPB converts the height in protobuf to the one of IBCHost.
+func HeightFromPB(height channeltypes.Height) ibchost.HeightData {
+	return ibchost.HeightData(height)
+}
+
+// HeightToCallData converts the height in protobuf to the one of IBCHandler.
+func HeightToCallData(height channeltypes.Height) ibchandler.HeightData {
+	return ibchandler.HeightData(height)
+}
+
+// HeightFromCallData converts the height of IBCHandler to protobuf.
+func HeightFromCallData(height ibchandler.HeightData) channeltypes.Height {
+	return channeltypes.Height(height)
+}
+
+// PacketStateFromCommitment returns the packet state holding the commitment returned by
+// IBCHost.GetPacketCommitment or IBCHost.GetPacketAcknowledgementCommitment.
+func PacketStateFromCommitment(portID, channelID string, sequence uint64, commitment [32]byte) channeltypes.PacketState {
+	return channeltypes.PacketState{
+		PortId:    portID,
+		ChannelId: channelID,
+		Sequence:  sequence,
+		Data:      commitment[:],
+	}
+}
+
+// PacketStateToCommitment returns the commitment held by the packet state as IBCHost returns it.
+// false is returned if the data of the packet state is not a commitment of 32 bytes.
+func PacketStateToCommitment(state channeltypes.PacketState) ([32]byte, bool) {
+	var commitment [32]byte
+	if len(state.Data) != len(commitment) {
+		return commitment, false
+	}
+	copy(commitment[:], state.Data)
+	return commitment, true
+}
@@ -0,0 +1,106 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchost"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	connectiontypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/connection"
+)
+
+func TestConnectionEndRoundTrip(t *testing.T) {
+	conn := ibchost.ConnectionEndData{
+		ClientId: "mock-client-0",
+		Versions: []ibchost.VersionData{
+			{Identifier: "1", Features: []string{"ORDER_ORDERED", "ORDER_UNORDERED"}},
+		},
+		State: uint8(connectiontypes.ConnectionEnd_STATE_OPEN),
+		Counterparty: ibchost.CounterpartyData{
+			ClientId:     "mock-client-1",
+			ConnectionId: "connection-1",
+			Prefix:       ibchost.MerklePrefixData{KeyPrefix: []byte("ibc")},
+		},
+		DelayPeriod: 3000000000,
+	}
+
+	pb := ConnectionEndToPB(conn)
+	bz, err := proto.Marshal(pb)
+	require.NoError(t, err)
+	var decoded connectiontypes.ConnectionEnd
+	require.NoError(t, proto.Unmarshal(bz, &decoded))
+	require.Equal(t, conn, ConnectionEndFromPB(&decoded))
+
+	// the counterparty and its prefix are always encoded as the contract does
+	empty := ConnectionEndToPB(ibchost.ConnectionEndData{})
+	bz, err = proto.Marshal(empty)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x22, 0x02, 0x1a, 0x00}, bz)
+
+	counterparty := CounterpartyFromCallData(CounterpartyToCallData(pb.Counterparty))
+	require.Equal(t, pb.Counterparty, counterparty)
+	version := VersionFromCallData(VersionToCallData(pb.Versions[0]))
+	require.Equal(t, pb.Versions[0], version)
+}
+
+func TestChannelRoundTrip(t *testing.T) {
+	ch := ibchost.ChannelData{
+		State:    uint8(channeltypes.OPEN),
+		Ordering: uint8(channeltypes.UNORDERED),
+		Counterparty: ibchost.ChannelCounterpartyData{
+			PortId:    "transfer",
+			ChannelId: "channel-1",
+		},
+		ConnectionHops: []string{"connection-0"},
+		Version:        "ics20-1",
+	}
+
+	pb := ChannelToPB(ch)
+	bz, err := proto.Marshal(pb)
+	require.NoError(t, err)
+	var decoded channeltypes.Channel
+	require.NoError(t, proto.Unmarshal(bz, &decoded))
+	require.Equal(t, ch, ChannelFromPB(&decoded))
+	require.Equal(t, pb, ChannelFromCallData(ChannelToCallData(pb)))
+}
+
+func TestPacketRoundTrip(t *testing.T) {
+	packet := ibchost.PacketData{
+		Sequence:           1,
+		SourcePort:         "transfer",
+		SourceChannel:      "channel-0",
+		DestinationPort:    "transfer",
+		DestinationChannel: "channel-1",
+		Data:               []byte{1, 2, 3},
+		TimeoutHeight:      ibchost.HeightData{RevisionNumber: 1, RevisionHeight: 100},
+		TimeoutTimestamp:   1000,
+	}
+
+	pb := PacketToPB(packet)
+	bz, err := proto.Marshal(&pb)
+	require.NoError(t, err)
+	var decoded channeltypes.Packet
+	require.NoError(t, proto.Unmarshal(bz, &decoded))
+	require.Equal(t, packet, PacketFromPB(decoded))
+	require.Equal(t, pb, PacketFromCallData(PacketToCallData(pb)))
+	require.Equal(t, pb.TimeoutHeight, HeightFromCallData(HeightToCallData(pb.TimeoutHeight)))
+}
+
+func TestPacketStateRoundTrip(t *testing.T) {
+	var commitment [32]byte
+	commitment[0], commitment[31] = 1, 2
+
+	state := PacketStateFromCommitment("transfer", "channel-0", 1, commitment)
+	bz, err := proto.Marshal(&state)
+	require.NoError(t, err)
+	var decoded channeltypes.PacketState
+	require.NoError(t, proto.Unmarshal(bz, &decoded))
+	converted, ok := PacketStateToCommitment(decoded)
+	require.True(t, ok)
+	require.Equal(t, commitment, converted)
+
+	_, ok = PacketStateToCommitment(channeltypes.PacketState{Data: []byte{1}})
+	require.False(t, ok)
+}
@@ -0,0 +1,72 @@
+// Package metrics records the metrics of the RPC calls, the transactions and the IBC packets.
+//
+// The metrics are passed to the Recorder set by SetRecorder, which defaults to NopRecorder,
+// so nothing is recorded unless a tool opts in, e.g. with a PrometheusRecorder.
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// PacketEvent is the event of a packet in its lifecycle.
+type PacketEvent string
+
+const (
+	// PacketSent is a packet sent from the source channel.
+	PacketSent PacketEvent = "sent"
+	// PacketReceived is a packet received on the destination channel.
+	PacketReceived PacketEvent = "received"
+	// PacketAcknowledged is a packet acknowledged on the source channel.
+	PacketAcknowledged PacketEvent = "acknowledged"
+)
+
+// Recorder records the metrics. The implementation must be safe for concurrent use.
+type Recorder interface {
+	// ObserveRPC records a JSON-RPC call of the method. err is the transport error or the error returned by the node.
+	ObserveRPC(method string, duration time.Duration, err error)
+	// ObserveTx records a transaction confirmed on the chain. msgType is the name of the contract method called.
+	ObserveTx(chainID, msgType string, gasUsed uint64, confirmation time.Duration, succeeded bool)
+	// IncPacket records an event of the packet on the channel, which is the source channel for
+	// PacketSent and PacketAcknowledged, and the destination channel for PacketReceived.
+	IncPacket(chainID string, event PacketEvent, portID, channelID string)
+	// SetClientLag records the number of blocks the client lags behind the head of the counterparty chain.
+	SetClientLag(chainID, clientID string, lag uint64)
+}
+
+// NopRecorder is a Recorder that records nothing.
+type NopRecorder struct{}
+
+var _ Recorder = NopRecorder{}
+
+func (NopRecorder) ObserveRPC(string, time.Duration, error) {}
+
+func (NopRecorder) ObserveTx(string, string, uint64, time.Duration, bool) {}
+
+func (NopRecorder) IncPacket(string, PacketEvent, string, string) {}
+
+func (NopRecorder) SetClientLag(string, string, uint64) {}
+
+// holder wraps the recorder since atomic.Value requires a consistent concrete type.
+type holder struct {
+	Recorder
+}
+
+var recorder atomic.Value
+
+func init() {
+	SetRecorder(nil)
+}
+
+// SetRecorder sets the recorder of the metrics. A nil recorder disables the recording.
+func SetRecorder(r Recorder) {
+	if r == nil {
+		r = NopRecorder{}
+	}
+	recorder.Store(holder{r})
+}
+
+// GetRecorder returns the recorder of the metrics.
+func GetRecorder() Recorder {
+	return recorder.Load().(holder).Recorder
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type rpcCall struct {
+	method string
+	err    bool
+}
+
+type testRecorder struct {
+	NopRecorder
+	mu    sync.Mutex
+	calls []rpcCall
+}
+
+func (r *testRecorder) ObserveRPC(method string, _ time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, rpcCall{method, err != nil})
+}
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if bytes.HasPrefix(body, []byte("[")) {
+			// replies in the reverse order
+			w.Write([]byte(`[{"jsonrpc":"2.0","id":2,"error":{"code":-32000,"message":"missing trie node"}},{"jsonrpc":"2.0","id":1,"result":"0x1"}]`))
+		} else if strings.Contains(string(body), "eth_getProof") {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":3,"error":{"code":-32601,"message":"method not found"}}`))
+		} else {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":4,"result":null}`))
+		}
+	}))
+	defer srv.Close()
+
+	r := &testRecorder{}
+	SetRecorder(r)
+	defer SetRecorder(nil)
+
+	client := NewHTTPClient()
+	for _, body := range []string{
+		`[{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"},{"jsonrpc":"2.0","id":2,"method":"eth_getBalance"}]`,
+		`{"jsonrpc":"2.0","id":3,"method":"eth_getProof"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"eth_getTransactionReceipt"}`,
+	} {
+		res, err := client.Post(srv.URL, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		// the response body must be readable by the caller
+		bz, err := ioutil.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NotEmpty(t, bz)
+		res.Body.Close()
+	}
+	require.Equal(t, []rpcCall{
+		{"eth_blockNumber", false},
+		{"eth_getBalance", true},
+		{"eth_getProof", true},
+		{"eth_getTransactionReceipt", false},
+	}, r.calls)
+
+	// an unreachable endpoint fails the call
+	srv.Close()
+	_, err := client.Post(srv.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":5,"method":"eth_chainId"}`))
+	require.Error(t, err)
+	require.Equal(t, rpcCall{"eth_chainId", true}, r.calls[len(r.calls)-1])
+}
+
+func TestPrometheusRecorder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	r, err := NewPrometheusRecorder(reg)
+	require.NoError(t, err)
+	_, err = NewPrometheusRecorder(reg)
+	require.Error(t, err, "the collectors must not be registered twice")
+
+	r.ObserveRPC("eth_getProof", time.Second, nil)
+	r.ObserveRPC("eth_getProof", time.Second, http.ErrHandlerTimeout)
+	r.ObserveTx("2018", "recvPacket", 100000, time.Second, false)
+	r.IncPacket("2018", PacketSent, "transfer", "channel-0")
+	r.IncPacket("2018", PacketSent, "transfer", "channel-0")
+	r.SetClientLag("2018", "ibft2-0", 42)
+
+	require.Equal(t, float64(1), testutil.ToFloat64(r.rpcErrors.WithLabelValues("eth_getProof")))
+	require.Equal(t, float64(1), testutil.ToFloat64(r.txFailures.WithLabelValues("2018", "recvPacket")))
+	require.Equal(t, float64(2), testutil.ToFloat64(r.packets.WithLabelValues("2018", "sent", "transfer", "channel-0")))
+	require.Equal(t, float64(42), testutil.ToFloat64(r.clientLag.WithLabelValues("2018", "ibft2-0")))
+
+	res := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Contains(t, res.Body.String(), `ibcsol_rpc_duration_seconds_count{method="eth_getProof"} 2`)
+}
+
+func TestSetRecorder(t *testing.T) {
+	require.Equal(t, NopRecorder{}, GetRecorder())
+	r := &testRecorder{}
+	SetRecorder(r)
+	require.Equal(t, r, GetRecorder())
+	SetRecorder(nil)
+	require.Equal(t, NopRecorder{}, GetRecorder())
+}
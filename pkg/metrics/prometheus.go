@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "ibcsol"
+
+// PrometheusRecorder is a Recorder that exports the metrics to Prometheus.
+type PrometheusRecorder struct {
+	rpcDuration    *prometheus.HistogramVec
+	rpcErrors      *prometheus.CounterVec
+	txGasUsed      *prometheus.HistogramVec
+	txConfirmation *prometheus.HistogramVec
+	txFailures     *prometheus.CounterVec
+	packets        *prometheus.CounterVec
+	clientLag      *prometheus.GaugeVec
+}
+
+var _ Recorder = (*PrometheusRecorder)(nil)
+
+// NewPrometheusRecorder creates a new PrometheusRecorder and registers its collectors to the registerer.
+func NewPrometheusRecorder(reg prometheus.Registerer) (*PrometheusRecorder, error) {
+	r := &PrometheusRecorder{
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "duration_seconds",
+			Help:      "Latency of the JSON-RPC calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "errors_total",
+			Help:      "Number of the JSON-RPC calls that failed.",
+		}, []string{"method"}),
+		txGasUsed: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "tx",
+			Name:      "gas_used",
+			Help:      "Gas used by the transactions.",
+			Buckets:   prometheus.ExponentialBuckets(25000, 2, 10),
+		}, []string{"chain_id", "msg_type"}),
+		txConfirmation: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "tx",
+			Name:      "confirmation_seconds",
+			Help:      "Time to wait for the receipts of the transactions.",
+			Buckets:   prometheus.ExponentialBuckets(0.25, 2, 8),
+		}, []string{"chain_id", "msg_type"}),
+		txFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "tx",
+			Name:      "failures_total",
+			Help:      "Number of the transactions reverted.",
+		}, []string{"chain_id", "msg_type"}),
+		packets: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "packet",
+			Name:      "events_total",
+			Help:      "Number of the packets sent, received and acknowledged per channel.",
+		}, []string{"chain_id", "event", "port_id", "channel_id"}),
+		clientLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "client",
+			Name:      "lag_blocks",
+			Help:      "Number of the blocks the client lags behind the head of the counterparty chain.",
+		}, []string{"chain_id", "client_id"}),
+	}
+	for _, c := range []prometheus.Collector{r.rpcDuration, r.rpcErrors, r.txGasUsed, r.txConfirmation, r.txFailures, r.packets, r.clientLag} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *PrometheusRecorder) ObserveRPC(method string, duration time.Duration, err error) {
+	r.rpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+	if err != nil {
+		r.rpcErrors.WithLabelValues(method).Inc()
+	}
+}
+
+func (r *PrometheusRecorder) ObserveTx(chainID, msgType string, gasUsed uint64, confirmation time.Duration, succeeded bool) {
+	r.txGasUsed.WithLabelValues(chainID, msgType).Observe(float64(gasUsed))
+	r.txConfirmation.WithLabelValues(chainID, msgType).Observe(confirmation.Seconds())
+	if !succeeded {
+		r.txFailures.WithLabelValues(chainID, msgType).Inc()
+	}
+}
+
+func (r *PrometheusRecorder) IncPacket(chainID string, event PacketEvent, portID, channelID string) {
+	r.packets.WithLabelValues(chainID, string(event), portID, channelID).Inc()
+}
+
+func (r *PrometheusRecorder) SetClientLag(chainID, clientID string, lag uint64) {
+	r.clientLag.WithLabelValues(chainID, clientID).Set(float64(lag))
+}
+
+// Handler returns the HTTP handler that exposes the metrics gathered by the gatherer.
+func Handler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+}
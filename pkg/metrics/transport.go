@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Transport is an http.RoundTripper that records the JSON-RPC calls sent through it.
+// A batch request records each call in it with the latency of the whole batch.
+type Transport struct {
+	// Base is the underlying transport. http.DefaultTransport is used if it is nil.
+	Base http.RoundTripper
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// NewHTTPClient returns an http.Client whose JSON-RPC calls are recorded.
+func NewHTTPClient() *http.Client {
+	return &http.Client{Transport: &Transport{}}
+}
+
+type jsonrpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Body == nil {
+		return base.RoundTrip(req)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	calls, err := decodeMessages(body)
+	if err != nil {
+		// not a JSON-RPC request
+		return base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	res, err := base.RoundTrip(req)
+	if err != nil {
+		observeCalls(calls, nil, time.Since(start), err)
+		return nil, err
+	}
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	duration := time.Since(start)
+	if err != nil {
+		observeCalls(calls, nil, duration, err)
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		observeCalls(calls, nil, duration, fmt.Errorf("unexpected status: %v", res.Status))
+		return res, nil
+	}
+	replies, err := decodeMessages(resBody)
+	observeCalls(calls, replies, duration, err)
+	return res, nil
+}
+
+// observeCalls records the calls with the replies matched by their IDs. The calls fail with err if it is not nil.
+func observeCalls(calls, replies []jsonrpcMessage, duration time.Duration, err error) {
+	errs := make(map[string]json.RawMessage)
+	for _, reply := range replies {
+		if len(reply.Error) > 0 && string(reply.Error) != "null" {
+			errs[string(reply.ID)] = reply.Error
+		}
+	}
+	r := GetRecorder()
+	for _, call := range calls {
+		callErr := err
+		if e, ok := errs[string(call.ID)]; ok && callErr == nil {
+			callErr = fmt.Errorf("%s", e)
+		}
+		r.ObserveRPC(call.Method, duration, callErr)
+	}
+}
+
+// decodeMessages decodes a JSON-RPC message or a batch of them.
+func decodeMessages(bz []byte) ([]jsonrpcMessage, error) {
+	bz = bytes.TrimSpace(bz)
+	if len(bz) > 0 && bz[0] == '[' {
+		var msgs []jsonrpcMessage
+		if err := json.Unmarshal(bz, &msgs); err != nil {
+			return nil, err
+		}
+		return msgs, nil
+	}
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(bz, &msg); err != nil {
+		return nil, err
+	}
+	return []jsonrpcMessage{msg}, nil
+}
@@ -0,0 +1,357 @@
+// Package monitor watches the IBC clients of a chain for the conditions that need operators' attention.
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/chains"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	ibft2clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/ibft2"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+)
+
+// DefaultPollInterval is the default interval to poll the chains for new blocks.
+const DefaultPollInterval = 5 * time.Second
+
+var abiUpdateClient abi.Method
+
+func init() {
+	parsedHandlerABI, err := abi.JSON(strings.NewReader(ibchandler.IbchandlerABI))
+	if err != nil {
+		panic(err)
+	}
+	abiUpdateClient = parsedHandlerABI.Methods["updateClient"]
+}
+
+// MisbehaviourType is the type of misbehaviour of an IBFT2 client.
+type MisbehaviourType string
+
+const (
+	// MisbehaviourConflictingHeader is a validly sealed header that differs from the canonical header
+	// of the counterparty chain, or from another header submitted at the same height.
+	MisbehaviourConflictingHeader MisbehaviourType = "conflicting_header"
+	// MisbehaviourRootMismatch is a consensus state whose root differs from the storage root of the IBC store
+	// of the counterparty chain at the same height.
+	MisbehaviourRootMismatch MisbehaviourType = "root_mismatch"
+)
+
+// Evidence is the evidence of misbehaviour of an IBFT2 client.
+type Evidence struct {
+	Type     MisbehaviourType
+	ClientID string
+	Height   uint64
+	// TxHash is the hash of the UpdateClient transaction that submitted the header.
+	TxHash common.Hash
+	// Submitted is the header submitted to the client with its seals.
+	Submitted *chains.ParsedHeader
+	// Conflicting is the header that conflicts with the submitted one with its seals, which is the canonical
+	// header of the counterparty chain or another header submitted at the same height.
+	Conflicting *chains.ParsedHeader
+	// StoredRoot and CanonicalRoot are the roots that differ for MisbehaviourRootMismatch.
+	StoredRoot    []byte
+	CanonicalRoot []byte
+}
+
+func (e Evidence) String() string {
+	s := fmt.Sprintf("%v: client=%v height=%v tx=%v", e.Type, e.ClientID, e.Height, e.TxHash.Hex())
+	switch e.Type {
+	case MisbehaviourConflictingHeader:
+		s += fmt.Sprintf(" submitted=%v conflicting=%v", formatSealedHeader(e.Submitted), formatSealedHeader(e.Conflicting))
+	case MisbehaviourRootMismatch:
+		s += fmt.Sprintf(" stored=0x%x canonical=0x%x", e.StoredRoot, e.CanonicalRoot)
+	}
+	return s
+}
+
+// AlertFunc is called with each evidence of misbehaviour found.
+type AlertFunc func(evidence Evidence)
+
+// MisbehaviourMonitor watches the headers submitted to an IBFT2 client on the chain, and compares them with
+// the canonical headers of the counterparty chain the client tracks. Only the headers accepted by the client
+// are taken as evidence: the transaction submitting the header must succeed, and the header must be sealed
+// by more than 2/3 of its validators and by at least 1/3 of the validators trusted at its trusted height.
+type MisbehaviourMonitor struct {
+	chain        *host.Chain
+	counterparty *host.Chain
+	clientID     string
+
+	// Alert is called with each evidence found by Run if it is not nil.
+	Alert AlertFunc
+	// PollInterval is the interval to poll the chain for new blocks.
+	PollInterval time.Duration
+
+	// submitted are the sealing hashes of the headers submitted to the client by height.
+	submitted map[uint64]*submittedHeader
+}
+
+type submittedHeader struct {
+	header *chains.ParsedHeader
+	hash   common.Hash
+}
+
+// update is the header submitted to the client by an UpdateClient transaction.
+type update struct {
+	header        *chains.ParsedHeader
+	trustedHeight uint64
+}
+
+// NewMisbehaviourMonitor creates a new MisbehaviourMonitor of the IBFT2 client on the chain, which tracks the counterparty chain.
+func NewMisbehaviourMonitor(chain, counterparty *host.Chain, clientID string) *MisbehaviourMonitor {
+	return &MisbehaviourMonitor{
+		chain:        chain,
+		counterparty: counterparty,
+		clientID:     clientID,
+		PollInterval: DefaultPollInterval,
+		submitted:    make(map[uint64]*submittedHeader),
+	}
+}
+
+// Run checks the blocks of the chain from the given block number, and then each new block until
+// the context is done. The evidence found is passed to Alert.
+func (m *MisbehaviourMonitor) Run(ctx context.Context, fromBlock uint64) error {
+	next := fromBlock
+	for {
+		head, err := m.chain.Client().BlockByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if head.NumberU64() >= next {
+			evidences, err := m.Check(ctx, next, head.NumberU64())
+			if err != nil {
+				return err
+			}
+			for _, evidence := range evidences {
+				if m.Alert != nil {
+					m.Alert(evidence)
+				}
+			}
+			next = head.NumberU64() + 1
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.PollInterval):
+		}
+	}
+}
+
+// Check checks the UpdateClient transactions of the client in the blocks of the given range, and returns
+// the evidence of misbehaviour found in them. The headers submitted are remembered so that a header
+// conflicting with one submitted in an earlier range is found as well.
+func (m *MisbehaviourMonitor) Check(ctx context.Context, fromBlock, toBlock uint64) ([]Evidence, error) {
+	clientType, err := m.chain.IBCHost.GetClientType(m.chain.CallOpts(ctx), m.clientID)
+	if err != nil {
+		return nil, err
+	} else if clientType != ibcclient.BesuIBFT2Client {
+		return nil, fmt.Errorf("misbehaviour can be detected only for %v: client=%v type=%v", ibcclient.BesuIBFT2Client, m.clientID, clientType)
+	}
+
+	var evidences []Evidence
+	for bn := fromBlock; bn <= toBlock; bn++ {
+		block, err := m.chain.Client().BlockByNumber(ctx, new(big.Int).SetUint64(bn))
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range block.Transactions() {
+			u, ok, err := m.submittedUpdate(tx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode the header submitted in %v: %w", tx.Hash().Hex(), err)
+			} else if !ok {
+				continue
+			}
+			// the header of a failed transaction is rejected by the client
+			rc, err := m.chain.Client().TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				return nil, err
+			} else if rc.Status() != gethtypes.ReceiptStatusSuccessful {
+				continue
+			}
+			found, err := m.checkHeader(ctx, tx.Hash(), u)
+			if err != nil {
+				return nil, err
+			}
+			evidences = append(evidences, found...)
+		}
+	}
+	return evidences, nil
+}
+
+// submittedUpdate returns the header submitted to the client if the transaction updates the client.
+func (m *MisbehaviourMonitor) submittedUpdate(tx *gethtypes.Transaction) (*update, bool, error) {
+	data := tx.Data()
+	if tx.To() == nil || *tx.To() != m.chain.ContractConfig.GetIBCHandlerAddress() ||
+		len(data) < 4 || !bytes.Equal(data[:4], abiUpdateClient.ID) {
+		return nil, false, nil
+	}
+	values, err := abiUpdateClient.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, false, err
+	}
+	msg := *abi.ConvertType(values[0], new(ibchandler.IBCMsgsMsgUpdateClient)).(*ibchandler.IBCMsgsMsgUpdateClient)
+	if msg.ClientId != m.clientID {
+		return nil, false, nil
+	}
+	var header ibft2clienttypes.Header
+	if err := convert.UnmarshalWithAny(msg.Header, &header); err != nil {
+		return nil, false, err
+	}
+	var base gethtypes.Header
+	if err := rlp.DecodeBytes(header.BesuHeaderRlp, &base); err != nil {
+		return nil, false, err
+	}
+	parsed, err := chains.ParseSealingHeader(&base, header.Seals)
+	if err != nil {
+		return nil, false, err
+	}
+	return &update{header: parsed, trustedHeight: header.TrustedHeight}, true, nil
+}
+
+// isTrustedHeader returns true if at least 1/3 of the validators of the consensus state at the trusted height
+// sealed the header, as IBFT2Client.verifyCommitSealsTrusting requires.
+func (m *MisbehaviourMonitor) isTrustedHeader(ctx context.Context, u *update) (bool, error) {
+	bz, found, err := m.chain.IBCHost.GetConsensusState(m.chain.CallOpts(ctx), m.clientID, u.trustedHeight)
+	if err != nil {
+		return false, err
+	} else if !found {
+		return false, nil
+	}
+	var consensusState ibft2clienttypes.ConsensusState
+	if err := convert.UnmarshalWithAny(bz, &consensusState); err != nil {
+		return false, err
+	}
+	hash, err := sealingHash(u.header)
+	if err != nil {
+		return false, err
+	}
+	signers, err := chains.RecoverCommitterAddressesVals(hash.Bytes(), u.header.Seals)
+	if err != nil {
+		// the client rejects the header with an invalid seal
+		return false, nil
+	}
+	trusted, remaining := countRemainingValidators(consensusState.Validators, signerAddresses(signers))
+	return isTrusted(trusted, remaining), nil
+}
+
+func signerAddresses(signers map[common.Address][]byte) []common.Address {
+	addrs := make([]common.Address, 0, len(signers))
+	for addr := range signers {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// checkHeader compares the validly sealed header with the canonical header of the counterparty chain and
+// the headers submitted before at the same height, and compares the root of the consensus state stored for
+// the header with the canonical storage root of the IBC store.
+func (m *MisbehaviourMonitor) checkHeader(ctx context.Context, txHash common.Hash, u *update) ([]Evidence, error) {
+	header := u.header
+	if _, err := header.ValidateAndGetCommitSeals(); err != nil {
+		return nil, nil
+	}
+	if ok, err := m.isTrustedHeader(ctx, u); err != nil || !ok {
+		return nil, err
+	}
+	hash, err := sealingHash(header)
+	if err != nil {
+		return nil, err
+	}
+	height := header.Base.Number.Uint64()
+	var evidences []Evidence
+
+	if prev, ok := m.submitted[height]; ok && prev.hash != hash {
+		evidences = append(evidences, Evidence{
+			Type:        MisbehaviourConflictingHeader,
+			ClientID:    m.clientID,
+			Height:      height,
+			TxHash:      txHash,
+			Submitted:   header,
+			Conflicting: prev.header,
+		})
+	} else if !ok {
+		m.submitted[height] = &submittedHeader{header: header, hash: hash}
+	}
+
+	block, err := m.counterparty.Client().BlockByNumber(ctx, header.Base.Number)
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := chains.ParseHeader(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	canonicalHash, err := sealingHash(canonical)
+	if err != nil {
+		return nil, err
+	}
+	if canonicalHash != hash {
+		return append(evidences, Evidence{
+			Type:        MisbehaviourConflictingHeader,
+			ClientID:    m.clientID,
+			Height:      height,
+			TxHash:      txHash,
+			Submitted:   header,
+			Conflicting: canonical,
+		}), nil
+	}
+
+	bz, found, err := m.chain.IBCHost.GetConsensusState(m.chain.CallOpts(ctx), m.clientID, height)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		// the transaction may have failed
+		return evidences, nil
+	}
+	var consensusState ibft2clienttypes.ConsensusState
+	if err := convert.UnmarshalWithAny(bz, &consensusState); err != nil {
+		return nil, err
+	}
+	root, err := m.counterparty.Client().GetStorageRoot(ctx, m.counterparty.IBCHostAddress(), header.Base.Number)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(consensusState.Root, root.Bytes()) {
+		evidences = append(evidences, Evidence{
+			Type:          MisbehaviourRootMismatch,
+			ClientID:      m.clientID,
+			Height:        height,
+			TxHash:        txHash,
+			Submitted:     header,
+			StoredRoot:    consensusState.Root,
+			CanonicalRoot: root.Bytes(),
+		})
+	}
+	return evidences, nil
+}
+
+// sealingHash returns the hash of the header signed by the validators, which identifies the header regardless of its seals.
+func sealingHash(header *chains.ParsedHeader) (common.Hash, error) {
+	bz, err := header.GetSealingHeaderBytes()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(bz), nil
+}
+
+func formatSealedHeader(header *chains.ParsedHeader) string {
+	if header == nil {
+		return "none"
+	}
+	hash, err := sealingHash(header)
+	if err != nil {
+		return fmt.Sprintf("invalid(%v)", err)
+	}
+	return fmt.Sprintf("{hash=%v root=%v seals=%v}", hash.Hex(), header.Base.Root.Hex(), len(header.Seals))
+}
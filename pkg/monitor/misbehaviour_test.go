@@ -0,0 +1,286 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/chains"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client/simulated"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/consts"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchost"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	ibft2clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/ibft2"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+)
+
+func TestSubmittedHeader(t *testing.T) {
+	chain, err := host.NewChain(client.Client{}, "2018", consts.Contract)
+	require.NoError(t, err)
+	m := NewMisbehaviourMonitor(chain, chain, "hyperledger-besu-ibft2-0")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	var (
+		vanity [32]byte
+		round  [4]byte
+	)
+	extra, err := rlp.EncodeToBytes([]interface{}{vanity, []common.Address{crypto.PubkeyToAddress(key.PublicKey)}, []interface{}{}, round})
+	require.NoError(t, err)
+	bz, err := rlp.EncodeToBytes(&gethtypes.Header{Number: big.NewInt(10), Difficulty: big.NewInt(1), Extra: extra})
+	require.NoError(t, err)
+	seal, err := crypto.Sign(crypto.Keccak256(bz), key)
+	require.NoError(t, err)
+
+	updateClient := func(clientID string) *gethtypes.Transaction {
+		header, err := convert.MarshalWithAny(&ibft2clienttypes.Header{BesuHeaderRlp: bz, Seals: [][]byte{seal}, TrustedHeight: 1})
+		require.NoError(t, err)
+		args, err := abiUpdateClient.Inputs.Pack(ibchandler.IBCMsgsMsgUpdateClient{ClientId: clientID, Header: header})
+		require.NoError(t, err)
+		return gethtypes.NewTransaction(0, consts.Contract.GetIBCHandlerAddress(), big.NewInt(0), 0, big.NewInt(0), append(abiUpdateClient.ID, args...))
+	}
+
+	u, ok, err := m.submittedUpdate(updateClient(m.clientID))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), u.trustedHeight)
+	header := u.header
+	require.Equal(t, uint64(10), header.Base.Number.Uint64())
+	require.Equal(t, [][]byte{seal}, header.Seals)
+	_, err = header.ValidateAndGetCommitSeals()
+	require.NoError(t, err)
+	hash, err := sealingHash(header)
+	require.NoError(t, err)
+	require.Equal(t, crypto.Keccak256Hash(bz), hash)
+
+	// the updates of other clients and the other transactions are ignored
+	_, ok, err = m.submittedUpdate(updateClient("hyperledger-besu-ibft2-1"))
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, ok, err = m.submittedUpdate(gethtypes.NewTransaction(0, consts.Contract.GetIBCHandlerAddress(), big.NewInt(0), 0, big.NewInt(0), nil))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestMisbehaviourEvidence(t *testing.T) {
+	ctx := context.Background()
+	validators, err := chains.GenerateValidatorKeys(8)
+	require.NoError(t, err)
+	consensus := chains.IBFT2Consensus{Validators: validators[:4]}
+
+	// the counterparty chain is sealed by the first 4 validators, which the client trusts
+	cpClient := simulated.NewIBFT2Client(core.GenesisAlloc{}, testGasLimit, chains.NewIBFT2Sealer(consensus))
+	counterparty, err := host.NewChain(*cpClient, "1337", consts.Contract)
+	require.NoError(t, err)
+	var canonical []*gethtypes.Header
+	for i := 0; i < 4; i++ {
+		block, err := cpClient.BlockByNumber(ctx, nil)
+		require.NoError(t, err)
+		canonical = append(canonical, block.Header())
+	}
+
+	// the consensus state of any height has a root that differs from the one of the counterparty chain
+	var trustedValidators [][]byte
+	for _, addr := range consensus.ValidatorAddresses() {
+		trustedValidators = append(trustedValidators, addr.Bytes())
+	}
+	chain, opts := newStubbedChain(t, &ibft2clienttypes.ClientState{LatestHeight: 1}, &ibft2clienttypes.ConsensusState{Root: common.Hash{0xff}.Bytes(), Validators: trustedValidators})
+	clientID := "hyperledger-besu-ibft2-0"
+	submit := func(header *gethtypes.Header, fail bool) common.Hash {
+		return submitHeader(t, chain, opts, clientID, header, fail)
+	}
+	conflicting := func(header *gethtypes.Header, consensus chains.IBFT2Consensus) *gethtypes.Header {
+		sealed, err := consensus.SealConflicting(header, func(header *gethtypes.Header) { header.Root = common.Hash{0xee} })
+		require.NoError(t, err)
+		return sealed
+	}
+
+	// the canonical header whose consensus state has a wrong root
+	rootMismatch := submit(canonical[0], false)
+	// the header conflicting with the canonical one, which is sealed by the trusted validators
+	conflictingHeader := submit(conflicting(canonical[1], consensus), false)
+	// the header of the failed transaction is ignored
+	submit(conflicting(canonical[2], consensus), true)
+	// the header sealed by the validators that are not trusted is ignored
+	submit(conflicting(canonical[3], chains.IBFT2Consensus{Validators: validators[4:]}), false)
+
+	head, err := chain.Client().BlockByNumber(ctx, nil)
+	require.NoError(t, err)
+	evidences, err := NewMisbehaviourMonitor(chain, counterparty, clientID).Check(ctx, 1, head.NumberU64())
+	require.NoError(t, err)
+	require.Len(t, evidences, 2)
+
+	require.Equal(t, MisbehaviourRootMismatch, evidences[0].Type)
+	require.Equal(t, rootMismatch, evidences[0].TxHash)
+	require.Equal(t, canonical[0].Number.Uint64(), evidences[0].Height)
+	require.Equal(t, common.Hash{0xff}.Bytes(), evidences[0].StoredRoot)
+	require.Equal(t, gethtypes.EmptyRootHash.Bytes(), evidences[0].CanonicalRoot)
+
+	require.Equal(t, MisbehaviourConflictingHeader, evidences[1].Type)
+	require.Equal(t, conflictingHeader, evidences[1].TxHash)
+	require.Equal(t, canonical[1].Number.Uint64(), evidences[1].Height)
+	require.Equal(t, common.Hash{0xee}, evidences[1].Submitted.Base.Root)
+	require.Equal(t, canonical[1].Root, evidences[1].Conflicting.Base.Root)
+}
+
+func TestMisbehaviourMonitorEquivocation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	validators, err := chains.GenerateValidatorKeys(4)
+	require.NoError(t, err)
+	sealer := chains.NewIBFT2Sealer(chains.IBFT2Consensus{Validators: validators})
+	cpClient := simulated.NewIBFT2Client(core.GenesisAlloc{}, testGasLimit, sealer)
+	counterparty, err := host.NewChain(*cpClient, "1337", consts.Contract)
+	require.NoError(t, err)
+	block, err := cpClient.BlockByNumber(ctx, nil)
+	require.NoError(t, err)
+	header := block.Header()
+
+	// the consensus state of any height has the canonical root, so only the conflicting header is evidence
+	var trustedValidators [][]byte
+	for _, addr := range sealer.Consensus(header.Number.Uint64()).ValidatorAddresses() {
+		trustedValidators = append(trustedValidators, addr.Bytes())
+	}
+	chain, opts := newStubbedChain(t, &ibft2clienttypes.ClientState{LatestHeight: 1}, &ibft2clienttypes.ConsensusState{Root: gethtypes.EmptyRootHash.Bytes(), Validators: trustedValidators})
+	clientID := "hyperledger-besu-ibft2-0"
+
+	evidences := make(chan Evidence, 8)
+	m := NewMisbehaviourMonitor(chain, counterparty, clientID)
+	m.PollInterval = 10 * time.Millisecond
+	m.Alert = func(evidence Evidence) { evidences <- evidence }
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- m.Run(runCtx, 1) }()
+	defer func() {
+		stop()
+		require.ErrorIs(t, <-done, context.Canceled)
+	}()
+
+	// the validators of the counterparty chain commit another block at the height of the canonical one
+	submitHeader(t, chain, opts, clientID, header, false)
+	conflicting, err := sealer.SealConflicting(header, func(header *gethtypes.Header) { header.Time++ })
+	require.NoError(t, err)
+	txHash := submitHeader(t, chain, opts, clientID, conflicting, false)
+
+	// the conflicting header differs from both the header submitted before and the canonical one
+	for i := 0; i < 2; i++ {
+		select {
+		case evidence := <-evidences:
+			require.Equal(t, MisbehaviourConflictingHeader, evidence.Type)
+			require.Equal(t, txHash, evidence.TxHash)
+			require.Equal(t, header.Number.Uint64(), evidence.Height)
+			require.Equal(t, conflicting.Time, evidence.Submitted.Base.Time)
+			require.Equal(t, header.Time, evidence.Conflicting.Base.Time)
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+	select {
+	case evidence := <-evidences:
+		t.Fatalf("unexpected evidence: %v", evidence)
+	case <-time.After(10 * m.PollInterval):
+	}
+}
+
+// submitHeader submits the header to the IBFT2 client on the chain stubbed by newStubbedChain, trusting the height 1.
+// The transaction fails if fail is true. The hash of the transaction is returned.
+func submitHeader(t *testing.T, chain *host.Chain, opts client.GenTxOpts, clientID string, header *gethtypes.Header, fail bool) common.Hash {
+	ctx := context.Background()
+	parsed, err := chains.ParseHeader(header)
+	require.NoError(t, err)
+	seals, err := parsed.ValidateAndGetCommitSeals()
+	require.NoError(t, err)
+	bz, err := parsed.GetSealingHeaderBytes()
+	require.NoError(t, err)
+	any, err := convert.MarshalWithAny(&ibft2clienttypes.Header{BesuHeaderRlp: bz, Seals: seals, TrustedHeight: 1})
+	require.NoError(t, err)
+	txOpts := opts(ctx)
+	if fail {
+		// the stubbed handler reverts the calls with value
+		txOpts.Value = big.NewInt(1)
+	}
+	tx, err := chain.IBCHandler.UpdateClient(txOpts, ibchandler.IBCMsgsMsgUpdateClient{ClientId: clientID, Header: any})
+	require.NoError(t, err)
+	rc, err := chain.Client().WaitForReceiptAndGet(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, !fail, rc.Status() == gethtypes.ReceiptStatusSuccessful)
+	return tx.Hash()
+}
+
+// testGasLimit is the gas limit of the blocks of the simulated chains, which is above the gas limit of the transactions.
+const testGasLimit = 10_000_000
+
+// newStubbedChain returns a simulated chain whose IBCHost is stubbed to return the client state and the consensus
+// state of any height for any IBFT2 client, and whose IBCHandler is stubbed to accept any call without value.
+// The options of the transactions are signed by an account funded on the chain.
+func newStubbedChain(t *testing.T, clientState *ibft2clienttypes.ClientState, consensusState *ibft2clienttypes.ConsensusState) (*host.Chain, client.GenTxOpts) {
+	hostABI, err := abi.JSON(strings.NewReader(ibchost.IbchostABI))
+	require.NoError(t, err)
+	clientType, err := hostABI.Methods["getClientType"].Outputs.Pack(ibcclient.BesuIBFT2Client)
+	require.NoError(t, err)
+	bz, err := convert.MarshalWithAny(clientState)
+	require.NoError(t, err)
+	clientStateResult, err := hostABI.Methods["getClientState"].Outputs.Pack(bz, true)
+	require.NoError(t, err)
+	bz, err = convert.MarshalWithAny(consensusState)
+	require.NoError(t, err)
+	consensusStateResult, err := hostABI.Methods["getConsensusState"].Outputs.Pack(bz, true)
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	alloc := core.GenesisAlloc{
+		crypto.PubkeyToAddress(key.PublicKey): {Balance: big.NewInt(1e18)},
+		consts.Contract.GetIBCHostAddress(): {Balance: new(big.Int), Code: stubCode(
+			[][]byte{hostABI.Methods["getClientType"].ID, hostABI.Methods["getClientState"].ID, hostABI.Methods["getConsensusState"].ID},
+			[][]byte{clientType, clientStateResult, consensusStateResult},
+		)},
+		// CALLVALUE PUSH1 5 JUMPI STOP JUMPDEST PUSH1 0 DUP1 REVERT
+		consts.Contract.GetIBCHandlerAddress(): {Balance: new(big.Int), Code: []byte{0x34, 0x60, 0x05, 0x57, 0x00, 0x5b, 0x60, 0x00, 0x80, 0xfd}},
+	}
+	cl := simulated.NewClient(alloc, testGasLimit, ibcclient.MockClient)
+	chain, err := host.NewChain(*cl, "1337", consts.Contract)
+	require.NoError(t, err)
+	return chain, client.MakeGenTxOpts(big.NewInt(1337), key)
+}
+
+// stubCode returns the code returning the results to the calls of the methods of the IDs, and reverting the other calls.
+func stubCode(ids [][]byte, results [][]byte) []byte {
+	const (
+		dispatchSize = 11 // DUP1 PUSH4 id EQ PUSH2 dest JUMPI
+		returnSize   = 16 // JUMPDEST PUSH2 size PUSH2 offset PUSH1 0 CODECOPY PUSH2 size PUSH1 0 RETURN
+	)
+	// PUSH1 0 CALLDATALOAD PUSH1 0xe0 SHR
+	code := []byte{0x60, 0x00, 0x35, 0x60, 0xe0, 0x1c}
+	returnsAt := len(code) + dispatchSize*len(ids) + 4
+	for i, id := range ids {
+		dest := returnsAt + returnSize*i
+		code = append(code, 0x80, 0x63, id[0], id[1], id[2], id[3], 0x14, 0x61, byte(dest>>8), byte(dest), 0x57)
+	}
+	// PUSH1 0 DUP1 REVERT
+	code = append(code, 0x60, 0x00, 0x80, 0xfd)
+	offset := returnsAt + returnSize*len(ids)
+	for _, result := range results {
+		size := len(result)
+		code = append(code, 0x5b, 0x61, byte(size>>8), byte(size), 0x61, byte(offset>>8), byte(offset), 0x60, 0x00, 0x39,
+			0x61, byte(size>>8), byte(size), 0x60, 0x00, 0xf3)
+		offset += size
+	}
+	for _, result := range results {
+		code = append(code, result...)
+	}
+	return code
+}
@@ -0,0 +1,272 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/chains"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	ibft2clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/ibft2"
+	mockclienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/mock"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/metrics"
+)
+
+// DefaultMaxLag is the default number of blocks a client may lag behind the head of the counterparty chain.
+const DefaultMaxLag uint64 = 100
+
+// ClientStatus is the status of a client compared with the head of the counterparty chain it tracks.
+type ClientStatus struct {
+	ClientID   string
+	ClientType string
+	// LatestHeight is the latest height of the counterparty chain the client has been updated to.
+	LatestHeight uint64
+	// HeadHeight is the height of the head of the counterparty chain.
+	HeadHeight uint64
+	// Stale is true if the client lags behind the head by more than the maximum lag.
+	Stale bool
+
+	// TrustedValidators is the number of the validators in the consensus state at the latest height,
+	// and RemainingValidators is the number of them still in the validator set of the head.
+	// They are set for the IBFT2 client only.
+	TrustedValidators   int
+	RemainingValidators int
+	// ValidatorSetAtRisk is true if more than 1/3 of the trusted validators have left the validator set,
+	// so that a few more changes make the client unupdatable.
+	ValidatorSetAtRisk bool
+	// Unupdatable is true if the client cannot be updated to the head directly since less than 1/3 of the
+	// trusted validators remain, which are required to sign the header. The client must be updated through
+	// the intermediate headers in that case.
+	Unupdatable bool
+
+	// Refreshed is true if the client has been updated to the head by the monitor.
+	Refreshed bool
+}
+
+// Lag returns the number of blocks the client lags behind the head of the counterparty chain.
+func (s ClientStatus) Lag() uint64 {
+	if s.HeadHeight < s.LatestHeight {
+		return 0
+	}
+	return s.HeadHeight - s.LatestHeight
+}
+
+// Healthy returns true if the client needs no attention.
+func (s ClientStatus) Healthy() bool {
+	return !s.Stale && !s.ValidatorSetAtRisk && !s.Unupdatable
+}
+
+func (s ClientStatus) String() string {
+	var warnings []string
+	if s.Stale {
+		warnings = append(warnings, "stale")
+	}
+	if s.Unupdatable {
+		warnings = append(warnings, "unupdatable")
+	} else if s.ValidatorSetAtRisk {
+		warnings = append(warnings, "validator-set-at-risk")
+	}
+	if s.Refreshed {
+		warnings = append(warnings, "refreshed")
+	}
+	str := fmt.Sprintf("client=%v type=%v latest=%v head=%v lag=%v", s.ClientID, s.ClientType, s.LatestHeight, s.HeadHeight, s.Lag())
+	if s.ClientType == ibcclient.BesuIBFT2Client {
+		str += fmt.Sprintf(" validators=%v/%v", s.RemainingValidators, s.TrustedValidators)
+	}
+	if len(warnings) > 0 {
+		str += fmt.Sprintf(" warnings=%v", strings.Join(warnings, ","))
+	}
+	return str
+}
+
+// StatusFunc is called with the status of a client that needs attention.
+type StatusFunc func(status ClientStatus)
+
+// StalenessMonitor periodically compares the latest heights of the clients on the chain with the head
+// of the counterparty chain they track, and watches the validator set of the counterparty chain changing
+// away from the one trusted by the IBFT2 clients.
+type StalenessMonitor struct {
+	chain        *host.Chain
+	counterparty *host.Chain
+
+	// ClientIDs are the clients to monitor. If it is empty, all the clients on the chain tracking
+	// the counterparty chain are monitored. The mock client holds no identity of the tracked chain,
+	// so every mock client is taken as tracking the counterparty chain.
+	ClientIDs []string
+	// MaxLag is the number of blocks a client may lag behind the head before it is considered stale.
+	MaxLag uint64
+	// AutoRefresh updates the clients that are stale or whose trusted validator set is at risk to the head.
+	AutoRefresh bool
+	// TxOpts generates the options of the transactions updating the clients, which is required by AutoRefresh.
+	TxOpts client.GenTxOpts
+	// Alert is called with the status of each client that needs attention if it is not nil.
+	Alert StatusFunc
+	// PollInterval is the interval to check the clients.
+	PollInterval time.Duration
+}
+
+// NewStalenessMonitor creates a new StalenessMonitor of the clients on the chain tracking the counterparty chain.
+func NewStalenessMonitor(chain, counterparty *host.Chain) *StalenessMonitor {
+	return &StalenessMonitor{
+		chain:        chain,
+		counterparty: counterparty,
+		MaxLag:       DefaultMaxLag,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// Run checks the clients every poll interval until the context is done.
+func (m *StalenessMonitor) Run(ctx context.Context) error {
+	for {
+		statuses, err := m.Check(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if !status.Healthy() && m.Alert != nil {
+				m.Alert(status)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.PollInterval):
+		}
+	}
+}
+
+// Check returns the status of each client. The clients that need attention are updated to the head
+// if AutoRefresh is enabled, except for the unupdatable ones.
+func (m *StalenessMonitor) Check(ctx context.Context) ([]ClientStatus, error) {
+	head, err := m.counterparty.Client().BlockByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var headValidators []common.Address
+	if m.counterparty.ClientType() == ibcclient.BesuIBFT2Client {
+		parsed, err := chains.ParseHeader(head.Header())
+		if err != nil {
+			return nil, err
+		}
+		headValidators = parsed.Validators
+	}
+
+	clientIDs := m.ClientIDs
+	if len(clientIDs) == 0 {
+		if clientIDs, err = m.chain.GetClientIDs(ctx); err != nil {
+			return nil, err
+		}
+	}
+	var statuses []ClientStatus
+	for _, clientID := range clientIDs {
+		status, ok, err := m.clientStatus(ctx, clientID, head.NumberU64(), headValidators)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		metrics.GetRecorder().SetClientLag(m.chain.ChainIDString(), clientID, status.Lag())
+		if m.AutoRefresh && !status.Unupdatable && (status.Stale || status.ValidatorSetAtRisk) {
+			if err := m.refresh(ctx, status); err != nil {
+				return nil, fmt.Errorf("failed to refresh the client %v: %w", clientID, err)
+			}
+			status.Refreshed = true
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// clientStatus returns the status of the client. false is returned if the client is not given explicitly
+// and does not track the counterparty chain.
+func (m *StalenessMonitor) clientStatus(ctx context.Context, clientID string, headHeight uint64, headValidators []common.Address) (ClientStatus, bool, error) {
+	opts := m.chain.CallOpts(ctx)
+	status := ClientStatus{ClientID: clientID, HeadHeight: headHeight}
+	clientType, err := m.chain.IBCHost.GetClientType(opts, clientID)
+	if err != nil {
+		return status, false, err
+	}
+	status.ClientType = clientType
+	bz, found, err := m.chain.IBCHost.GetClientState(opts, clientID)
+	if err != nil {
+		return status, false, err
+	} else if !found {
+		return status, false, fmt.Errorf("client not found: %v", clientID)
+	}
+
+	switch clientType {
+	case ibcclient.MockClient:
+		var cs mockclienttypes.ClientState
+		if err := convert.UnmarshalWithAny(bz, &cs); err != nil {
+			return status, false, err
+		}
+		status.LatestHeight = cs.LatestHeight
+	case ibcclient.BesuIBFT2Client:
+		var cs ibft2clienttypes.ClientState
+		if err := convert.UnmarshalWithAny(bz, &cs); err != nil {
+			return status, false, err
+		}
+		if len(m.ClientIDs) == 0 && (cs.ChainId != m.counterparty.ChainIDString() || !bytes.Equal(cs.IbcStoreAddress, m.counterparty.IBCHostAddress().Bytes())) {
+			return status, false, nil
+		}
+		status.LatestHeight = cs.LatestHeight
+		bz, found, err := m.chain.IBCHost.GetConsensusState(opts, clientID, cs.LatestHeight)
+		if err != nil {
+			return status, false, err
+		} else if !found {
+			return status, false, fmt.Errorf("consensus state not found: client=%v height=%v", clientID, cs.LatestHeight)
+		}
+		var consensusState ibft2clienttypes.ConsensusState
+		if err := convert.UnmarshalWithAny(bz, &consensusState); err != nil {
+			return status, false, err
+		}
+		status.TrustedValidators, status.RemainingValidators = countRemainingValidators(consensusState.Validators, headValidators)
+		status.Unupdatable = !isTrusted(status.TrustedValidators, status.RemainingValidators)
+		status.ValidatorSetAtRisk = status.RemainingValidators*3 < status.TrustedValidators*2
+	default:
+		if len(m.ClientIDs) == 0 {
+			return status, false, nil
+		}
+		return status, false, fmt.Errorf("unknown client type: '%v'", clientType)
+	}
+	status.Stale = status.Lag() > m.MaxLag
+	return status, true, nil
+}
+
+// refresh updates the client to the head of the counterparty chain the status was taken at.
+func (m *StalenessMonitor) refresh(ctx context.Context, status ClientStatus) error {
+	if m.TxOpts == nil {
+		return errors.New("no transaction options to refresh the client")
+	}
+	return m.chain.UpdateClient(ctx, m.TxOpts(ctx), m.counterparty, status.ClientID, new(big.Int).SetUint64(status.HeadHeight))
+}
+
+// countRemainingValidators returns the number of the trusted validators, and the number of them in the validator set of the head.
+func countRemainingValidators(trusted [][]byte, head []common.Address) (int, int) {
+	current := make(map[common.Address]bool)
+	for _, val := range head {
+		current[val] = true
+	}
+	remaining := 0
+	for _, val := range trusted {
+		if current[common.BytesToAddress(val)] {
+			remaining++
+		}
+	}
+	return len(trusted), remaining
+}
+
+// isTrusted returns true if the remaining validators can sign a header trusted by the IBFT2 client, which
+// requires the signatures of at least 1/3 of the trusted validators as IBFT2Client.verifyCommitSealsTrusting does.
+func isTrusted(trusted, remaining int) bool {
+	return remaining >= trusted*1/3
+}
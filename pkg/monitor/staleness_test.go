@@ -0,0 +1,123 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/chains"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client/simulated"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/consts"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	ibft2clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/ibft2"
+)
+
+func TestValidatorSetChange(t *testing.T) {
+	vals := make([]common.Address, 6)
+	trusted := make([][]byte, len(vals))
+	for i := range vals {
+		vals[i] = common.BytesToAddress([]byte{byte(i + 1)})
+		trusted[i] = vals[i].Bytes()
+	}
+
+	var cases = []struct {
+		head        []common.Address
+		remaining   int
+		atRisk      bool
+		unupdatable bool
+	}{
+		{vals, 6, false, false},
+		{vals[2:], 4, false, false},
+		{append(vals[3:], common.HexToAddress("0xff")), 3, true, false},
+		{vals[4:], 2, true, false},
+		{vals[5:], 1, true, true},
+		{nil, 0, true, true},
+	}
+	for i, c := range cases {
+		total, remaining := countRemainingValidators(trusted, c.head)
+		require.Equal(t, len(trusted), total, i)
+		require.Equal(t, c.remaining, remaining, i)
+		require.Equal(t, c.atRisk, remaining*3 < total*2, i)
+		require.Equal(t, c.unupdatable, !isTrusted(total, remaining), i)
+	}
+}
+
+func TestClientStatus(t *testing.T) {
+	status := ClientStatus{ClientID: "mock-client-0", ClientType: "mock-client", LatestHeight: 10, HeadHeight: 5}
+	require.Zero(t, status.Lag())
+	require.True(t, status.Healthy())
+	require.Equal(t, "client=mock-client-0 type=mock-client latest=10 head=5 lag=0", status.String())
+
+	status.HeadHeight, status.Stale = 200, true
+	require.Equal(t, uint64(190), status.Lag())
+	require.False(t, status.Healthy())
+	require.Equal(t, "client=mock-client-0 type=mock-client latest=10 head=200 lag=190 warnings=stale", status.String())
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	validators, err := chains.GenerateValidatorKeys(4)
+	require.NoError(t, err)
+	consensus := chains.IBFT2Consensus{Validators: validators}
+	cpClient := simulated.NewIBFT2Client(core.GenesisAlloc{}, testGasLimit, chains.NewIBFT2Sealer(consensus))
+	counterparty, err := host.NewChain(*cpClient, "1337", consts.Contract)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := cpClient.BlockByNumber(ctx, nil)
+		require.NoError(t, err)
+	}
+
+	// the client has been updated to the first block
+	var trustedValidators [][]byte
+	for _, addr := range consensus.ValidatorAddresses() {
+		trustedValidators = append(trustedValidators, addr.Bytes())
+	}
+	chain, opts := newStubbedChain(t, &ibft2clienttypes.ClientState{LatestHeight: 1}, &ibft2clienttypes.ConsensusState{Validators: trustedValidators})
+	clientID := "hyperledger-besu-ibft2-0"
+	m := NewStalenessMonitor(chain, counterparty)
+	m.ClientIDs = []string{clientID}
+	m.MaxLag = 1
+	m.AutoRefresh = true
+
+	// the client cannot be refreshed without the options of the transactions
+	_, err = m.Check(ctx)
+	require.Error(t, err)
+
+	// the failure of the transaction is returned
+	m.TxOpts = func(ctx context.Context) *bind.TransactOpts {
+		txOpts := opts(ctx)
+		// the stubbed handler reverts the calls with value
+		txOpts.Value = big.NewInt(1)
+		return txOpts
+	}
+	_, err = m.Check(ctx)
+	require.Error(t, err)
+
+	m.TxOpts = opts
+	statuses, err := m.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	status := statuses[0]
+	require.True(t, status.Stale)
+	require.True(t, status.Refreshed)
+	require.Equal(t, 4, status.RemainingValidators)
+
+	// the header of the head is submitted to the client, which is verified against its latest height
+	block, err := chain.Client().BlockByNumber(ctx, nil)
+	require.NoError(t, err)
+	parent, err := chain.Client().BlockByNumber(ctx, new(big.Int).Sub(block.Number(), big.NewInt(1)))
+	require.NoError(t, err)
+	require.Len(t, parent.Transactions(), 1)
+	u, ok, err := NewMisbehaviourMonitor(chain, counterparty, clientID).submittedUpdate(parent.Transactions()[0])
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, status.HeadHeight, u.header.Base.Number.Uint64())
+	require.Equal(t, uint64(1), u.trustedHeight)
+	_, err = u.header.ValidateAndGetCommitSeals()
+	require.NoError(t, err)
+}
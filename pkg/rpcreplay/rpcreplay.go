@@ -0,0 +1,275 @@
+// Package rpcreplay records the JSON-RPC calls sent to a node and replays them without the node.
+//
+// A Recorder is an http.RoundTripper that sends the calls to the node and keeps each call with its reply,
+// which are saved to a fixture file. A Replayer is an http.RoundTripper that replies to the calls with the
+// replies in a fixture file, so that the code decoding the replies can be tested with no network.
+// Both are given to a client by client.WithTransport.
+package rpcreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Interaction is a JSON-RPC call and its reply.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a JSON-RPC call without its ID.
+type Request struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is the reply to a JSON-RPC call without its ID.
+type Response struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// Fixture is the content of a fixture file.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load reads the fixture file.
+func Load(path string) (*Fixture, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(bz, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode the fixture %v: %v", path, err)
+	}
+	return &fixture, nil
+}
+
+// Save writes the fixture file.
+func (f Fixture) Save(path string) error {
+	bz, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(bz, '\n'), 0644)
+}
+
+type jsonrpcMessage struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// key returns the key matching the call with the recorded ones, which ignores the formatting of the parameters.
+func (r Request) key() (string, error) {
+	params := r.Params
+	if len(params) > 0 {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, params); err != nil {
+			return "", err
+		}
+		params = buf.Bytes()
+	}
+	return r.Method + string(params), nil
+}
+
+// Recorder is an http.RoundTripper that records the JSON-RPC calls sent through it with their replies.
+type Recorder struct {
+	// Base is the underlying transport. http.DefaultTransport is used if it is nil.
+	Base http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// NewRecorder returns a Recorder sending the calls through the base transport.
+func NewRecorder(base http.RoundTripper) *Recorder {
+	return &Recorder{Base: base}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Body == nil {
+		return base.RoundTrip(req)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	calls, _, err := decodeMessages(body)
+	if err != nil {
+		// not a JSON-RPC request
+		return base.RoundTrip(req)
+	}
+
+	res, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res, nil
+	}
+	replies, _, err := decodeMessages(resBody)
+	if err != nil {
+		return res, nil
+	}
+	byID := make(map[string]jsonrpcMessage)
+	for _, reply := range replies {
+		byID[string(reply.ID)] = reply
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, call := range calls {
+		reply, ok := byID[string(call.ID)]
+		if !ok {
+			continue
+		}
+		r.interactions = append(r.interactions, Interaction{
+			Request:  Request{Method: call.Method, Params: call.Params},
+			Response: Response{Result: reply.Result, Error: reply.Error},
+		})
+	}
+	return res, nil
+}
+
+// Fixture returns the calls recorded so far.
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Fixture{Interactions: append([]Interaction{}, r.interactions...)}
+}
+
+// Save writes the calls recorded so far to the fixture file.
+func (r *Recorder) Save(path string) error {
+	return r.Fixture().Save(path)
+}
+
+// Replayer is an http.RoundTripper that replies to the JSON-RPC calls with the recorded replies.
+// The calls with the same method and parameters are replied in the recorded order, and the last
+// reply is repeated once they are exhausted. A call never recorded fails with an error.
+type Replayer struct {
+	mu      sync.Mutex
+	replies map[string][]Response
+}
+
+var _ http.RoundTripper = (*Replayer)(nil)
+
+// NewReplayer returns a Replayer replying with the interactions of the fixture.
+func NewReplayer(fixture *Fixture) (*Replayer, error) {
+	r := &Replayer{replies: make(map[string][]Response)}
+	for _, i := range fixture.Interactions {
+		key, err := i.Request.key()
+		if err != nil {
+			return nil, err
+		}
+		r.replies[key] = append(r.replies[key], i.Response)
+	}
+	return r, nil
+}
+
+// NewReplayerFromFile returns a Replayer replying with the interactions of the fixture file.
+func NewReplayerFromFile(path string) (*Replayer, error) {
+	fixture, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(fixture)
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return nil, fmt.Errorf("no JSON-RPC call in the request to %v", req.URL)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	calls, batch, err := decodeMessages(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the JSON-RPC call: %v", err)
+	}
+	var replies []jsonrpcMessage
+	for _, call := range calls {
+		res, err := r.reply(Request{Method: call.Method, Params: call.Params})
+		if err != nil {
+			return nil, err
+		}
+		replies = append(replies, jsonrpcMessage{Version: "2.0", ID: call.ID, Result: res.Result, Error: res.Error})
+	}
+	var resBody []byte
+	if batch {
+		resBody, err = json.Marshal(replies)
+	} else {
+		resBody, err = json.Marshal(replies[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(resBody)),
+		ContentLength: int64(len(resBody)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Replayer) reply(req Request) (Response, error) {
+	key, err := req.key()
+	if err != nil {
+		return Response{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	replies := r.replies[key]
+	if len(replies) == 0 {
+		return Response{}, fmt.Errorf("no recorded reply to the call: %v", key)
+	}
+	if len(replies) > 1 {
+		r.replies[key] = replies[1:]
+	}
+	return replies[0], nil
+}
+
+// decodeMessages decodes a JSON-RPC message or a batch of them.
+func decodeMessages(bz []byte) (msgs []jsonrpcMessage, batch bool, err error) {
+	bz = bytes.TrimSpace(bz)
+	if len(bz) > 0 && bz[0] == '[' {
+		if err := json.Unmarshal(bz, &msgs); err != nil {
+			return nil, false, err
+		}
+		return msgs, true, nil
+	}
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(bz, &msg); err != nil {
+		return nil, false, err
+	}
+	return []jsonrpcMessage{msg}, false, nil
+}
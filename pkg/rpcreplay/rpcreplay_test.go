@@ -0,0 +1,101 @@
+package rpcreplay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// newCounterNode returns a JSON-RPC server replying to "counter" with the number of the calls so far,
+// and to the other methods with an error.
+func newCounterNode() *httptest.Server {
+	calls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		msgs, batch, err := decodeMessages(readAll(req))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var replies []jsonrpcMessage
+		for _, msg := range msgs {
+			reply := jsonrpcMessage{Version: "2.0", ID: msg.ID}
+			if msg.Method == "counter" {
+				calls++
+				reply.Result = json.RawMessage(fmt.Sprint(calls))
+			} else {
+				reply.Error = json.RawMessage(`{"code":-32601,"message":"method not found"}`)
+			}
+			replies = append(replies, reply)
+		}
+		if batch {
+			json.NewEncoder(w).Encode(replies)
+		} else {
+			json.NewEncoder(w).Encode(replies[0])
+		}
+	}))
+}
+
+func readAll(req *http.Request) []byte {
+	var bz json.RawMessage
+	json.NewDecoder(req.Body).Decode(&bz)
+	return bz
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	node := newCounterNode()
+	defer node.Close()
+
+	recorder := NewRecorder(nil)
+	conn, err := rpc.DialHTTPWithClient(node.URL, &http.Client{Transport: recorder})
+	require.NoError(t, err)
+	var n int
+	require.NoError(t, conn.CallContext(ctx, &n, "counter", "a"))
+	require.Equal(t, 1, n)
+	require.NoError(t, conn.CallContext(ctx, &n, "counter", "a"))
+	require.Equal(t, 2, n)
+	require.Error(t, conn.CallContext(ctx, &n, "unknown"))
+	batch := []rpc.BatchElem{
+		{Method: "counter", Args: []interface{}{"b"}, Result: new(int)},
+		{Method: "counter", Args: []interface{}{"c"}, Result: new(int)},
+	}
+	require.NoError(t, conn.BatchCallContext(ctx, batch))
+	require.Len(t, recorder.Fixture().Interactions, 5)
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, recorder.Save(path))
+	replayer, err := NewReplayerFromFile(path)
+	require.NoError(t, err)
+	conn, err = rpc.DialHTTPWithClient("http://replay", &http.Client{Transport: replayer})
+	require.NoError(t, err)
+
+	// the calls with the same parameters are replied in the recorded order, and the last reply is repeated
+	for _, expected := range []int{1, 2, 2} {
+		require.NoError(t, conn.CallContext(ctx, &n, "counter", "a"))
+		require.Equal(t, expected, n)
+	}
+	err = conn.CallContext(ctx, &n, "unknown")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "method not found")
+
+	// the calls in a batch are replied one by one
+	batch = []rpc.BatchElem{
+		{Method: "counter", Args: []interface{}{"c"}, Result: new(int)},
+		{Method: "counter", Args: []interface{}{"b"}, Result: new(int)},
+	}
+	require.NoError(t, conn.BatchCallContext(ctx, batch))
+	require.NoError(t, batch[0].Error)
+	require.Equal(t, 4, *batch[0].Result.(*int))
+	require.NoError(t, batch[1].Error)
+	require.Equal(t, 3, *batch[1].Result.(*int))
+
+	// a call never recorded fails
+	require.Error(t, conn.CallContext(ctx, &n, "counter", "d"))
+}
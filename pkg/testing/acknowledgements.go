@@ -0,0 +1,126 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/tracing"
+)
+
+// ErrAcknowledgementNotFound is returned when the acknowledgement of a packet has not been written yet.
+var ErrAcknowledgementNotFound = host.ErrAcknowledgementNotFound
+
+// ackPollInterval is the interval to poll the acknowledgement of a packet acknowledged asynchronously.
+const ackPollInterval = 500 * time.Millisecond
+
+// DefaultAcknowledgementTimeout is how long WaitForAcknowledgement waits if the context has no deadline.
+const DefaultAcknowledgementTimeout = 1 * time.Minute
+
+// WaitForAcknowledgement waits until the acknowledgement of the packet is written on this chain,
+// which may happen in a later transaction than the one receiving the packet if the app acknowledges
+// it asynchronously through IBCHandler.WriteAcknowledgement. It gives up at the deadline of the context,
+// or after DefaultAcknowledgementTimeout if the context has none.
+func (chain *Chain) WaitForAcknowledgement(ctx context.Context, packet channeltypes.Packet) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultAcknowledgementTimeout)
+		defer cancel()
+	}
+	for {
+		ack, err := chain.FindAcknowledgement(ctx, packet.DestinationPort, packet.DestinationChannel, packet.Sequence)
+		if err == nil {
+			return ack, nil
+		} else if !errors.Is(err, ErrAcknowledgementNotFound) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("acknowledgement not written: %v: %w", FormatPacket(packet), ctx.Err())
+		case <-time.After(ackPollInterval):
+		}
+	}
+}
+
+// RecvPacket receives the packet on the source chain and updates the client of the source chain on
+// the counterparty chain. The acknowledgement written in the same transaction is returned, or nil if
+// the app acknowledges the packet asynchronously.
+func (c *Coordinator) RecvPacket(
+	ctx context.Context,
+	source, counterparty *Chain,
+	sourceChannel, counterpartyChannel TestChannel,
+	packet channeltypes.Packet,
+) ([]byte, error) {
+	ctx = tracing.ContextWithPacket(ctx, PacketRoute(counterparty, source), packet)
+	ack, err := source.RecvPacket(ctx, counterparty, sourceChannel, counterpartyChannel, packet)
+	if err != nil {
+		return nil, err
+	}
+	source.UpdateHeader()
+
+	// update source client on counterparty connection
+	if err := c.UpdateClient(ctx, counterparty, source, counterpartyChannel.ClientID); err != nil {
+		return nil, err
+	}
+	return ack, nil
+}
+
+// RelayAcknowledgement waits for the acknowledgement of the packet sent from the source chain to be
+// written on the counterparty chain, and then acknowledges the packet on the source chain with it.
+// The client of the counterparty chain is updated on the source chain beforehand since the acknowledgement
+// may have been written asynchronously, and the delay period of the source channel is waited for.
+// The decoded acknowledgement is returned so that the caller can check whether the packet succeeded.
+func (c *Coordinator) RelayAcknowledgement(
+	ctx context.Context,
+	source, counterparty *Chain,
+	sourceChannel, counterpartyChannel TestChannel,
+	packet channeltypes.Packet,
+) (app.Acknowledgement, error) {
+	ctx = tracing.ContextWithPacket(ctx, PacketRoute(source, counterparty), packet)
+	bz, err := counterparty.WaitForAcknowledgement(ctx, packet)
+	if err != nil {
+		return app.Acknowledgement{}, err
+	}
+	ack, err := app.DecodeAcknowledgement(bz)
+	if err != nil {
+		return app.Acknowledgement{}, err
+	}
+	counterparty.UpdateHeader()
+	if err := c.UpdateClient(ctx, source, counterparty, sourceChannel.ClientID); err != nil {
+		return app.Acknowledgement{}, err
+	}
+	if err := source.WaitForDelayPeriod(ctx, sourceChannel, nil); err != nil {
+		return app.Acknowledgement{}, err
+	}
+	if err := c.HandlePacketAcknowledgement(ctx, source, counterparty, sourceChannel, counterpartyChannel, packet, bz); err != nil {
+		return app.Acknowledgement{}, err
+	}
+	return ack, nil
+}
+
+// RelayPacket relays the packet sent from the source chain to the counterparty chain, and then relays
+// its acknowledgement back to the source chain. The delay period of the counterparty channel is waited
+// for before the packet is received. The decoded acknowledgement is returned.
+func (c *Coordinator) RelayPacket(
+	ctx context.Context,
+	source, counterparty *Chain,
+	sourceChannel, counterpartyChannel TestChannel,
+	packet channeltypes.Packet,
+) (app.Acknowledgement, error) {
+	ctx = tracing.ContextWithPacket(ctx, PacketRoute(source, counterparty), packet)
+	source.UpdateHeader()
+	if err := c.UpdateClient(ctx, counterparty, source, counterpartyChannel.ClientID); err != nil {
+		return app.Acknowledgement{}, err
+	}
+	if err := counterparty.WaitForDelayPeriod(ctx, counterpartyChannel, nil); err != nil {
+		return app.Acknowledgement{}, err
+	}
+	if _, err := c.RecvPacket(ctx, counterparty, source, counterpartyChannel, sourceChannel, packet); err != nil {
+		return app.Acknowledgement{}, err
+	}
+	return c.RelayAcknowledgement(ctx, source, counterparty, sourceChannel, counterpartyChannel, packet)
+}
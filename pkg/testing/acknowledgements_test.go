@@ -0,0 +1,80 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client/simulated"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+)
+
+func TestWaitForAcknowledgement(t *testing.T) {
+	// the IBC handler is stubbed by the code emitting the WriteAcknowledgement event with the calldata,
+	// which simulates the app acknowledging a packet asynchronously in a later transaction
+	// CALLDATASIZE PUSH1 0 PUSH1 0 CALLDATACOPY PUSH32 topic CALLDATASIZE PUSH1 0 LOG1 STOP
+	handler := common.HexToAddress("0x12")
+	code := append([]byte{0x36, 0x60, 0x00, 0x60, 0x00, 0x37, 0x7f}, abiWriteAcknowledgement.ID.Bytes()...)
+	code = append(code, 0x36, 0x60, 0x00, 0xa1, 0x00)
+	alloc, err := simulatedAlloc(testMnemonicPhrase, 1)
+	require.NoError(t, err)
+	alloc[handler] = core.GenesisAccount{Code: code, Balance: new(big.Int)}
+	cl := simulated.NewClient(alloc, simulatedGasLimit, ibcclient.MockClient)
+	chain := NewChain(t, SimulatedChainID, *cl, DeployedContracts{IBCHandler: handler}, testMnemonicPhrase, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	writeAcknowledgement := func(sequence uint64, ack []byte) {
+		data, err := abiWriteAcknowledgement.Inputs.Pack("transfer", "channel-1", sequence, ack)
+		require.NoError(t, err)
+		contract := bind.NewBoundContract(handler, abi.ABI{}, cl, cl, cl)
+		require.NoError(t, chain.WaitIfNoError(ctx)(contract.RawTransact(chain.TxOpts(ctx, RelayerKeyIndex), data)))
+	}
+
+	packet := channeltypes.NewPacket(nil, 1, "transfer", "channel-0", "transfer", "channel-1", channeltypes.Height{}, 0)
+	_, err = chain.FindAcknowledgement(ctx, packet.DestinationPort, packet.DestinationChannel, packet.Sequence)
+	require.True(t, errors.Is(err, ErrAcknowledgementNotFound), err)
+
+	type result struct {
+		ack []byte
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ack, err := chain.WaitForAcknowledgement(ctx, packet)
+		done <- result{ack, err}
+	}()
+
+	// the acknowledgement of another packet is not waited for
+	writeAcknowledgement(2, []byte("other"))
+	select {
+	case r := <-done:
+		t.Fatalf("returned before the acknowledgement is written: ack=%x err=%v", r.ack, r.err)
+	case <-time.After(2 * ackPollInterval):
+	}
+
+	writeAcknowledgement(1, []byte("ack"))
+	select {
+	case r := <-done:
+		require.NoError(t, r.err)
+		require.Equal(t, []byte("ack"), r.ack)
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	// the wait for an acknowledgement never written ends at the deadline
+	deadlineCtx, cancelDeadline := context.WithTimeout(ctx, 2*ackPollInterval)
+	defer cancelDeadline()
+	packet.Sequence = 3
+	_, err = chain.WaitForAcknowledgement(deadlineCtx, packet)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
@@ -12,13 +12,13 @@ import (
 	"testing"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
 	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
@@ -27,10 +27,14 @@ import (
 	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ics20bank"
 	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ics20transferbank"
 	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/simpletoken"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app"
 	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
 	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
 	ibft2clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/ibft2"
 	mockclienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/mock"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/convert"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/tracing"
 	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/wallet"
 )
 
@@ -38,32 +42,19 @@ const (
 	DefaultChannelVersion        = "ics20-1"
 	BlockTime             uint64 = 1000 * 1000 * 1000 // 1[sec]
 	DefaultDelayPeriod    uint64 = 3 * BlockTime
-	DefaultPrefix                = "ibc"
 	TransferPort                 = "transfer"
 
 	RelayerKeyIndex uint32 = 0
 )
 
-var (
-	abiSendPacket,
-	abiGeneratedClientIdentifier,
-	abiGeneratedConnectionIdentifier,
-	abiGeneratedChannelIdentifier abi.Event
-)
+var abiWriteAcknowledgement abi.Event
 
 func init() {
 	parsedHandlerABI, err := abi.JSON(strings.NewReader(ibchandler.IbchandlerABI))
 	if err != nil {
 		panic(err)
 	}
-	parsedHostABI, err := abi.JSON(strings.NewReader(ibchost.IbchostABI))
-	if err != nil {
-		panic(err)
-	}
-	abiSendPacket = parsedHandlerABI.Events["SendPacket"]
-	abiGeneratedClientIdentifier = parsedHostABI.Events["GeneratedClientIdentifier"]
-	abiGeneratedConnectionIdentifier = parsedHostABI.Events["GeneratedConnectionIdentifier"]
-	abiGeneratedChannelIdentifier = parsedHostABI.Events["GeneratedChannelIdentifier"]
+	abiWriteAcknowledgement = parsedHandlerABI.Events["WriteAcknowledgement"]
 }
 
 type Chain struct {
@@ -81,8 +72,12 @@ type Chain struct {
 	ICS20Bank     ics20bank.Ics20bank
 
 	chainID int64
+	// ibcChainID is the chain ID used by IBC, which is the EIP-155 chain ID
+	ibcChainID string
 
 	ContractConfig ContractConfig
+	// host is the chain bound to the same client and contracts, which records the metrics of the transactions.
+	host *host.Chain
 
 	mnemonicPhrase string
 	keys           map[uint32]*ecdsa.PrivateKey
@@ -94,6 +89,9 @@ type Chain struct {
 	ClientIDs   []string          // ClientID's used on this chain
 	Connections []*TestConnection // track connectionID's created for this chain
 	IBCID       uint64
+
+	// counterparties are the chains tracked by the clients created on this chain, by the client IDs
+	counterparties map[string]*Chain
 }
 
 type ContractConfig interface {
@@ -109,47 +107,26 @@ type ContractConfig interface {
 }
 
 func NewChain(t *testing.T, chainID int64, client client.Client, config ContractConfig, mnemonicPhrase string, ibcID uint64) *Chain {
-	ibcHost, err := ibchost.NewIbchost(config.GetIBCHostAddress(), client)
-	if err != nil {
-		t.Error(err)
-	}
-	ibcHandler, err := ibchandler.NewIbchandler(config.GetIBCHandlerAddress(), client)
-	if err != nil {
-		t.Error(err)
-	}
-	ibcIdentifier, err := ibcidentifier.NewIbcidentifier(config.GetIBCIdentifierAddress(), client)
-	if err != nil {
-		t.Error(err)
-	}
-	simpletoken, err := simpletoken.NewSimpletoken(config.GetSimpleTokenAddress(), client)
-	if err != nil {
-		t.Error(err)
-	}
-	ics20transfer, err := ics20transferbank.NewIcs20transferbank(config.GetICS20TransferBankAddress(), client)
-	if err != nil {
-		t.Error(err)
-	}
-	ics20bank, err := ics20bank.NewIcs20bank(config.GetICS20BankAddress(), client)
-	if err != nil {
-		t.Error(err)
-	}
-
-	return &Chain{
+	chain := &Chain{
 		t:              t,
 		client:         client,
 		chainID:        chainID,
-		ContractConfig: config,
+		ibcChainID:     fmt.Sprint(chainID),
 		mnemonicPhrase: mnemonicPhrase,
 		keys:           make(map[uint32]*ecdsa.PrivateKey),
 		IBCID:          ibcID,
-
-		IBCHost:       *ibcHost,
-		IBCHandler:    *ibcHandler,
-		IBCIdentifier: *ibcIdentifier,
-		SimpleToken:   *simpletoken,
-		ICS20Transfer: *ics20transfer,
-		ICS20Bank:     *ics20bank,
+		counterparties: make(map[string]*Chain),
+	}
+	if err := chain.SetContractConfig(config); err != nil {
+		t.Error(err)
 	}
+	return chain
+}
+
+// Host returns the chain bound to the same client and contracts as this chain, which is passed to
+// the tools inspecting running chains, such as the audit and the monitors.
+func (chain *Chain) Host() *host.Chain {
+	return chain.host
 }
 
 func (chain *Chain) Client() client.Client {
@@ -189,12 +166,84 @@ func (chain *Chain) ChainID() int64 {
 	return chain.chainID
 }
 
+// ChainIDString returns the chain ID used by IBC, which is the EIP-155 chain ID.
 func (chain *Chain) ChainIDString() string {
-	return fmt.Sprint(chain.chainID)
+	return chain.ibcChainID
+}
+
+// RevisionNumber returns the revision number parsed from the chain ID used by IBC. The EIP-155 chain ID
+// has no revision, so this is 0, as the contracts assume by comparing only the revision heights.
+func (chain *Chain) RevisionNumber() uint64 {
+	return channeltypes.ParseChainID(chain.ibcChainID)
+}
+
+// NewHeight returns the height on this chain for the given block number.
+func (chain *Chain) NewHeight(blockNumber uint64) channeltypes.Height {
+	return channeltypes.NewHeight(chain.RevisionNumber(), blockNumber)
 }
 
+// GetCommitmentPrefix returns the commitment prefix of the IBC host of this chain, which is passed to the
+// counterparty chain on the connection handshake. It defaults to host.DefaultPrefix.
 func (chain *Chain) GetCommitmentPrefix() []byte {
-	return []byte(DefaultPrefix)
+	prefix, err := chain.host.GetCommitmentPrefix(context.Background())
+	require.NoError(chain.t, err)
+	return prefix
+}
+
+// SetCommitmentPrefix sets the commitment prefix of the IBC host of this chain, which is allowed to the deployer
+// of the contracts until a connection is created on this chain.
+func (chain *Chain) SetCommitmentPrefix(ctx context.Context, prefix []byte) error {
+	return chain.WaitIfNoError(ctx)(
+		chain.IBCHandler.SetCommitmentPrefix(chain.TxOpts(ctx, RelayerKeyIndex), prefix),
+	)
+}
+
+// IBCHostAddress returns the address of the IBC host of this chain in the ContractConfig.
+func (chain *Chain) IBCHostAddress() common.Address {
+	return chain.ContractConfig.GetIBCHostAddress()
+}
+
+// SetContractConfig binds the contracts of this chain to the addresses in the config. This allows several
+// IBC stacks deployed on the same network to be used as different chains. The storage of the IBC host is
+// proven to the counterparty chain, and the events of the IBC host and the IBC handler are queried.
+func (chain *Chain) SetContractConfig(config ContractConfig) error {
+	ibcHost, err := ibchost.NewIbchost(config.GetIBCHostAddress(), chain.client)
+	if err != nil {
+		return err
+	}
+	ibcHandler, err := ibchandler.NewIbchandler(config.GetIBCHandlerAddress(), chain.client)
+	if err != nil {
+		return err
+	}
+	ibcIdentifier, err := ibcidentifier.NewIbcidentifier(config.GetIBCIdentifierAddress(), chain.client)
+	if err != nil {
+		return err
+	}
+	simpletoken, err := simpletoken.NewSimpletoken(config.GetSimpleTokenAddress(), chain.client)
+	if err != nil {
+		return err
+	}
+	ics20transfer, err := ics20transferbank.NewIcs20transferbank(config.GetICS20TransferBankAddress(), chain.client)
+	if err != nil {
+		return err
+	}
+	ics20bank, err := ics20bank.NewIcs20bank(config.GetICS20BankAddress(), chain.client)
+	if err != nil {
+		return err
+	}
+	h, err := host.NewChain(chain.client, chain.ibcChainID, config)
+	if err != nil {
+		return err
+	}
+	chain.ContractConfig = config
+	chain.host = h
+	chain.IBCHost = *ibcHost
+	chain.IBCHandler = *ibcHandler
+	chain.IBCIdentifier = *ibcIdentifier
+	chain.SimpleToken = *simpletoken
+	chain.ICS20Transfer = *ics20transfer
+	chain.ICS20Bank = *ics20bank
+	return nil
 }
 
 func (chain *Chain) GetIBFT2ClientState(clientID string) *ibft2clienttypes.ClientState {
@@ -240,7 +289,7 @@ func (chain *Chain) GetContractState(counterparty *Chain, counterpartyClientID s
 	}
 	return chain.client.GetContractState(
 		context.Background(),
-		chain.ContractConfig.GetIBCHostAddress(),
+		chain.IBCHostAddress(),
 		storageKeys,
 		height,
 	)
@@ -272,7 +321,7 @@ func (chain *Chain) ConstructMockMsgCreateClient(counterparty *Chain) ibchandler
 func (chain *Chain) ConstructIBFT2MsgCreateClient(counterparty *Chain) ibchandler.IBCMsgsMsgCreateClient {
 	clientState := ibft2clienttypes.ClientState{
 		ChainId:         counterparty.ChainIDString(),
-		IbcStoreAddress: counterparty.ContractConfig.GetIBCHostAddress().Bytes(),
+		IbcStoreAddress: counterparty.IBCHostAddress().Bytes(),
 		LatestHeight:    counterparty.LastHeader().Number.Uint64(),
 	}
 	consensusState := ibft2clienttypes.ConsensusState{
@@ -335,7 +384,7 @@ func (chain *Chain) UpdateHeader() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 	for {
-		state, err := chain.client.GetContractState(ctx, chain.ContractConfig.GetIBCHostAddress(), nil, nil)
+		state, err := chain.client.GetContractState(ctx, chain.IBCHostAddress(), nil, nil)
 		if err != nil {
 			panic(err)
 		}
@@ -349,13 +398,23 @@ func (chain *Chain) UpdateHeader() {
 }
 
 func (chain *Chain) CreateMockClient(ctx context.Context, counterparty *Chain) (string, error) {
-	msg := chain.ConstructMockMsgCreateClient(counterparty)
+	return chain.createClient(ctx, counterparty, chain.ConstructMockMsgCreateClient(counterparty))
+}
+
+// createClient creates the client tracking the counterparty chain, which is kept to trace the packets
+// relayed through the channels on the client.
+func (chain *Chain) createClient(ctx context.Context, counterparty *Chain, msg ibchandler.IBCMsgsMsgCreateClient) (string, error) {
 	if err := chain.WaitIfNoError(ctx)(
 		chain.IBCHandler.CreateClient(chain.TxOpts(ctx, RelayerKeyIndex), msg),
 	); err != nil {
 		return "", err
 	}
-	return chain.GetLastGeneratedClientID(ctx)
+	clientID, err := chain.GetLastGeneratedClientID(ctx)
+	if err != nil {
+		return "", err
+	}
+	chain.counterparties[clientID] = counterparty
+	return clientID, nil
 }
 
 func (chain *Chain) UpdateMockClient(ctx context.Context, counterparty *Chain, clientID string) error {
@@ -366,13 +425,7 @@ func (chain *Chain) UpdateMockClient(ctx context.Context, counterparty *Chain, c
 }
 
 func (chain *Chain) CreateIBFT2Client(ctx context.Context, counterparty *Chain) (string, error) {
-	msg := chain.ConstructIBFT2MsgCreateClient(counterparty)
-	if err := chain.WaitIfNoError(ctx)(
-		chain.IBCHandler.CreateClient(chain.TxOpts(ctx, RelayerKeyIndex), msg),
-	); err != nil {
-		return "", err
-	}
-	return chain.GetLastGeneratedClientID(ctx)
+	return chain.createClient(ctx, counterparty, chain.ConstructIBFT2MsgCreateClient(counterparty))
 }
 
 func (chain *Chain) UpdateIBFT2Client(ctx context.Context, counterparty *Chain, clientID string) error {
@@ -407,7 +460,15 @@ func (chain *Chain) ConnectionOpenTry(ctx context.Context, counterparty *Chain,
 	if err != nil {
 		return "", err
 	}
-	clientStateBytes, proofClient, err := counterparty.QueryClientProof(chain, counterpartyConnection.ClientID, big.NewInt(int64(proofConnection.Height)))
+	clientStateBytes, proofClient, err := counterparty.QueryClientProof(chain, counterpartyConnection.ClientID, new(big.Int).SetUint64(proofConnection.Height.RevisionHeight))
+	if err != nil {
+		return "", err
+	}
+	consensusHeight, err := counterparty.clientLatestHeight(chain, clientStateBytes)
+	if err != nil {
+		return "", err
+	}
+	_, proofConsensus, err := counterparty.QueryConsensusStateProof(chain, counterpartyConnection.ClientID, consensusHeight.RevisionHeight, new(big.Int).SetUint64(proofConnection.Height.RevisionHeight))
 	if err != nil {
 		return "", err
 	}
@@ -427,9 +488,11 @@ func (chain *Chain) ConnectionOpenTry(ctx context.Context, counterparty *Chain,
 				CounterpartyVersions: []ibchandler.VersionData{
 					{Identifier: "1", Features: []string{"ORDER_ORDERED", "ORDER_UNORDERED"}},
 				},
-				ProofHeight: proofConnection.Height,
-				ProofInit:   proofConnection.Data,
-				ProofClient: proofClient.Data,
+				ProofHeight:     proofConnection.Height.RevisionHeight,
+				ProofInit:       proofConnection.Data,
+				ProofClient:     proofClient.Data,
+				ProofConsensus:  proofConsensus.Data,
+				ConsensusHeight: consensusHeight.RevisionHeight,
 			},
 		),
 	); err != nil {
@@ -448,7 +511,15 @@ func (chain *Chain) ConnectionOpenAck(
 	if err != nil {
 		return err
 	}
-	clientStateBytes, proofClient, err := counterparty.QueryClientProof(chain, counterpartyConnection.ClientID, big.NewInt(int64(proofConnection.Height)))
+	clientStateBytes, proofClient, err := counterparty.QueryClientProof(chain, counterpartyConnection.ClientID, new(big.Int).SetUint64(proofConnection.Height.RevisionHeight))
+	if err != nil {
+		return err
+	}
+	consensusHeight, err := counterparty.clientLatestHeight(chain, clientStateBytes)
+	if err != nil {
+		return err
+	}
+	_, proofConsensus, err := counterparty.QueryConsensusStateProof(chain, counterpartyConnection.ClientID, consensusHeight.RevisionHeight, new(big.Int).SetUint64(proofConnection.Height.RevisionHeight))
 	if err != nil {
 		return err
 	}
@@ -460,9 +531,11 @@ func (chain *Chain) ConnectionOpenAck(
 				CounterpartyConnectionID: counterpartyConnection.ID,
 				ClientStateBytes:         clientStateBytes,
 				Version:                  ibchandler.VersionData{Identifier: "1", Features: []string{"ORDER_ORDERED", "ORDER_UNORDERED"}},
-				ProofHeight:              proofConnection.Height,
+				ProofHeight:              proofConnection.Height.RevisionHeight,
 				ProofTry:                 proofConnection.Data,
 				ProofClient:              proofClient.Data,
+				ProofConsensus:           proofConsensus.Data,
+				ConsensusHeight:          consensusHeight.RevisionHeight,
 			},
 		),
 	)
@@ -483,7 +556,7 @@ func (chain *Chain) ConnectionOpenConfirm(
 			ibchandler.IBCMsgsMsgConnectionOpenConfirm{
 				ConnectionId: connection.ID,
 				ProofAck:     proof.Data,
-				ProofHeight:  proof.Height,
+				ProofHeight:  proof.Height.RevisionHeight,
 			},
 		),
 	)
@@ -546,7 +619,7 @@ func (chain *Chain) ChannelOpenTry(
 				},
 				CounterpartyVersion: counterpartyCh.Version,
 				ProofInit:           proof.Data,
-				ProofHeight:         proof.Height,
+				ProofHeight:         proof.Height.RevisionHeight,
 			},
 		),
 	); err != nil {
@@ -573,7 +646,7 @@ func (chain *Chain) ChannelOpenAck(
 				CounterpartyVersion:   counterpartyCh.Version,
 				CounterpartyChannelId: counterpartyCh.ID,
 				ProofTry:              proof.Data,
-				ProofHeight:           proof.Height,
+				ProofHeight:           proof.Height.RevisionHeight,
 			},
 		),
 	)
@@ -595,7 +668,7 @@ func (chain *Chain) ChannelOpenConfirm(
 				PortId:      ch.PortID,
 				ChannelId:   ch.ID,
 				ProofAck:    proof.Data,
-				ProofHeight: proof.Height,
+				ProofHeight: proof.Height.RevisionHeight,
 			},
 		),
 	)
@@ -632,7 +705,7 @@ func (chain *Chain) ChannelCloseConfirm(
 				PortId:      ch.PortID,
 				ChannelId:   ch.ID,
 				ProofInit:   proof.Data,
-				ProofHeight: proof.Height,
+				ProofHeight: proof.Height.RevisionHeight,
 			},
 		),
 	)
@@ -645,7 +718,7 @@ func (chain *Chain) SendPacket(
 	return chain.WaitIfNoError(ctx)(
 		chain.IBCHandler.SendPacket(
 			chain.TxOpts(ctx, RelayerKeyIndex),
-			packetToCallData(packet),
+			convert.PacketToCallData(packet),
 		),
 	)
 }
@@ -656,24 +729,46 @@ func (chain *Chain) HandlePacketRecv(
 	ch, counterpartyCh TestChannel,
 	packet channeltypes.Packet,
 ) error {
-	proof, err := counterparty.QueryProof(chain, ch.ClientID, chain.PacketCommitmentSlot(packet.SourcePort, packet.SourceChannel, packet.Sequence), nil)
+	_, err := chain.RecvPacket(ctx, counterparty, ch, counterpartyCh, packet)
+	return err
+}
+
+// RecvPacket receives the packet like HandlePacketRecv and returns the acknowledgement written in the same
+// transaction. nil is returned as the acknowledgement if the app acknowledges the packet asynchronously.
+func (chain *Chain) RecvPacket(
+	ctx context.Context,
+	counterparty *Chain,
+	ch, counterpartyCh TestChannel,
+	packet channeltypes.Packet,
+) ([]byte, error) {
+	if err := chain.checkPacketTimeout(ctx, packet); err != nil {
+		return nil, err
+	}
+	ctx = tracing.ContextWithPacket(ctx, PacketRoute(counterparty, chain), packet)
+	proof, err := counterparty.queryPacketProof(ctx, chain, ch.ClientID, chain.PacketCommitmentSlot(packet.SourcePort, packet.SourceChannel, packet.Sequence))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	switch chain.ClientType() {
 	case ibcclient.MockClient:
-		proof.Data = commitPacket(packet)
-	}
-	return chain.WaitIfNoError(ctx)(
-		chain.IBCHandler.RecvPacket(
-			chain.TxOpts(ctx, RelayerKeyIndex),
-			ibchandler.IBCMsgsMsgPacketRecv{
-				Packet:      packetToCallData(packet),
-				Proof:       proof.Data,
-				ProofHeight: proof.Height,
-			},
-		),
+		proof.Data = channeltypes.CommitPacket(packet)
+	}
+	tx, err := chain.IBCHandler.RecvPacket(
+		chain.TxOpts(ctx, RelayerKeyIndex),
+		ibchandler.IBCMsgsMsgPacketRecv{
+			Packet:      convert.PacketToCallData(packet),
+			Proof:       proof.Data,
+			ProofHeight: proof.Height.RevisionHeight,
+		},
 	)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := chain.waitForReceipt(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive packet: packet=[%v]: %w", FormatPacket(packet), err)
+	}
+	return chain.findAcknowledgementInLogs(rc.Logs(), packet.DestinationPort, packet.DestinationChannel, packet.Sequence)
 }
 
 func (chain *Chain) HandlePacketAcknowledgement(
@@ -683,68 +778,63 @@ func (chain *Chain) HandlePacketAcknowledgement(
 	packet channeltypes.Packet,
 	acknowledgement []byte,
 ) error {
-	proof, err := counterparty.QueryProof(chain, ch.ClientID, chain.PacketAcknowledgementCommitmentSlot(packet.DestinationPort, packet.DestinationChannel, packet.Sequence), nil)
+	ctx = tracing.ContextWithPacket(ctx, PacketRoute(chain, counterparty), packet)
+	proof, err := counterparty.queryPacketProof(ctx, chain, ch.ClientID, chain.PacketAcknowledgementCommitmentSlot(packet.DestinationPort, packet.DestinationChannel, packet.Sequence))
 	if err != nil {
 		return err
 	}
 	switch chain.ClientType() {
 	case ibcclient.MockClient:
-		proof.Data = commitAcknowledgement(acknowledgement)
+		proof.Data = channeltypes.CommitAcknowledgement(acknowledgement)
 	}
-	return chain.WaitIfNoError(ctx)(
+	if err := chain.WaitIfNoError(ctx)(
 		chain.IBCHandler.AcknowledgePacket(
 			chain.TxOpts(ctx, RelayerKeyIndex),
 			ibchandler.IBCMsgsMsgPacketAcknowledgement{
-				Packet:          packetToCallData(packet),
+				Packet:          convert.PacketToCallData(packet),
 				Acknowledgement: acknowledgement,
 				Proof:           proof.Data,
-				ProofHeight:     proof.Height,
+				ProofHeight:     proof.Height.RevisionHeight,
 			},
 		),
-	)
+	); err != nil {
+		return fmt.Errorf("failed to acknowledge packet: packet=[%v] acknowledgement={%v}: %w",
+			FormatPacket(packet), app.FormatAcknowledgement(packet.DestinationPort, acknowledgement), err)
+	}
+	return nil
 }
 
 func (chain *Chain) GetLastGeneratedClientID(
 	ctx context.Context,
 ) (string, error) {
-	return chain.getLastID(ctx, abiGeneratedClientIdentifier)
+	return lastID(chain.host.GetClientIDs(ctx))
+}
+
+// GetClientIDs returns the IDs of all the clients created on this chain in the order of creation.
+func (chain *Chain) GetClientIDs(ctx context.Context) ([]string, error) {
+	return chain.host.GetClientIDs(ctx)
 }
 
 func (chain *Chain) GetLastGeneratedConnectionID(
 	ctx context.Context,
 ) (string, error) {
-	return chain.getLastID(ctx, abiGeneratedConnectionIdentifier)
+	return lastID(chain.host.GetConnectionIDs(ctx))
 }
 
 func (chain *Chain) GetLastGeneratedChannelID(
 	ctx context.Context,
 ) (string, error) {
-	return chain.getLastID(ctx, abiGeneratedChannelIdentifier)
+	return lastID(chain.host.GetChannelIDs(ctx))
 }
 
-func (chain *Chain) getLastID(ctx context.Context, event abi.Event) (string, error) {
-	query := ethereum.FilterQuery{
-		FromBlock: big.NewInt(0),
-		Addresses: []common.Address{
-			chain.ContractConfig.GetIBCHostAddress(),
-		},
-		Topics: [][]common.Hash{{
-			event.ID,
-		}},
-	}
-	logs, err := chain.client.FilterLogs(ctx, query)
+func lastID(ids []string, err error) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if len(logs) == 0 {
+	if len(ids) == 0 {
 		return "", errors.New("no items")
 	}
-	log := logs[len(logs)-1]
-	values, err := event.Inputs.Unpack(log.Data)
-	if err != nil {
-		return "", err
-	}
-	return values[0].(string), nil
+	return ids[len(ids)-1], nil
 }
 
 func (chain *Chain) GetLastSentPacket(
@@ -765,66 +855,40 @@ func (chain *Chain) FindPacket(
 	sourceChannel string,
 	sequence uint64,
 ) (*channeltypes.Packet, error) {
-	query := ethereum.FilterQuery{
-		FromBlock: big.NewInt(0),
-		Addresses: []common.Address{
-			chain.ContractConfig.GetIBCHandlerAddress(),
-		},
-		Topics: [][]common.Hash{{
-			abiSendPacket.ID,
-		}},
-	}
-	logs, err := chain.client.FilterLogs(ctx, query)
-	if err != nil {
-		return nil, err
-	}
+	return chain.host.FindPacket(ctx, sourcePortID, sourceChannel, sequence)
+}
 
+// FindAcknowledgement returns the acknowledgement written for the packet identified by the
+// given destination port, channel and sequence. ErrAcknowledgementNotFound is returned if the
+// acknowledgement has not been written yet.
+func (chain *Chain) FindAcknowledgement(
+	ctx context.Context,
+	destinationPortID string,
+	destinationChannel string,
+	sequence uint64,
+) ([]byte, error) {
+	return chain.host.FindAcknowledgement(ctx, destinationPortID, destinationChannel, sequence)
+}
+
+// findAcknowledgementInLogs returns the acknowledgement in the WriteAcknowledgement event of the given logs.
+// nil is returned if no such event is found.
+func (chain *Chain) findAcknowledgementInLogs(
+	logs []*gethtypes.Log,
+	destinationPortID string,
+	destinationChannel string,
+	sequence uint64,
+) ([]byte, error) {
 	for _, log := range logs {
-		if values, err := abiSendPacket.Inputs.Unpack(log.Data); err != nil {
+		if log.Address != chain.ContractConfig.GetIBCHandlerAddress() || len(log.Topics) == 0 || log.Topics[0] != abiWriteAcknowledgement.ID {
+			continue
+		}
+		if values, err := abiWriteAcknowledgement.Inputs.Unpack(log.Data); err != nil {
 			return nil, err
-		} else {
-			p := values[0].(struct {
-				Sequence           uint64  "json:\"sequence\""
-				SourcePort         string  "json:\"source_port\""
-				SourceChannel      string  "json:\"source_channel\""
-				DestinationPort    string  "json:\"destination_port\""
-				DestinationChannel string  "json:\"destination_channel\""
-				Data               []uint8 "json:\"data\""
-				TimeoutHeight      struct {
-					RevisionNumber uint64 "json:\"revision_number\""
-					RevisionHeight uint64 "json:\"revision_height\""
-				} "json:\"timeout_height\""
-				TimeoutTimestamp uint64 "json:\"timeout_timestamp\""
-			})
-			if p.SourcePort == sourcePortID && p.SourceChannel == sourceChannel && p.Sequence == sequence {
-				return &channeltypes.Packet{
-					Sequence:           p.Sequence,
-					SourcePort:         p.SourcePort,
-					SourceChannel:      p.SourceChannel,
-					DestinationPort:    p.DestinationPort,
-					DestinationChannel: p.DestinationChannel,
-					Data:               p.Data,
-					TimeoutHeight:      channeltypes.Height(p.TimeoutHeight),
-					TimeoutTimestamp:   p.TimeoutTimestamp,
-				}, nil
-			}
+		} else if values[0].(string) == destinationPortID && values[1].(string) == destinationChannel && values[2].(uint64) == sequence {
+			return values[3].([]byte), nil
 		}
 	}
-
-	return nil, fmt.Errorf("packet not found: sourcePortID=%v sourceChannel=%v sequence=%v", sourcePortID, sourceChannel, sequence)
-}
-
-func packetToCallData(packet channeltypes.Packet) ibchandler.PacketData {
-	return ibchandler.PacketData{
-		Sequence:           packet.Sequence,
-		SourcePort:         packet.SourcePort,
-		SourceChannel:      packet.SourceChannel,
-		DestinationPort:    packet.DestinationPort,
-		DestinationChannel: packet.DestinationChannel,
-		Data:               packet.Data,
-		TimeoutHeight:      ibchandler.HeightData(packet.TimeoutHeight),
-		TimeoutTimestamp:   packet.TimeoutTimestamp,
-	}
+	return nil, nil
 }
 
 // Slot calculator
@@ -835,6 +899,12 @@ func (chain *Chain) ClientStateCommitmentSlot(clientID string) string {
 	return "0x" + hex.EncodeToString(key[:])
 }
 
+func (chain *Chain) ConsensusStateCommitmentSlot(clientID string, height uint64) string {
+	key, err := chain.IBCIdentifier.ConsensusStateCommitmentSlot(chain.CallOpts(context.Background(), RelayerKeyIndex), clientID, height)
+	require.NoError(chain.t, err)
+	return "0x" + hex.EncodeToString(key[:])
+}
+
 func (chain *Chain) ConnectionStateCommitmentSlot(connectionID string) string {
 	key, err := chain.IBCIdentifier.ConnectionCommitmentSlot(chain.CallOpts(context.Background(), RelayerKeyIndex), connectionID)
 	require.NoError(chain.t, err)
@@ -859,10 +929,22 @@ func (chain *Chain) PacketAcknowledgementCommitmentSlot(portID, channelID string
 	return "0x" + hex.EncodeToString(key[:])
 }
 
+func (chain *Chain) PacketReceiptCommitmentSlot(portID, channelID string, sequence uint64) string {
+	key, err := chain.IBCIdentifier.PacketReceiptCommitmentSlot(chain.CallOpts(context.Background(), RelayerKeyIndex), portID, channelID, sequence)
+	require.NoError(chain.t, err)
+	return "0x" + hex.EncodeToString(key[:])
+}
+
+func (chain *Chain) NextSequenceRecvCommitmentSlot(portID, channelID string) string {
+	key, err := chain.IBCIdentifier.NextSequenceRecvCommitmentSlot(chain.CallOpts(context.Background(), RelayerKeyIndex), portID, channelID)
+	require.NoError(chain.t, err)
+	return "0x" + hex.EncodeToString(key[:])
+}
+
 // Querier
 
 type Proof struct {
-	Height uint64
+	Height channeltypes.Height
 	Data   []byte
 }
 
@@ -874,7 +956,14 @@ func (chain *Chain) QueryProof(counterparty *Chain, counterpartyClientID string,
 	if err != nil {
 		return nil, err
 	}
-	return &Proof{Height: s.Header().Number.Uint64(), Data: s.ETHProof().StorageProofRLP[0]}, nil
+	return &Proof{Height: chain.NewHeight(s.Header().Number.Uint64()), Data: s.ETHProof().StorageProofRLP[0]}, nil
+}
+
+// queryPacketProof queries the proof of the packet commitment or the acknowledgement commitment at the latest height, recording its span.
+func (chain *Chain) queryPacketProof(ctx context.Context, counterparty *Chain, counterpartyClientID string, storageKey string) (proof *Proof, err error) {
+	_, span := chain.startSpan(ctx, tracing.SpanQueryProof, trace.WithAttributes(tracing.AttributeClientID.String(counterpartyClientID)))
+	defer func() { tracing.End(span, err) }()
+	return chain.QueryProof(counterparty, counterpartyClientID, storageKey, nil)
 }
 
 func (counterparty *Chain) QueryClientProof(chain *Chain, counterpartyClientID string, height *big.Int) ([]byte, *Proof, error) {
@@ -899,6 +988,61 @@ func (counterparty *Chain) QueryClientProof(chain *Chain, counterpartyClientID s
 	return cs, proof, nil
 }
 
+func (counterparty *Chain) QueryConsensusStateProof(chain *Chain, counterpartyClientID string, consensusHeight uint64, height *big.Int) ([]byte, *Proof, error) {
+	cs, found, err := counterparty.IBCHost.GetConsensusState(
+		counterparty.CallOpts(context.Background(), RelayerKeyIndex),
+		counterpartyClientID,
+		consensusHeight,
+	)
+	if err != nil {
+		return nil, nil, err
+	} else if !found {
+		return nil, nil, fmt.Errorf("consensus state not found: clientID=%v height=%v", counterpartyClientID, consensusHeight)
+	}
+	proof, err := counterparty.QueryProof(chain, counterpartyClientID, chain.ConsensusStateCommitmentSlot(counterpartyClientID, consensusHeight), height)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch counterparty.ClientType() {
+	case ibcclient.MockClient:
+		h := sha256.Sum256(cs)
+		proof.Data = h[:]
+	}
+	return cs, proof, nil
+}
+
+// QueryClientLatestHeight returns the latest height of the client on this chain that tracks the counterparty chain.
+func (chain *Chain) QueryClientLatestHeight(ctx context.Context, counterparty *Chain, clientID string) (channeltypes.Height, error) {
+	cs, found, err := chain.IBCHost.GetClientState(chain.CallOpts(ctx, RelayerKeyIndex), clientID)
+	if err != nil {
+		return channeltypes.Height{}, err
+	} else if !found {
+		return channeltypes.Height{}, fmt.Errorf("client not found: %v", clientID)
+	}
+	return chain.clientLatestHeight(counterparty, cs)
+}
+
+// clientLatestHeight returns the latest height of the given client state of this chain's client type.
+// The mock client state does not contain a chain ID, so the revision of the counterparty chain is used.
+func (chain *Chain) clientLatestHeight(counterparty *Chain, clientStateBytes []byte) (channeltypes.Height, error) {
+	switch chain.ClientType() {
+	case ibcclient.MockClient:
+		var cs mockclienttypes.ClientState
+		if err := UnmarshalWithAny(clientStateBytes, &cs); err != nil {
+			return channeltypes.Height{}, err
+		}
+		return counterparty.NewHeight(cs.LatestHeight), nil
+	case ibcclient.BesuIBFT2Client:
+		var cs ibft2clienttypes.ClientState
+		if err := UnmarshalWithAny(clientStateBytes, &cs); err != nil {
+			return channeltypes.Height{}, err
+		}
+		return channeltypes.NewHeight(channeltypes.ParseChainID(cs.ChainId), cs.LatestHeight), nil
+	default:
+		return channeltypes.Height{}, fmt.Errorf("unknown client type: '%v'", chain.ClientType())
+	}
+}
+
 func (counterparty *Chain) QueryConnectionProof(chain *Chain, counterpartyClientID string, counterpartyConnectionID string, height *big.Int) (*Proof, error) {
 	proof, err := counterparty.QueryProof(chain, counterpartyClientID, chain.ConnectionStateCommitmentSlot(counterpartyConnectionID), height)
 	if err != nil {
@@ -915,7 +1059,7 @@ func (counterparty *Chain) QueryConnectionProof(chain *Chain, counterpartyClient
 		} else if !found {
 			return nil, fmt.Errorf("connection not found: %v", counterpartyConnectionID)
 		}
-		bz, err := proto.Marshal(connectionEndToPB(conn))
+		bz, err := proto.Marshal(convert.ConnectionEndToPB(conn))
 		if err != nil {
 			return nil, err
 		}
@@ -941,7 +1085,7 @@ func (counterparty *Chain) QueryChannelProof(chain *Chain, counterpartyClientID
 		} else if !found {
 			return nil, fmt.Errorf("channel not found: %v", channel)
 		}
-		bz, err := proto.Marshal(channelToPB(ch))
+		bz, err := proto.Marshal(convert.ChannelToPB(ch))
 		if err != nil {
 			return nil, err
 		}
@@ -955,15 +1099,28 @@ func (chain *Chain) LastHeader() *gethtypes.Header {
 	return chain.LastContractState.Header()
 }
 
+// LastHeight returns the height of the last header.
+func (chain *Chain) LastHeight() channeltypes.Height {
+	return chain.NewHeight(chain.LastHeader().Number.Uint64())
+}
+
 func (chain *Chain) WaitForReceiptAndGet(ctx context.Context, tx *gethtypes.Transaction) error {
-	rc, err := chain.Client().WaitForReceiptAndGet(ctx, tx)
+	_, err := chain.waitForReceipt(ctx, tx)
+	return err
+}
+
+// waitForReceipt waits for the receipt of the transaction and returns it if the transaction succeeded.
+func (chain *Chain) waitForReceipt(ctx context.Context, tx *gethtypes.Transaction) (client.Receipt, error) {
+	start := time.Now()
+	rc, err := chain.host.WaitForReceipt(ctx, tx)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	chain.tracePacketEvents(ctx, rc, start)
 	if rc.Status() == 1 {
-		return nil
+		return rc, nil
 	} else {
-		return fmt.Errorf("failed to call transaction: err='%v' rc='%v' reason='%v'", err, rc, rc.RevertReason())
+		return nil, fmt.Errorf("failed to call transaction: err='%v' rc='%v' reason='%v'", err, rc, rc.RevertReason())
 	}
 }
 
@@ -0,0 +1,69 @@
+package testing
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client/simulated"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+)
+
+func TestSetContractConfig(t *testing.T) {
+	// the contracts of each config are stubbed by the code returning the same word to any call,
+	// so that the contracts bound to the chain are told by the results of the calls
+	stub := func(word byte) []byte {
+		// PUSH1 word PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+		return []byte{0x60, word, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3}
+	}
+	configs := []DeployedContracts{
+		{
+			IBCHost: common.HexToAddress("0x11"), IBCHandler: common.HexToAddress("0x12"), IBCIdentifier: common.HexToAddress("0x13"),
+			SimpleToken: common.HexToAddress("0x14"), ICS20TransferBank: common.HexToAddress("0x15"), ICS20Bank: common.HexToAddress("0x16"),
+		},
+		{
+			IBCHost: common.HexToAddress("0x21"), IBCHandler: common.HexToAddress("0x22"), IBCIdentifier: common.HexToAddress("0x23"),
+			SimpleToken: common.HexToAddress("0x24"), ICS20TransferBank: common.HexToAddress("0x25"), ICS20Bank: common.HexToAddress("0x26"),
+		},
+	}
+	alloc, err := simulatedAlloc(testMnemonicPhrase, 1)
+	require.NoError(t, err)
+	for i, config := range configs {
+		for _, address := range []common.Address{
+			config.IBCHost, config.IBCHandler, config.IBCIdentifier, config.SimpleToken, config.ICS20TransferBank, config.ICS20Bank,
+		} {
+			alloc[address] = core.GenesisAccount{Code: stub(byte(i + 1)), Balance: new(big.Int)}
+		}
+	}
+	cl := simulated.NewClient(alloc, simulatedGasLimit, ibcclient.MockClient)
+	chain := NewChain(t, SimulatedChainID, *cl, configs[0], testMnemonicPhrase, 0)
+
+	requireBound := func(config DeployedContracts, word byte) {
+		ctx := context.Background()
+		opts := chain.CallOpts(ctx, RelayerKeyIndex)
+		require.Equal(t, config, chain.ContractConfig)
+		require.Equal(t, config.IBCHost, chain.IBCHostAddress())
+		seq, err := chain.IBCHost.GetNextSequenceSend(opts, "port", "channel-0")
+		require.NoError(t, err)
+		require.Equal(t, uint64(word), seq)
+		host, err := chain.IBCHandler.GetHostAddress(opts)
+		require.NoError(t, err)
+		require.Equal(t, common.BytesToAddress([]byte{word}), host)
+		slot, err := chain.IBCIdentifier.ClientStateCommitmentSlot(opts, "client-0")
+		require.NoError(t, err)
+		require.Equal(t, common.BytesToHash([]byte{word}), common.Hash(slot))
+		balance, err := chain.SimpleToken.BalanceOf(opts, opts.From)
+		require.NoError(t, err)
+		require.Equal(t, int64(word), balance.Int64())
+		balance, err = chain.ICS20Bank.BalanceOf(opts, opts.From, "denom")
+		require.NoError(t, err)
+		require.Equal(t, int64(word), balance.Int64())
+	}
+	requireBound(configs[0], 1)
+	require.NoError(t, chain.SetContractConfig(configs[1]))
+	requireBound(configs[1], 2)
+}
@@ -2,12 +2,15 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
 	clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/tracing"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Coordinator struct {
@@ -91,8 +94,10 @@ func (c Coordinator) UpdateClient(
 	ctx context.Context,
 	source, counterparty *Chain,
 	clientID string,
-) error {
-	var err error
+) (err error) {
+	ctx, span := source.startSpan(ctx, tracing.SpanUpdateClient, trace.WithAttributes(tracing.AttributeClientID.String(clientID)))
+	defer func() { tracing.End(span, err) }()
+
 	switch counterparty.ClientType() {
 	case clienttypes.BesuIBFT2Client:
 		err = source.UpdateIBFT2Client(ctx, counterparty, clientID)
@@ -410,6 +415,7 @@ func (c *Coordinator) SendPacket(
 	packet channeltypes.Packet,
 	counterpartyClientID string,
 ) error {
+	ctx = tracing.ContextWithPacket(ctx, PacketRoute(source, counterparty), packet)
 	if err := source.SendPacket(ctx, packet); err != nil {
 		return err
 	}
@@ -429,6 +435,7 @@ func (c *Coordinator) HandlePacketRecv(
 	sourceChannel, counterpartyChannel TestChannel,
 	packet channeltypes.Packet,
 ) error {
+	ctx = tracing.ContextWithPacket(ctx, PacketRoute(counterparty, source), packet)
 	if err := source.HandlePacketRecv(ctx, counterparty, sourceChannel, counterpartyChannel, packet); err != nil {
 		return err
 	}
@@ -449,6 +456,7 @@ func (c *Coordinator) HandlePacketAcknowledgement(
 	packet channeltypes.Packet,
 	acknowledgement []byte,
 ) error {
+	ctx = tracing.ContextWithPacket(ctx, PacketRoute(source, counterparty), packet)
 	if err := source.HandlePacketAcknowledgement(ctx, counterparty, sourceChannel, counterpartyChannel, packet, acknowledgement); err != nil {
 		return err
 	}
@@ -461,3 +469,74 @@ func (c *Coordinator) HandlePacketAcknowledgement(
 		counterpartyChannel.ClientID,
 	)
 }
+
+// ClearPackets relays all packets sent from the source channel that have not been
+// received on the counterparty chain yet except timed-out ones, and then relays all acknowledgements
+// written on the counterparty chain that have not been acknowledged on the source chain.
+func (c *Coordinator) ClearPackets(
+	ctx context.Context,
+	source, counterparty *Chain,
+	sourceChannel, counterpartyChannel TestChannel,
+) error {
+	// update both clients so that proofs for the pending commitments can be verified
+	source.UpdateHeader()
+	if err := c.UpdateClient(ctx, counterparty, source, counterpartyChannel.ClientID); err != nil {
+		return err
+	}
+	counterparty.UpdateHeader()
+	if err := c.UpdateClient(ctx, source, counterparty, sourceChannel.ClientID); err != nil {
+		return err
+	}
+
+	seqs, err := source.QueryUnrelayedSequences(ctx, counterparty, sourceChannel, counterpartyChannel)
+	if err != nil {
+		return err
+	}
+	for _, seq := range seqs.Packets {
+		packet, err := source.FindPacket(ctx, sourceChannel.PortID, sourceChannel.ID, seq)
+		if err != nil {
+			return err
+		}
+		if err := c.HandlePacketRecv(ctx, counterparty, source, counterpartyChannel, sourceChannel, *packet); errors.Is(err, ErrPacketTimedOut) {
+			// the packet can only be timed out on the source chain
+			continue
+		} else if err != nil {
+			return err
+		}
+	}
+
+	// query again to include the acknowledgements written by the above packets
+	seqs, err = source.QueryUnrelayedSequences(ctx, counterparty, sourceChannel, counterpartyChannel)
+	if err != nil {
+		return err
+	}
+	for _, seq := range seqs.Acknowledgements {
+		packet, err := source.FindPacket(ctx, sourceChannel.PortID, sourceChannel.ID, seq)
+		if err != nil {
+			return err
+		}
+		ack, err := counterparty.FindAcknowledgement(ctx, counterpartyChannel.PortID, counterpartyChannel.ID, seq)
+		if err != nil {
+			return err
+		}
+		if err := c.HandlePacketAcknowledgement(ctx, source, counterparty, sourceChannel, counterpartyChannel, *packet, ack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QueryTimedOutPackets updates the client of the counterparty chain on the source chain, and then
+// returns the packets sent from the source channel that have timed out on the counterparty chain
+// together with the proofs required to time them out.
+func (c *Coordinator) QueryTimedOutPackets(
+	ctx context.Context,
+	source, counterparty *Chain,
+	sourceChannel, counterpartyChannel TestChannel,
+) ([]TimedOutPacket, error) {
+	counterparty.UpdateHeader()
+	if err := c.UpdateClient(ctx, source, counterparty, sourceChannel.ClientID); err != nil {
+		return nil, err
+	}
+	return source.QueryTimedOutPackets(ctx, counterparty, sourceChannel, counterpartyChannel)
+}
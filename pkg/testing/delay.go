@@ -0,0 +1,46 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+)
+
+// QueryDelayPeriodEnd returns the time (in nanoseconds) and the block height of this chain from which
+// a packet proof at the given height is accepted on the channel. If the height is nil, the latest
+// height of the channel's client is used, which is the height a packet proof is queried at by default.
+func (chain *Chain) QueryDelayPeriodEnd(ctx context.Context, ch TestChannel, height *big.Int) (uint64, uint64, error) {
+	proofHeight, err := chain.delayProofHeight(ch, height)
+	if err != nil {
+		return 0, 0, err
+	}
+	return chain.host.QueryDelayPeriodEnd(ctx, ch.PortID, ch.ID, proofHeight)
+}
+
+// WaitForDelayPeriod waits until the latest block of this chain reaches both the time and the height
+// returned by QueryDelayPeriodEnd, so that a packet proof at the given height is accepted by
+// any subsequent block.
+func (chain *Chain) WaitForDelayPeriod(ctx context.Context, ch TestChannel, height *big.Int) error {
+	proofHeight, err := chain.delayProofHeight(ch, height)
+	if err != nil {
+		return err
+	}
+	return chain.host.WaitForDelayPeriod(ctx, ch.PortID, ch.ID, proofHeight)
+}
+
+// delayProofHeight returns the height if it is not nil, or the latest height of the client of the channel.
+func (chain *Chain) delayProofHeight(ch TestChannel, height *big.Int) (uint64, error) {
+	if height != nil {
+		return height.Uint64(), nil
+	}
+	switch chain.ClientType() {
+	case ibcclient.MockClient:
+		return chain.GetMockClientState(ch.ClientID).LatestHeight, nil
+	case ibcclient.BesuIBFT2Client:
+		return chain.GetIBFT2ClientState(ch.ClientID).LatestHeight, nil
+	default:
+		return 0, fmt.Errorf("unknown client type: '%v'", chain.ClientType())
+	}
+}
@@ -0,0 +1,98 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+)
+
+// ErrPacketTimedOut is returned when a packet can no longer be received on its destination chain.
+var ErrPacketTimedOut = host.ErrPacketTimedOut
+
+// UnrelayedSequences keeps track of the packet sequences on a channel that
+// still need to be relayed to the counterparty chain or back from it.
+type UnrelayedSequences = host.UnrelayedSequences
+
+// QueryUnrelayedSequences returns the packets sent from the channel on this chain
+// and the acknowledgements written on the counterparty channel that are pending.
+func (chain *Chain) QueryUnrelayedSequences(
+	ctx context.Context,
+	counterparty *Chain,
+	ch, counterpartyCh TestChannel,
+) (*UnrelayedSequences, error) {
+	return chain.host.QueryUnrelayedSequences(ctx, counterparty.host, ch.PortID, ch.ID)
+}
+
+// TimedOutPacket is a packet that can no longer be received on the destination chain.
+type TimedOutPacket struct {
+	Packet channeltypes.Packet
+	// Proof proves the absence of the packet receipt on the destination chain for an
+	// UNORDERED channel, or the next receive sequence for an ORDERED channel.
+	Proof *Proof
+}
+
+// QueryTimedOutPackets returns the packets sent from the channel on this chain that have not been
+// received on the counterparty chain and have timed out at the counterparty's last header.
+// The proofs of the returned packets are queried at the height of that header.
+func (chain *Chain) QueryTimedOutPackets(
+	ctx context.Context,
+	counterparty *Chain,
+	ch, counterpartyCh TestChannel,
+) ([]TimedOutPacket, error) {
+	channel, found, err := counterparty.IBCHost.GetChannel(counterparty.CallOpts(ctx, RelayerKeyIndex), counterpartyCh.PortID, counterpartyCh.ID)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, fmt.Errorf("channel not found: %v", counterpartyCh)
+	}
+	seqs, err := chain.QueryUnrelayedSequences(ctx, counterparty, ch, counterpartyCh)
+	if err != nil {
+		return nil, err
+	}
+
+	header := counterparty.LastHeader()
+	height := counterparty.LastHeight()
+	var packets []TimedOutPacket
+	for _, seq := range seqs.Packets {
+		packet, err := chain.FindPacket(ctx, ch.PortID, ch.ID, seq)
+		if err != nil {
+			return nil, err
+		}
+		if !packet.IsTimedOut(height, header.Time) {
+			continue
+		}
+		var slot string
+		switch channeltypes.Channel_Order(channel.Ordering) {
+		case channeltypes.ORDERED:
+			slot = counterparty.NextSequenceRecvCommitmentSlot(packet.DestinationPort, packet.DestinationChannel)
+		default:
+			slot = counterparty.PacketReceiptCommitmentSlot(packet.DestinationPort, packet.DestinationChannel, packet.Sequence)
+		}
+		proof, err := counterparty.QueryProof(chain, ch.ClientID, slot, header.Number)
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, TimedOutPacket{Packet: *packet, Proof: proof})
+	}
+	return packets, nil
+}
+
+// checkPacketTimeout returns ErrPacketTimedOut if the packet can no longer be received on this chain.
+func (chain *Chain) checkPacketTimeout(ctx context.Context, packet channeltypes.Packet) error {
+	return chain.host.CheckPacketTimeout(ctx, packet)
+}
+
+// FormatPacket returns the human-readable form of the packet. The packet data is decoded
+// with the decoder registered for the source port in the app package.
+func FormatPacket(packet channeltypes.Packet) string {
+	return fmt.Sprintf("sequence=%v source=%v/%v destination=%v/%v timeoutHeight=%v timeoutTimestamp=%v data={%v}",
+		packet.Sequence,
+		packet.SourcePort, packet.SourceChannel,
+		packet.DestinationPort, packet.DestinationChannel,
+		packet.TimeoutHeight.Format(), packet.TimeoutTimestamp,
+		app.FormatPacketData(packet.SourcePort, packet.Data),
+	)
+}
@@ -0,0 +1,279 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	connectiontypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/connection"
+)
+
+// ResumeConnection completes the handshake of the connection on chainA which may have been
+// interrupted at any step. It reads the connection states on both chains and submits only the
+// remaining handshake messages. The connection information for chainA and chainB are returned.
+func (c *Coordinator) ResumeConnection(
+	ctx context.Context,
+	chainA, chainB *Chain,
+	connectionID string,
+) (*TestConnection, *TestConnection, error) {
+	connEndA, found, err := chainA.IBCHost.GetConnection(chainA.CallOpts(ctx, RelayerKeyIndex), connectionID)
+	if err != nil {
+		return nil, nil, err
+	} else if !found {
+		return nil, nil, fmt.Errorf("connection not found: %v", connectionID)
+	}
+	connA := chainA.getOrAddTestConnection(connectionID, connEndA.ClientId, connEndA.Counterparty.ClientId)
+	connIDB := connEndA.Counterparty.ConnectionId
+	if connIDB == "" {
+		// the counterparty connection ID is not recorded on chainA until ConnOpenAck
+		if connIDB, err = chainB.findCounterpartyConnectionID(ctx, connA); err != nil {
+			return nil, nil, err
+		}
+	}
+	connB := chainB.getOrAddTestConnection(connIDB, connEndA.Counterparty.ClientId, connEndA.ClientId)
+
+	stateA := connectiontypes.ConnectionEnd_State(connEndA.State)
+	stateB := connectiontypes.ConnectionEnd_STATE_UNINITIALIZED_UNSPECIFIED
+	if connB.ID != "" {
+		connEndB, found, err := chainB.IBCHost.GetConnection(chainB.CallOpts(ctx, RelayerKeyIndex), connB.ID)
+		if err != nil {
+			return nil, nil, err
+		} else if !found {
+			return nil, nil, fmt.Errorf("connection not found: %v", connB.ID)
+		}
+		stateB = connectiontypes.ConnectionEnd_State(connEndB.State)
+	}
+
+	if stateA == connectiontypes.ConnectionEnd_STATE_OPEN && stateB == connectiontypes.ConnectionEnd_STATE_OPEN {
+		return connA, connB, nil
+	}
+	// the process may have stopped before updating the clients
+	if err := c.updateClients(ctx, chainA, chainB, connA.ClientID, connB.ClientID); err != nil {
+		return nil, nil, err
+	}
+
+	if stateA == connectiontypes.ConnectionEnd_STATE_TRYOPEN {
+		// the handshake was initiated on chainB
+		err = c.resumeConnection(ctx, chainB, chainA, connB, connA, stateB, stateA)
+	} else {
+		err = c.resumeConnection(ctx, chainA, chainB, connA, connB, stateA, stateB)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return connA, connB, nil
+}
+
+// resumeConnection submits the remaining handshake messages for the connection initiated on the source chain.
+func (c *Coordinator) resumeConnection(
+	ctx context.Context,
+	source, counterparty *Chain,
+	sourceConnection, counterpartyConnection *TestConnection,
+	sourceState, counterpartyState connectiontypes.ConnectionEnd_State,
+) error {
+	for {
+		switch {
+		case sourceState == connectiontypes.ConnectionEnd_STATE_OPEN && counterpartyState == connectiontypes.ConnectionEnd_STATE_OPEN:
+			return nil
+		case sourceState == connectiontypes.ConnectionEnd_STATE_INIT && counterpartyState == connectiontypes.ConnectionEnd_STATE_UNINITIALIZED_UNSPECIFIED:
+			if err := c.ConnOpenTry(ctx, counterparty, source, counterpartyConnection, sourceConnection); err != nil {
+				return err
+			}
+			counterpartyState = connectiontypes.ConnectionEnd_STATE_TRYOPEN
+		case sourceState == connectiontypes.ConnectionEnd_STATE_INIT && counterpartyState == connectiontypes.ConnectionEnd_STATE_TRYOPEN:
+			if err := c.ConnOpenAck(ctx, source, counterparty, sourceConnection, counterpartyConnection); err != nil {
+				return err
+			}
+			sourceState = connectiontypes.ConnectionEnd_STATE_OPEN
+		case sourceState == connectiontypes.ConnectionEnd_STATE_OPEN && counterpartyState == connectiontypes.ConnectionEnd_STATE_TRYOPEN:
+			if err := c.ConnOpenConfirm(ctx, counterparty, source, counterpartyConnection, sourceConnection); err != nil {
+				return err
+			}
+			counterpartyState = connectiontypes.ConnectionEnd_STATE_OPEN
+		default:
+			return fmt.Errorf("unexpected connection states: %v=%v %v=%v", sourceConnection.ID, sourceState, counterpartyConnection.ID, counterpartyState)
+		}
+	}
+}
+
+// ResumeChannel completes the opening or closing handshake of the channel on chainA which may have been
+// interrupted at any step. The connection of the channel is resumed first if it is not OPEN on both chains.
+// It reads the channel states on both chains and submits only the remaining handshake messages.
+// The channel information for chainA and chainB are returned.
+func (c *Coordinator) ResumeChannel(
+	ctx context.Context,
+	chainA, chainB *Chain,
+	portID, channelID string,
+) (TestChannel, TestChannel, error) {
+	channelA, found, err := chainA.IBCHost.GetChannel(chainA.CallOpts(ctx, RelayerKeyIndex), portID, channelID)
+	if err != nil {
+		return TestChannel{}, TestChannel{}, err
+	} else if !found {
+		return TestChannel{}, TestChannel{}, fmt.Errorf("channel not found: portID=%v channelID=%v", portID, channelID)
+	}
+	connA, connB, err := c.ResumeConnection(ctx, chainA, chainB, channelA.ConnectionHops[0])
+	if err != nil {
+		return TestChannel{}, TestChannel{}, err
+	}
+
+	chanA := chainA.NextTestChannel(connA, portID)
+	chanA.ID = channelID
+	chanA.Version = channelA.Version
+	chanB := chainB.NextTestChannel(connB, channelA.Counterparty.PortId)
+	chanB.ID = channelA.Counterparty.ChannelId
+	chanB.Version = channelA.Version
+	if chanB.ID == "" {
+		// the counterparty channel ID is not recorded on chainA until ChanOpenAck
+		if chanB.ID, err = chainB.findCounterpartyChannelID(ctx, chanA, chanB.PortID); err != nil {
+			return TestChannel{}, TestChannel{}, err
+		}
+	}
+
+	stateA := channeltypes.Channel_State(channelA.State)
+	stateB := channeltypes.UNINITIALIZED
+	if chanB.ID != "" {
+		channelB, found, err := chainB.IBCHost.GetChannel(chainB.CallOpts(ctx, RelayerKeyIndex), chanB.PortID, chanB.ID)
+		if err != nil {
+			return TestChannel{}, TestChannel{}, err
+		} else if !found {
+			return TestChannel{}, TestChannel{}, fmt.Errorf("channel not found: portID=%v channelID=%v", chanB.PortID, chanB.ID)
+		}
+		stateB = channeltypes.Channel_State(channelB.State)
+		chanB.Version = channelB.Version
+	}
+
+	order := channeltypes.Channel_Order(channelA.Ordering)
+	if stateA == channeltypes.TRYOPEN || (stateA == channeltypes.OPEN && stateB == channeltypes.CLOSED) {
+		// the handshake was initiated on chainB
+		err = c.resumeChannel(ctx, chainB, chainA, &chanB, &chanA, connB, connA, stateB, stateA, order)
+	} else {
+		err = c.resumeChannel(ctx, chainA, chainB, &chanA, &chanB, connA, connB, stateA, stateB, order)
+	}
+	if err != nil {
+		return TestChannel{}, TestChannel{}, err
+	}
+	// the channel on chainB gets its ID from ChanOpenTry if it has not been created yet
+	connA.putChannel(chanA)
+	connB.putChannel(chanB)
+	return chanA, chanB, nil
+}
+
+// resumeChannel submits the remaining handshake messages for the channel handshake initiated on the source chain.
+func (c *Coordinator) resumeChannel(
+	ctx context.Context,
+	source, counterparty *Chain,
+	sourceChannel, counterpartyChannel *TestChannel,
+	sourceConnection, counterpartyConnection *TestConnection,
+	sourceState, counterpartyState channeltypes.Channel_State,
+	order channeltypes.Channel_Order,
+) error {
+	for {
+		switch {
+		case sourceState == channeltypes.OPEN && counterpartyState == channeltypes.OPEN,
+			sourceState == channeltypes.CLOSED && counterpartyState == channeltypes.CLOSED:
+			return nil
+		case sourceState == channeltypes.INIT && counterpartyState == channeltypes.UNINITIALIZED:
+			if err := c.ChanOpenTry(ctx, counterparty, source, counterpartyChannel, sourceChannel, counterpartyConnection, order); err != nil {
+				return err
+			}
+			counterpartyState = channeltypes.TRYOPEN
+		case sourceState == channeltypes.INIT && counterpartyState == channeltypes.TRYOPEN:
+			if err := c.ChanOpenAck(ctx, source, counterparty, *sourceChannel, *counterpartyChannel); err != nil {
+				return err
+			}
+			sourceState = channeltypes.OPEN
+		case sourceState == channeltypes.OPEN && counterpartyState == channeltypes.TRYOPEN:
+			if err := c.ChanOpenConfirm(ctx, counterparty, source, *counterpartyChannel, *sourceChannel); err != nil {
+				return err
+			}
+			counterpartyState = channeltypes.OPEN
+		case sourceState == channeltypes.CLOSED && counterpartyState == channeltypes.OPEN:
+			if err := c.ChanCloseConfirm(ctx, counterparty, source, *counterpartyChannel, *sourceChannel); err != nil {
+				return err
+			}
+			counterpartyState = channeltypes.CLOSED
+		default:
+			return fmt.Errorf("unexpected channel states: %v/%v=%v %v/%v=%v",
+				sourceChannel.PortID, sourceChannel.ID, sourceState, counterpartyChannel.PortID, counterpartyChannel.ID, counterpartyState)
+		}
+	}
+}
+
+// updateClients updates the clients on both chains with the latest headers of their counterparty chains.
+func (c *Coordinator) updateClients(ctx context.Context, chainA, chainB *Chain, clientA, clientB string) error {
+	chainA.UpdateHeader()
+	if err := c.UpdateClient(ctx, chainB, chainA, clientB); err != nil {
+		return err
+	}
+	chainB.UpdateHeader()
+	return c.UpdateClient(ctx, chainA, chainB, clientA)
+}
+
+// getOrAddTestConnection returns the test connection of this chain with the ID and the clients.
+// A new one is added if no such connection is tracked yet.
+func (chain *Chain) getOrAddTestConnection(connectionID, clientID, counterpartyClientID string) *TestConnection {
+	for _, conn := range chain.Connections {
+		if conn.ID == connectionID && conn.ClientID == clientID && conn.CounterpartyClientID == counterpartyClientID {
+			return conn
+		}
+	}
+	conn := chain.AddTestConnection(clientID, counterpartyClientID)
+	conn.ID = connectionID
+	return conn
+}
+
+// putChannel replaces the test channel of the connection with the same port and ID, or adds it
+// if no such channel is tracked yet.
+func (conn *TestConnection) putChannel(channel TestChannel) {
+	for i, ch := range conn.Channels {
+		if ch.PortID == channel.PortID && ch.ID == channel.ID {
+			conn.Channels[i] = channel
+			return
+		}
+	}
+	conn.Channels = append(conn.Channels, channel)
+}
+
+// findCounterpartyConnectionID returns the ID of the connection on this chain whose counterparty is
+// the given connection. An empty string is returned if no such connection exists.
+func (chain *Chain) findCounterpartyConnectionID(ctx context.Context, counterpartyConnection *TestConnection) (string, error) {
+	ids, err := chain.host.GetConnectionIDs(ctx)
+	if err != nil {
+		return "", err
+	}
+	for i := len(ids) - 1; i >= 0; i-- {
+		conn, found, err := chain.IBCHost.GetConnection(chain.CallOpts(ctx, RelayerKeyIndex), ids[i])
+		if err != nil {
+			return "", err
+		} else if !found {
+			continue
+		}
+		if conn.ClientId == counterpartyConnection.CounterpartyClientID &&
+			conn.Counterparty.ClientId == counterpartyConnection.ClientID &&
+			conn.Counterparty.ConnectionId == counterpartyConnection.ID {
+			return ids[i], nil
+		}
+	}
+	return "", nil
+}
+
+// findCounterpartyChannelID returns the ID of the channel bound to the port on this chain whose counterparty
+// is the given channel. An empty string is returned if no such channel exists.
+func (chain *Chain) findCounterpartyChannelID(ctx context.Context, counterpartyChannel TestChannel, portID string) (string, error) {
+	ids, err := chain.host.GetChannelIDs(ctx)
+	if err != nil {
+		return "", err
+	}
+	for i := len(ids) - 1; i >= 0; i-- {
+		ch, found, err := chain.IBCHost.GetChannel(chain.CallOpts(ctx, RelayerKeyIndex), portID, ids[i])
+		if err != nil {
+			return "", err
+		} else if !found {
+			continue
+		}
+		if ch.Counterparty.PortId == counterpartyChannel.PortID && ch.Counterparty.ChannelId == counterpartyChannel.ID {
+			return ids[i], nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,307 @@
+package testing
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client/simulated"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchost"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ics20bank"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/wallet"
+)
+
+const (
+	// SimulatedChainID is the chain ID of the chains simulated by the simulated backend of go-ethereum,
+	// which is always 1337.
+	SimulatedChainID int64 = 1337
+	// DefaultArtifactsDir is the directory of the artifacts of the contracts built by `truffle compile`,
+	// relative to the root of the repository.
+	DefaultArtifactsDir = "build/contracts"
+
+	// simulatedAccounts is the number of the accounts funded on a simulated chain.
+	simulatedAccounts uint32 = 10
+	// simulatedGasLimit is the gas limit of the blocks of a simulated chain, which is the one of the ganache chain.
+	simulatedGasLimit uint64 = 1000000000
+)
+
+// simulatedLibraries are the libraries linked to the contracts, in the order of their deployment.
+var simulatedLibraries = []string{"IBCIdentifier", "IBCMsgs", "IBCClient", "IBCConnection", "IBCChannel"}
+
+// NewSimulatedChains creates the chains on a chain simulated in process by the simulated backend of go-ethereum,
+// one for each of the IBC IDs. They share the contracts deployed from the artifacts in artifactsDir by the account
+// of RelayerKeyIndex, as the chains of the integration tests share a ganache node.
+// See simulated.NewClient for the production of the blocks.
+func NewSimulatedChains(t *testing.T, clientType string, artifactsDir string, mnemonicPhrase string, ibcIDs ...uint64) []*Chain {
+	alloc, err := simulatedAlloc(mnemonicPhrase, simulatedAccounts)
+	require.NoError(t, err)
+	return newSimulatedChains(t, simulated.NewClient(alloc, simulatedGasLimit, clientType), artifactsDir, mnemonicPhrase, ibcIDs...)
+}
+
+// NewSimulatedIBFT2Chains creates the chains as NewSimulatedChains, whose headers are sealed by the sealer
+// as the headers of an IBFT2 chain, so that the chains are verified by the IBFT2 client.
+func NewSimulatedIBFT2Chains(t *testing.T, sealer simulated.HeaderSealer, artifactsDir string, mnemonicPhrase string, ibcIDs ...uint64) []*Chain {
+	alloc, err := simulatedAlloc(mnemonicPhrase, simulatedAccounts)
+	require.NoError(t, err)
+	return newSimulatedChains(t, simulated.NewIBFT2Client(alloc, simulatedGasLimit, sealer), artifactsDir, mnemonicPhrase, ibcIDs...)
+}
+
+func newSimulatedChains(t *testing.T, cl *client.Client, artifactsDir string, mnemonicPhrase string, ibcIDs ...uint64) []*Chain {
+	ctx := context.Background()
+	artifacts, err := LoadArtifacts(artifactsDir)
+	require.NoError(t, err)
+	key, err := wallet.GetPrvKeyFromMnemonicAndHDWPath(mnemonicPhrase, fmt.Sprintf("m/44'/60'/0'/0/%v", RelayerKeyIndex))
+	require.NoError(t, err)
+	contracts, err := DeployContracts(ctx, *cl, client.MakeGenTxOpts(big.NewInt(SimulatedChainID), key)(ctx), artifacts)
+	require.NoError(t, err)
+
+	var chains []*Chain
+	for _, ibcID := range ibcIDs {
+		chains = append(chains, NewChain(t, SimulatedChainID, *cl, contracts, mnemonicPhrase, ibcID))
+	}
+	return chains
+}
+
+// simulatedAlloc returns the genesis accounts funding the first `accounts` accounts derived from the mnemonic.
+func simulatedAlloc(mnemonicPhrase string, accounts uint32) (core.GenesisAlloc, error) {
+	alloc := make(core.GenesisAlloc)
+	balance := new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil) // 100 ether
+	for i := uint32(0); i < accounts; i++ {
+		key, err := wallet.GetPrvKeyFromMnemonicAndHDWPath(mnemonicPhrase, fmt.Sprintf("m/44'/60'/0'/0/%v", i))
+		if err != nil {
+			return nil, err
+		}
+		alloc[crypto.PubkeyToAddress(key.PublicKey)] = core.GenesisAccount{Balance: balance}
+	}
+	return alloc, nil
+}
+
+// Artifact is the artifact of a contract built by truffle.
+type Artifact struct {
+	ContractName string          `json:"contractName"`
+	ABI          json.RawMessage `json:"abi"`
+	Bytecode     string          `json:"bytecode"`
+}
+
+// LoadArtifacts loads the artifacts of the contracts in the directory, keyed by the contract names.
+func LoadArtifacts(dir string) (map[string]Artifact, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	} else if len(paths) == 0 {
+		return nil, fmt.Errorf("no artifact found in %v: the contracts must be compiled by `truffle compile`", dir)
+	}
+	artifacts := make(map[string]Artifact)
+	for _, path := range paths {
+		bz, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var artifact Artifact
+		if err := json.Unmarshal(bz, &artifact); err != nil {
+			return nil, fmt.Errorf("failed to decode the artifact %v: %v", path, err)
+		}
+		artifacts[artifact.ContractName] = artifact
+	}
+	return artifacts, nil
+}
+
+// linkBytecode replaces the placeholders of the libraries in the bytecode with their addresses. Truffle formats
+// the placeholder of a library as its name prefixed with "__" and padded with "_" to 40 characters.
+func linkBytecode(bytecode string, libraries map[string]common.Address) ([]byte, error) {
+	for name, address := range libraries {
+		placeholder := "__" + name
+		if len(placeholder) > 40 {
+			placeholder = placeholder[:40]
+		}
+		placeholder += strings.Repeat("_", 40-len(placeholder))
+		bytecode = strings.ReplaceAll(bytecode, placeholder, hex.EncodeToString(address.Bytes()))
+	}
+	if i := strings.Index(bytecode, "__"); i >= 0 {
+		end := i + 40
+		if end > len(bytecode) {
+			end = len(bytecode)
+		}
+		return nil, fmt.Errorf("unlinked library: %v", bytecode[i:end])
+	}
+	return hex.DecodeString(strings.TrimPrefix(bytecode, "0x"))
+}
+
+// DeployedContracts is the addresses of the contracts deployed by DeployContracts. It implements ContractConfig.
+type DeployedContracts struct {
+	IBCHost           common.Address
+	IBCHandler        common.Address
+	IBCIdentifier     common.Address
+	IBFT2Client       common.Address
+	MockClient        common.Address
+	SimpleToken       common.Address
+	ICS20TransferBank common.Address
+	ICS20Bank         common.Address
+}
+
+var _ ContractConfig = (*DeployedContracts)(nil)
+
+// DeployContracts deploys the contracts from their artifacts and initializes them as the truffle migrations do,
+// sending the transactions with opts.
+func DeployContracts(ctx context.Context, cl client.Client, opts *bind.TransactOpts, artifacts map[string]Artifact) (*DeployedContracts, error) {
+	d := deployer{ctx: ctx, client: cl, opts: *opts, artifacts: artifacts, libraries: make(map[string]common.Address)}
+	// estimate the gas of each deployment since the contracts are large
+	d.opts.GasLimit = 0
+	for _, name := range simulatedLibraries {
+		address, err := d.deploy(name)
+		if err != nil {
+			return nil, err
+		}
+		d.libraries[name] = address
+	}
+
+	var (
+		contracts = DeployedContracts{IBCIdentifier: d.libraries["IBCIdentifier"]}
+		err       error
+	)
+	if contracts.IBFT2Client, err = d.deploy("IBFT2Client"); err != nil {
+		return nil, err
+	}
+	if contracts.MockClient, err = d.deploy("MockClient"); err != nil {
+		return nil, err
+	}
+	if contracts.IBCHost, err = d.deploy("IBCHost"); err != nil {
+		return nil, err
+	}
+	if contracts.IBCHandler, err = d.deploy("IBCHandler", contracts.IBCHost); err != nil {
+		return nil, err
+	}
+	if contracts.SimpleToken, err = d.deploy("SimpleToken", "simple", "simple", big.NewInt(1000000)); err != nil {
+		return nil, err
+	}
+	if contracts.ICS20Bank, err = d.deploy("ICS20Bank"); err != nil {
+		return nil, err
+	}
+	if contracts.ICS20TransferBank, err = d.deploy("ICS20TransferBank", contracts.IBCHost, contracts.IBCHandler, contracts.ICS20Bank); err != nil {
+		return nil, err
+	}
+
+	host, err := ibchost.NewIbchost(contracts.IBCHost, cl)
+	if err != nil {
+		return nil, err
+	}
+	handler, err := ibchandler.NewIbchandler(contracts.IBCHandler, cl)
+	if err != nil {
+		return nil, err
+	}
+	bank, err := ics20bank.NewIcs20bank(contracts.ICS20Bank, cl)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range []func() (*gethtypes.Transaction, error){
+		func() (*gethtypes.Transaction, error) { return host.SetIBCModule(opts, contracts.IBCHandler) },
+		func() (*gethtypes.Transaction, error) {
+			return handler.BindPort(opts, TransferPort, contracts.ICS20TransferBank)
+		},
+		func() (*gethtypes.Transaction, error) {
+			return handler.RegisterClient(opts, ibcclient.BesuIBFT2Client, contracts.IBFT2Client)
+		},
+		func() (*gethtypes.Transaction, error) {
+			return handler.RegisterClient(opts, ibcclient.MockClient, contracts.MockClient)
+		},
+		func() (*gethtypes.Transaction, error) { return bank.SetOperator(opts, contracts.ICS20TransferBank) },
+	} {
+		if err := d.wait(f()); err != nil {
+			return nil, err
+		}
+	}
+	return &contracts, nil
+}
+
+type deployer struct {
+	ctx       context.Context
+	client    client.Client
+	opts      bind.TransactOpts
+	artifacts map[string]Artifact
+	libraries map[string]common.Address
+}
+
+// deploy deploys the contract linked with the libraries deployed so far.
+func (d deployer) deploy(name string, params ...interface{}) (common.Address, error) {
+	artifact, ok := d.artifacts[name]
+	if !ok {
+		return common.Address{}, fmt.Errorf("artifact not found: %v", name)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
+	if err != nil {
+		return common.Address{}, err
+	}
+	bytecode, err := linkBytecode(artifact.Bytecode, d.libraries)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to link %v: %v", name, err)
+	}
+	opts := d.opts
+	opts.Context = d.ctx
+	address, tx, _, err := bind.DeployContract(&opts, parsed, bytecode, d.client, params...)
+	if err := d.wait(tx, err); err != nil {
+		return common.Address{}, fmt.Errorf("failed to deploy %v: %v", name, err)
+	}
+	return address, nil
+}
+
+func (d deployer) wait(tx *gethtypes.Transaction, err error) error {
+	if err != nil {
+		return err
+	}
+	rc, err := d.client.WaitForReceiptAndGet(d.ctx, tx)
+	if err != nil {
+		return err
+	} else if rc.Status() != gethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("failed to execute the transaction: tx=%v", tx.Hash().Hex())
+	}
+	return nil
+}
+
+func (c DeployedContracts) GetIBCHostAddress() common.Address {
+	return c.IBCHost
+}
+
+func (c DeployedContracts) GetIBCHandlerAddress() common.Address {
+	return c.IBCHandler
+}
+
+func (c DeployedContracts) GetIBCIdentifierAddress() common.Address {
+	return c.IBCIdentifier
+}
+
+func (c DeployedContracts) GetIBFT2ClientAddress() common.Address {
+	return c.IBFT2Client
+}
+
+func (c DeployedContracts) GetMockClientAddress() common.Address {
+	return c.MockClient
+}
+
+func (c DeployedContracts) GetSimpleTokenAddress() common.Address {
+	return c.SimpleToken
+}
+
+func (c DeployedContracts) GetICS20TransferBankAddress() common.Address {
+	return c.ICS20TransferBank
+}
+
+func (c DeployedContracts) GetICS20BankAddress() common.Address {
+	return c.ICS20Bank
+}
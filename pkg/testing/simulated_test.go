@@ -0,0 +1,78 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client/simulated"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ics20transferbank"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/simpletoken"
+	ibcclient "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/wallet"
+)
+
+const testMnemonicPhrase = "math razor capable expose worth grape metal sunset metal sudden usage scheme"
+
+func TestLinkBytecode(t *testing.T) {
+	identifier := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	msgs := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	bytecode := "0x6080__IBCIdentifier_________________________73__IBCMsgs_______________________________00"
+
+	linked, err := linkBytecode(bytecode, map[string]common.Address{"IBCIdentifier": identifier, "IBCMsgs": msgs})
+	require.NoError(t, err)
+	require.Equal(t, common.FromHex("0x6080"+identifier.Hex()[2:]+"73"+msgs.Hex()[2:]+"00"), linked)
+
+	// the bytecode linked partially is rejected
+	_, err = linkBytecode(bytecode, map[string]common.Address{"IBCIdentifier": identifier})
+	require.Error(t, err)
+}
+
+func TestDeployContracts(t *testing.T) {
+	// the contracts are stubbed by the code returning a STOP, so that the deployment is tested without the
+	// artifacts built by truffle; the contracts built by truffle are tested by TestSimulatedDeployContracts
+	// in tests/integration
+	const stub = "0x6001600c60003960016000f300"
+	dir := t.TempDir()
+	for _, name := range append(simulatedLibraries, "IBFT2Client", "MockClient", "IBCHost", "IBCHandler", "SimpleToken", "ICS20Bank", "ICS20TransferBank") {
+		abi, bytecode := "[]", stub
+		switch name {
+		case "IBCHandler":
+			abi, bytecode = ibchandler.IbchandlerABI, stub+"__IBCChannel____________________________"
+		case "SimpleToken":
+			abi = simpletoken.SimpletokenABI
+		case "ICS20TransferBank":
+			abi = ics20transferbank.Ics20transferbankABI
+		}
+		bz, err := json.Marshal(Artifact{ContractName: name, ABI: json.RawMessage(abi), Bytecode: bytecode})
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".json"), bz, 0644))
+	}
+	artifacts, err := LoadArtifacts(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	alloc, err := simulatedAlloc(testMnemonicPhrase, 1)
+	require.NoError(t, err)
+	cl := simulated.NewClient(alloc, simulatedGasLimit, ibcclient.MockClient)
+	key, err := wallet.GetPrvKeyFromMnemonicAndHDWPath(testMnemonicPhrase, "m/44'/60'/0'/0/0")
+	require.NoError(t, err)
+	contracts, err := DeployContracts(ctx, *cl, client.MakeGenTxOpts(big.NewInt(SimulatedChainID), key)(ctx), artifacts)
+	require.NoError(t, err)
+	for _, address := range []common.Address{
+		contracts.IBCHost, contracts.IBCHandler, contracts.IBCIdentifier, contracts.IBFT2Client,
+		contracts.MockClient, contracts.SimpleToken, contracts.ICS20TransferBank, contracts.ICS20Bank,
+	} {
+		code, err := cl.CodeAt(ctx, address, nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x00}, code)
+	}
+}
@@ -0,0 +1,29 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/audit"
+)
+
+// RequireSupplyInvariant requires that the tokens of the denomination escrowed on the chain for the channel
+// back the vouchers outstanding on the counterparty chain, as audit.CheckSupply checks, and returns the report
+// so that the amounts can be compared across the transfers.
+func RequireSupplyInvariant(
+	t *testing.T,
+	ctx context.Context,
+	chain, counterparty *Chain,
+	channel TestChannel,
+	baseDenom string,
+	extraHolders ...common.Address,
+) *audit.SupplyReport {
+	t.Helper()
+	report, err := audit.CheckSupply(ctx, chain.host, counterparty.host, channel.PortID, channel.ID, baseDenom, extraHolders...)
+	require.NoError(t, err)
+	require.True(t, report.OK(), report.String())
+	return report
+}
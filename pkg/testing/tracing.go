@@ -0,0 +1,93 @@
+package testing
+
+import (
+	"context"
+	"time"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/metrics"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/tracing"
+)
+
+var packetEventSpans = map[metrics.PacketEvent]string{
+	metrics.PacketSent:         tracing.SpanSendPacket,
+	metrics.PacketReceived:     tracing.SpanRecvPacket,
+	metrics.PacketAcknowledged: tracing.SpanAcknowledgePacket,
+}
+
+// tracePacketEvents records the span of the succeeded transaction in the trace of each packet of the packet
+// events in its logs. The spans start when waiting for the receipt starts.
+func (chain *Chain) tracePacketEvents(ctx context.Context, rc client.Receipt, start time.Time) {
+	if rc.Status() != gethtypes.ReceiptStatusSuccessful {
+		return
+	}
+	for _, ev := range chain.host.PacketEvents(rc.Logs()) {
+		chain.tracePacketEvent(ctx, ev, rc, start)
+	}
+}
+
+// PacketRoute returns the route of the packets relayed from the source chain to the destination chain.
+func PacketRoute(source, destination *Chain) tracing.PacketRoute {
+	return tracing.PacketRoute{
+		SourceChainID:      source.ChainIDString(),
+		DestinationChainID: destination.ChainIDString(),
+		SourceIBCHost:      source.IBCHostAddress(),
+	}
+}
+
+// packetRoute returns the route of the packet of the packet event emitted on this chain. The counterparty
+// chain is found by the client of the channel of the packet on this chain, and false is returned if
+// the client has not been created by this chain.
+func (chain *Chain) packetRoute(ev host.PacketEvent) (tracing.PacketRoute, bool) {
+	portID, channelID := ev.Packet.SourcePort, ev.Packet.SourceChannel
+	if ev.Event == metrics.PacketReceived {
+		portID, channelID = ev.Packet.DestinationPort, ev.Packet.DestinationChannel
+	}
+	for _, conn := range chain.Connections {
+		for _, ch := range conn.Channels {
+			if ch.PortID != portID || ch.ID != channelID {
+				continue
+			}
+			counterparty, ok := chain.counterparties[ch.ClientID]
+			if !ok {
+				return tracing.PacketRoute{}, false
+			} else if ev.Event == metrics.PacketReceived {
+				return PacketRoute(counterparty, chain), true
+			}
+			return PacketRoute(chain, counterparty), true
+		}
+	}
+	return tracing.PacketRoute{}, false
+}
+
+// tracePacketEvent records the span of the transaction emitting the packet event in the trace of the packet.
+// The span is not recorded if the route of the packet is unknown.
+func (chain *Chain) tracePacketEvent(ctx context.Context, ev host.PacketEvent, rc client.Receipt, start time.Time) {
+	route, ok := chain.packetRoute(ev)
+	if !ok {
+		return
+	}
+	attrs := append(tracing.PacketAttributes(ev.Packet),
+		tracing.AttributeChainID.String(chain.ChainIDString()),
+		tracing.AttributeTxHash.String(rc.TxHash().Hex()),
+		tracing.AttributeGasUsed.Int64(int64(rc.GasUsed())),
+	)
+	_, span := tracing.Tracer().Start(
+		tracing.ContextWithPacket(ctx, route, ev.Packet),
+		packetEventSpans[ev.Event],
+		trace.WithTimestamp(start),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	span.End()
+}
+
+// startSpan starts a span of a step on this chain in the trace of the context.
+func (chain *Chain) startSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append(opts, trace.WithAttributes(tracing.AttributeChainID.String(chain.ChainIDString())))
+	return tracing.Tracer().Start(ctx, name, opts...)
+}
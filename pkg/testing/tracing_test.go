@@ -0,0 +1,51 @@
+package testing
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/metrics"
+)
+
+func TestPacketRoute(t *testing.T) {
+	chainA := NewChain(t, 1, client.Client{}, DeployedContracts{IBCHost: common.HexToAddress("0x11")}, testMnemonicPhrase, 0)
+	chainB := NewChain(t, 2, client.Client{}, DeployedContracts{IBCHost: common.HexToAddress("0x21")}, testMnemonicPhrase, 1)
+	// the clients are tracked as if they had been created by the chains
+	chainA.counterparties["client-a"] = chainB
+	chainB.counterparties["client-b"] = chainA
+	connA := chainA.AddTestConnection("client-a", "client-b")
+	connA.Channels = append(connA.Channels, TestChannel{PortID: "transfer", ID: "channel-0", ClientID: "client-a"})
+	connB := chainB.AddTestConnection("client-b", "client-a")
+	connB.Channels = append(connB.Channels, TestChannel{PortID: "transfer", ID: "channel-1", ClientID: "client-b"})
+
+	packet := channeltypes.NewPacket(nil, 1, "transfer", "channel-0", "transfer", "channel-1", channeltypes.Height{}, 0)
+	route := PacketRoute(chainA, chainB)
+	require.Equal(t, "1", route.SourceChainID)
+	require.Equal(t, "2", route.DestinationChainID)
+	require.Equal(t, common.HexToAddress("0x11"), route.SourceIBCHost)
+
+	// the route is the same whichever chain emits the event of the packet
+	for _, c := range []struct {
+		chain *Chain
+		event metrics.PacketEvent
+	}{
+		{chainA, metrics.PacketSent},
+		{chainB, metrics.PacketReceived},
+		{chainA, metrics.PacketAcknowledged},
+	} {
+		actual, ok := c.chain.packetRoute(host.PacketEvent{Event: c.event, Packet: packet})
+		require.True(t, ok, c.event)
+		require.Equal(t, route, actual, c.event)
+	}
+
+	// the route of the packet on an unknown channel is not found
+	other := packet
+	other.SourceChannel = "channel-2"
+	_, ok := chainA.packetRoute(host.PacketEvent{Event: metrics.PacketSent, Packet: other})
+	require.False(t, ok)
+}
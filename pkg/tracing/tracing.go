@@ -0,0 +1,112 @@
+// Package tracing traces the lifecycles of IBC packets with OpenTelemetry.
+//
+// The spans of the steps relaying a packet, which are its SendPacket transaction, the client updates,
+// the proof queries, its RecvPacket transaction and its AcknowledgePacket transaction, share a trace ID
+// derived from the packet and its route, so that they are linked under one trace even if they are recorded by
+// different processes. The spans are recorded by the global TracerProvider of OpenTelemetry, which
+// records nothing unless a provider is set, e.g. by NewOTLPTracerProvider or NewInMemoryTracerProvider.
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+)
+
+// TracerName is the name of the tracer of this module.
+const TracerName = "github.com/hyperledger-labs/yui-ibc-solidity"
+
+// The names of the spans of the steps in a packet lifecycle.
+const (
+	SpanSendPacket        = "SendPacket"
+	SpanUpdateClient      = "UpdateClient"
+	SpanQueryProof        = "QueryProof"
+	SpanRecvPacket        = "RecvPacket"
+	SpanAcknowledgePacket = "AcknowledgePacket"
+)
+
+// The keys of the span attributes.
+const (
+	AttributeChainID            = attribute.Key("ibc.chain_id")
+	AttributeClientID           = attribute.Key("ibc.client_id")
+	AttributeSourcePort         = attribute.Key("ibc.packet.source_port")
+	AttributeSourceChannel      = attribute.Key("ibc.packet.source_channel")
+	AttributeDestinationPort    = attribute.Key("ibc.packet.destination_port")
+	AttributeDestinationChannel = attribute.Key("ibc.packet.destination_channel")
+	AttributeSequence           = attribute.Key("ibc.packet.sequence")
+	AttributeTxHash             = attribute.Key("eth.tx_hash")
+	AttributeGasUsed            = attribute.Key("eth.gas_used")
+)
+
+// Tracer returns the tracer of this module from the global TracerProvider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// PacketRoute is the chains between which a packet is relayed. The ports, the channels and the sequence of
+// a packet are only unique on the chains, which are identified by their chain IDs used by IBC and the address
+// of IBCHost on the source chain, since the contracts may be deployed more than once on the same chain.
+type PacketRoute struct {
+	SourceChainID      string
+	DestinationChainID string
+	SourceIBCHost      common.Address
+}
+
+// PacketSpanContext returns the span context identifying the lifecycle of the packet relayed on the route,
+// whose trace ID and span ID are derived from the route, and the ports, the channels and the sequence of the
+// packet. The span itself is never recorded; it is the remote parent of the spans of the steps.
+func PacketSpanContext(route PacketRoute, packet channeltypes.Packet) trace.SpanContext {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%v/%v/%v/%v/%v/%v/%v/%v",
+		route.SourceChainID, route.DestinationChainID, route.SourceIBCHost.Hex(),
+		packet.SourcePort, packet.SourceChannel, packet.DestinationPort, packet.DestinationChannel, packet.Sequence)))
+	var (
+		traceID trace.TraceID
+		spanID  trace.SpanID
+	)
+	copy(traceID[:], h[:16])
+	copy(spanID[:], h[16:24])
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+// ContextWithPacket returns the context whose spans are recorded in the trace of the packet relayed on the route.
+// The context is returned as it is if it is already in the trace of the packet.
+func ContextWithPacket(ctx context.Context, route PacketRoute, packet channeltypes.Packet) context.Context {
+	sc := PacketSpanContext(route, packet)
+	if trace.SpanContextFromContext(ctx).TraceID() == sc.TraceID() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// PacketAttributes returns the attributes identifying the packet.
+func PacketAttributes(packet channeltypes.Packet) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AttributeSourcePort.String(packet.SourcePort),
+		AttributeSourceChannel.String(packet.SourceChannel),
+		AttributeDestinationPort.String(packet.DestinationPort),
+		AttributeDestinationChannel.String(packet.DestinationChannel),
+		AttributeSequence.Int64(int64(packet.Sequence)),
+	}
+}
+
+// End ends the span, recording the error if it is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+)
+
+func TestPacketSpanContext(t *testing.T) {
+	packet := channeltypes.Packet{
+		Sequence:           1,
+		SourcePort:         "transfer",
+		SourceChannel:      "channel-0",
+		DestinationPort:    "transfer",
+		DestinationChannel: "channel-1",
+	}
+	route := PacketRoute{SourceChainID: "ibc0", DestinationChainID: "ibc1", SourceIBCHost: common.HexToAddress("0x11")}
+	sc := PacketSpanContext(route, packet)
+	require.True(t, sc.IsValid())
+	require.True(t, sc.IsRemote())
+	require.True(t, sc.IsSampled())
+	require.Equal(t, sc, PacketSpanContext(route, packet))
+
+	next := packet
+	next.Sequence++
+	require.NotEqual(t, sc.TraceID(), PacketSpanContext(route, next).TraceID())
+	other := packet
+	other.DestinationChannel = "channel-2"
+	require.NotEqual(t, sc.TraceID(), PacketSpanContext(route, other).TraceID())
+
+	// the same packet relayed between other chains or sent from other contracts is traced separately
+	for _, otherRoute := range []PacketRoute{
+		{SourceChainID: "ibc2", DestinationChainID: "ibc1", SourceIBCHost: route.SourceIBCHost},
+		{SourceChainID: "ibc0", DestinationChainID: "ibc2", SourceIBCHost: route.SourceIBCHost},
+		{SourceChainID: "ibc0", DestinationChainID: "ibc1", SourceIBCHost: common.HexToAddress("0x21")},
+	} {
+		require.NotEqual(t, sc.TraceID(), PacketSpanContext(otherRoute, packet).TraceID(), otherRoute)
+	}
+}
+
+func TestPacketTrace(t *testing.T) {
+	provider, exporter := NewInMemoryTracerProvider()
+	tracer := provider.Tracer(TracerName)
+	packet := channeltypes.Packet{Sequence: 3, SourcePort: "transfer", SourceChannel: "channel-0", DestinationPort: "transfer", DestinationChannel: "channel-0"}
+	route := PacketRoute{SourceChainID: "ibc0", DestinationChainID: "ibc1", SourceIBCHost: common.HexToAddress("0x11")}
+
+	// the steps recorded separately are linked by the packet
+	ctx := ContextWithPacket(context.Background(), route, packet)
+	_, span := tracer.Start(ctx, SpanSendPacket)
+	End(span, nil)
+	ctx, span = tracer.Start(ContextWithPacket(context.Background(), route, packet), SpanUpdateClient)
+	// the context already in the trace of the packet is kept
+	require.Equal(t, ctx, ContextWithPacket(ctx, route, packet))
+	_, child := tracer.Start(ctx, SpanQueryProof)
+	End(child, errors.New("missing trie node"))
+	End(span, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	sc := PacketSpanContext(route, packet)
+	for _, s := range spans {
+		require.Equal(t, sc.TraceID(), s.SpanContext.TraceID())
+	}
+	require.Equal(t, SpanSendPacket, spans[0].Name)
+	require.Equal(t, sc.SpanID(), spans[0].Parent.SpanID())
+	require.Equal(t, SpanQueryProof, spans[1].Name)
+	require.Equal(t, spans[2].SpanContext.SpanID(), spans[1].Parent.SpanID())
+	require.Equal(t, codes.Error, spans[1].Status.Code)
+	require.Len(t, spans[1].Events, 1)
+}
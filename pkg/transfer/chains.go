@@ -0,0 +1,159 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/simpletoken"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/metrics"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/tracing"
+)
+
+// chains sends the transactions of the steps of a transfer to the chains of the endpoints.
+type chains interface {
+	approve(ctx context.Context, ep Endpoint, token common.Address, amount uint64) error
+	deposit(ctx context.Context, ep Endpoint, token common.Address, amount uint64) error
+	// sendTransfer sends the tokens and returns the packet, which times out after the timeout blocks
+	// of the destination chain.
+	sendTransfer(ctx context.Context, src, dst Endpoint, denom string, amount uint64, receiver common.Address, timeoutBlocks uint64) (*channeltypes.Packet, error)
+	// recvPacket receives the packet on the destination chain and returns the acknowledgement written for it.
+	recvPacket(ctx context.Context, src, dst Endpoint, packet channeltypes.Packet) ([]byte, error)
+	acknowledgePacket(ctx context.Context, src, dst Endpoint, packet channeltypes.Packet, acknowledgement []byte) error
+	withdraw(ctx context.Context, ep Endpoint, token common.Address, amount uint64, receiver common.Address) error
+}
+
+// hostChains sends the transactions to the contracts bound to the chains of the endpoints, and records
+// the spans of the packet in its trace.
+type hostChains struct{}
+
+var _ chains = hostChains{}
+
+func (hostChains) approve(ctx context.Context, ep Endpoint, token common.Address, amount uint64) error {
+	erc20, err := simpletoken.NewSimpletoken(token, ep.Chain.Client())
+	if err != nil {
+		return err
+	}
+	tx, err := erc20.Approve(ep.TxOpts(ctx), ep.Chain.ContractConfig.GetICS20BankAddress(), new(big.Int).SetUint64(amount))
+	if err != nil {
+		return err
+	}
+	return ep.Chain.WaitForSuccess(ctx, tx)
+}
+
+func (hostChains) deposit(ctx context.Context, ep Endpoint, token common.Address, amount uint64) error {
+	opts := ep.TxOpts(ctx)
+	tx, err := ep.Chain.ICS20Bank.Deposit(opts, token, new(big.Int).SetUint64(amount), opts.From)
+	if err != nil {
+		return err
+	}
+	return ep.Chain.WaitForSuccess(ctx, tx)
+}
+
+func (hostChains) sendTransfer(ctx context.Context, src, dst Endpoint, denom string, amount uint64, receiver common.Address, timeoutBlocks uint64) (*channeltypes.Packet, error) {
+	start := time.Now()
+	block, err := dst.Chain.Client().BlockByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := src.Chain.ICS20Transfer.SendTransfer(
+		src.TxOpts(ctx),
+		denom,
+		amount,
+		receiver,
+		src.PortID, src.ChannelID,
+		block.NumberU64()+timeoutBlocks,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := src.Chain.WaitForReceipt(ctx, tx)
+	if err != nil {
+		return nil, err
+	} else if rc.Status() != gethtypes.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("failed to call transaction: tx=%v reason='%v'", tx.Hash().Hex(), rc.RevertReason())
+	}
+	for _, ev := range src.Chain.PacketEvents(rc.Logs()) {
+		if ev.Event != metrics.PacketSent {
+			continue
+		}
+		// the span starts before the packet that identifies its trace is known
+		_, span := startSpan(packetContext(ctx, src, dst, ev.Packet), src.Chain, tracing.SpanSendPacket, trace.WithTimestamp(start))
+		span.SetAttributes(tracing.AttributeTxHash.String(rc.TxHash().Hex()), tracing.AttributeGasUsed.Int64(int64(rc.GasUsed())))
+		span.End()
+		return &ev.Packet, nil
+	}
+	return nil, fmt.Errorf("no packet sent: tx=%v", tx.Hash().Hex())
+}
+
+func (hostChains) recvPacket(ctx context.Context, src, dst Endpoint, packet channeltypes.Packet) ([]byte, error) {
+	ctx = packetContext(ctx, src, dst, packet)
+	if err := updateClient(ctx, dst, src); err != nil {
+		return nil, err
+	}
+	if err := recordSpan(ctx, dst.Chain, tracing.SpanRecvPacket, func(ctx context.Context) error {
+		return dst.Chain.RecvPacket(ctx, dst.TxOpts(ctx), src.Chain, packet)
+	}); err != nil {
+		return nil, err
+	}
+	return dst.Chain.FindAcknowledgement(ctx, packet.DestinationPort, packet.DestinationChannel, packet.Sequence)
+}
+
+func (hostChains) acknowledgePacket(ctx context.Context, src, dst Endpoint, packet channeltypes.Packet, acknowledgement []byte) error {
+	ctx = packetContext(ctx, src, dst, packet)
+	if err := updateClient(ctx, src, dst); err != nil {
+		return err
+	}
+	return recordSpan(ctx, src.Chain, tracing.SpanAcknowledgePacket, func(ctx context.Context) error {
+		return src.Chain.AcknowledgePacket(ctx, src.TxOpts(ctx), dst.Chain, packet, acknowledgement)
+	})
+}
+
+func (hostChains) withdraw(ctx context.Context, ep Endpoint, token common.Address, amount uint64, receiver common.Address) error {
+	tx, err := ep.Chain.ICS20Bank.Withdraw(ep.TxOpts(ctx), token, new(big.Int).SetUint64(amount), receiver)
+	if err != nil {
+		return err
+	}
+	return ep.Chain.WaitForSuccess(ctx, tx)
+}
+
+// updateClient updates the client of the channel of the endpoint to the latest block of the counterparty chain.
+func updateClient(ctx context.Context, ep, counterparty Endpoint) error {
+	clientID, _, err := ep.Chain.ChannelClient(ctx, ep.PortID, ep.ChannelID)
+	if err != nil {
+		return err
+	}
+	return recordSpan(ctx, ep.Chain, tracing.SpanUpdateClient, func(ctx context.Context) error {
+		return ep.Chain.UpdateClient(ctx, ep.TxOpts(ctx), counterparty.Chain, clientID, nil)
+	}, trace.WithAttributes(tracing.AttributeClientID.String(clientID)))
+}
+
+// packetContext returns the context whose spans are recorded in the trace of the packet sent from src to dst.
+func packetContext(ctx context.Context, src, dst Endpoint, packet channeltypes.Packet) context.Context {
+	route := tracing.PacketRoute{
+		SourceChainID:      src.Chain.ChainIDString(),
+		DestinationChainID: dst.Chain.ChainIDString(),
+		SourceIBCHost:      src.Chain.IBCHostAddress(),
+	}
+	return tracing.ContextWithPacket(ctx, route, packet)
+}
+
+func startSpan(ctx context.Context, chain *host.Chain, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append(opts, trace.WithAttributes(tracing.AttributeChainID.String(chain.ChainIDString())))
+	return tracing.Tracer().Start(ctx, name, opts...)
+}
+
+// recordSpan records the span of the step on the chain, which ends with the error of the step if any.
+func recordSpan(ctx context.Context, chain *host.Chain, name string, step func(ctx context.Context) error, opts ...trace.SpanStartOption) error {
+	ctx, span := startSpan(ctx, chain, name, opts...)
+	err := step(ctx)
+	tracing.End(span, err)
+	return err
+}
@@ -0,0 +1,204 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app/denom"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+)
+
+// DefaultTimeoutBlocks is the default number of blocks of the destination chain after which a transfer times out.
+const DefaultTimeoutBlocks uint64 = 1000
+
+// ErrAcknowledgementFailed is returned when the destination chain acknowledges a transfer with an error.
+// The tokens are refunded to the sender on the source chain in that case.
+var ErrAcknowledgementFailed = errors.New("acknowledgement failed")
+
+// Step is a step of a token transfer.
+type Step int
+
+const (
+	// StepApprove approves the ICS20Bank to move the tokens of the sender.
+	StepApprove Step = iota
+	// StepDeposit deposits the tokens of the sender to the ICS20Bank.
+	StepDeposit
+	// StepSend sends the transfer packet on the source chain.
+	StepSend
+	// StepRecv relays the packet to the destination chain.
+	StepRecv
+	// StepAcknowledge relays the acknowledgement back to the source chain.
+	StepAcknowledge
+	// StepWithdraw withdraws the returned tokens from the ICS20Bank on the destination chain.
+	StepWithdraw
+)
+
+func (s Step) String() string {
+	switch s {
+	case StepApprove:
+		return "approve"
+	case StepDeposit:
+		return "deposit"
+	case StepSend:
+		return "send"
+	case StepRecv:
+		return "recv"
+	case StepAcknowledge:
+		return "acknowledge"
+	case StepWithdraw:
+		return "withdraw"
+	default:
+		return fmt.Sprintf("Step(%d)", int(s))
+	}
+}
+
+// ProgressFunc is called each time a step of a transfer is completed.
+type ProgressFunc func(step Step)
+
+// Endpoint is one end of an ICS-20 channel.
+type Endpoint struct {
+	Chain     *host.Chain
+	PortID    string
+	ChannelID string
+	// TxOpts generates the options of the transactions on the chain. They are signed by the account that
+	// sends the tokens from the source chain, or receives the returned tokens on the destination chain
+	// before withdrawing them, which also relays the packet and its acknowledgement to the chain.
+	TxOpts client.GenTxOpts
+}
+
+// Result is the result of a completed transfer.
+type Result struct {
+	Packet          channeltypes.Packet
+	Acknowledgement app.Acknowledgement
+	// Denom is the denomination of the tokens received on the destination chain.
+	Denom string
+}
+
+// Succeeded returns true if the transfer was acknowledged successfully.
+func (r Result) Succeeded() bool {
+	return r.Acknowledgement.Success()
+}
+
+// Transferer runs ICS-20 token transfers between chains end-to-end, including the relay of the packet
+// and its acknowledgement. The clients of both chains are updated right before the packet and its
+// acknowledgement are relayed.
+type Transferer struct {
+	chains chains
+
+	// TimeoutBlocks is the number of blocks of the destination chain after which a transfer times out.
+	TimeoutBlocks uint64
+	// Progress is called each time a step is completed if it is not nil.
+	Progress ProgressFunc
+}
+
+// NewTransferer creates a new Transferer that sends the transactions to the chains of the endpoints.
+func NewTransferer() *Transferer {
+	return &Transferer{
+		chains:        hostChains{},
+		TimeoutBlocks: DefaultTimeoutBlocks,
+	}
+}
+
+// Transfer sends the ERC20 token native to the source chain to the receiver on the destination chain.
+// The tokens of the source account are approved and deposited to the ICS20Bank before they are sent,
+// and the receiver is credited with the voucher denomination in the ICS20Bank of the destination chain.
+func (t *Transferer) Transfer(
+	ctx context.Context,
+	src, dst Endpoint,
+	token common.Address,
+	amount uint64,
+	receiver common.Address,
+) (*Result, error) {
+	if err := t.chains.approve(ctx, src, token, amount); err != nil {
+		return nil, fmt.Errorf("failed to approve: %w", err)
+	}
+	t.progress(StepApprove)
+
+	if err := t.chains.deposit(ctx, src, token, amount); err != nil {
+		return nil, fmt.Errorf("failed to deposit: %w", err)
+	}
+	t.progress(StepDeposit)
+
+	return t.send(ctx, src, dst, baseDenom(token), amount, receiver)
+}
+
+// Return sends the vouchers of the ERC20 token back from the source chain to the chain the token is
+// native to, and then withdraws the returned tokens of the destination account to the receiver.
+// The vouchers are those received on the source chain through the source channel.
+func (t *Transferer) Return(
+	ctx context.Context,
+	src, dst Endpoint,
+	token common.Address,
+	amount uint64,
+	receiver common.Address,
+) (*Result, error) {
+	voucher := denom.Trace{BaseDenom: baseDenom(token)}.Wrap(src.PortID, src.ChannelID)
+	result, err := t.send(ctx, src, dst, voucher.FullPath(), amount, dst.TxOpts(ctx).From)
+	if err != nil {
+		return result, err
+	}
+
+	if err := t.chains.withdraw(ctx, dst, token, amount, receiver); err != nil {
+		return result, fmt.Errorf("failed to withdraw: %w", err)
+	}
+	t.progress(StepWithdraw)
+	return result, nil
+}
+
+// send sends the tokens of the source account and relays the packet and its acknowledgement.
+func (t *Transferer) send(
+	ctx context.Context,
+	src, dst Endpoint,
+	sendDenom string,
+	amount uint64,
+	receiver common.Address,
+) (*Result, error) {
+	packet, err := t.chains.sendTransfer(ctx, src, dst, sendDenom, amount, receiver, t.TimeoutBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send transfer: %w", err)
+	}
+	result := &Result{
+		Packet: *packet,
+		Denom:  denom.ReceivedDenom(src.PortID, src.ChannelID, dst.PortID, dst.ChannelID, sendDenom),
+	}
+	t.progress(StepSend)
+
+	bz, err := t.chains.recvPacket(ctx, src, dst, *packet)
+	if err != nil {
+		return result, fmt.Errorf("failed to relay packet: %w", err)
+	}
+	t.progress(StepRecv)
+
+	ack, err := app.DecodeAcknowledgement(bz)
+	if err != nil {
+		return result, err
+	}
+	if err := t.chains.acknowledgePacket(ctx, src, dst, *packet, bz); err != nil {
+		return result, fmt.Errorf("failed to relay acknowledgement: %w", err)
+	}
+	result.Acknowledgement = ack
+	t.progress(StepAcknowledge)
+
+	if !ack.Success() {
+		return result, fmt.Errorf("%w: sequence=%v error=%v", ErrAcknowledgementFailed, packet.Sequence, ack.ErrorMessage())
+	}
+	return result, nil
+}
+
+func (t *Transferer) progress(step Step) {
+	if t.Progress != nil {
+		t.Progress(step)
+	}
+}
+
+// baseDenom returns the denomination of the ERC20 token in the ICS20Bank.
+func baseDenom(token common.Address) string {
+	return strings.ToLower(token.String())
+}
@@ -0,0 +1,199 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+)
+
+// fakeChains records the calls of the steps instead of sending the transactions, and acknowledges every
+// packet with ack.
+type fakeChains struct {
+	ack []byte
+	// fail is the prefix of the calls that fail if it is not empty.
+	fail string
+
+	calls []string
+}
+
+var _ chains = (*fakeChains)(nil)
+
+func (c *fakeChains) call(format string, args ...interface{}) error {
+	call := fmt.Sprintf(format, args...)
+	c.calls = append(c.calls, call)
+	if c.fail != "" && strings.HasPrefix(call, c.fail) {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+func (c *fakeChains) approve(ctx context.Context, ep Endpoint, token common.Address, amount uint64) error {
+	return c.call("approve %v", amount)
+}
+
+func (c *fakeChains) deposit(ctx context.Context, ep Endpoint, token common.Address, amount uint64) error {
+	return c.call("deposit %v", amount)
+}
+
+func (c *fakeChains) sendTransfer(ctx context.Context, src, dst Endpoint, denom string, amount uint64, receiver common.Address, timeoutBlocks uint64) (*channeltypes.Packet, error) {
+	if err := c.call("sendTransfer %v %v %v %v", denom, amount, receiver.Hex(), timeoutBlocks); err != nil {
+		return nil, err
+	}
+	return &channeltypes.Packet{
+		Sequence:           1,
+		SourcePort:         src.PortID,
+		SourceChannel:      src.ChannelID,
+		DestinationPort:    dst.PortID,
+		DestinationChannel: dst.ChannelID,
+	}, nil
+}
+
+func (c *fakeChains) recvPacket(ctx context.Context, src, dst Endpoint, packet channeltypes.Packet) ([]byte, error) {
+	if err := c.call("recvPacket %v", packet.DestinationChannel); err != nil {
+		return nil, err
+	}
+	return c.ack, nil
+}
+
+func (c *fakeChains) acknowledgePacket(ctx context.Context, src, dst Endpoint, packet channeltypes.Packet, acknowledgement []byte) error {
+	return c.call("acknowledgePacket %v %x", packet.SourceChannel, acknowledgement)
+}
+
+func (c *fakeChains) withdraw(ctx context.Context, ep Endpoint, token common.Address, amount uint64, receiver common.Address) error {
+	return c.call("withdraw %v %v", amount, receiver.Hex())
+}
+
+func newTestTransferer(chains *fakeChains) (*Transferer, *[]Step) {
+	var steps []Step
+	t := &Transferer{
+		chains:        chains,
+		TimeoutBlocks: DefaultTimeoutBlocks,
+		Progress:      func(step Step) { steps = append(steps, step) },
+	}
+	return t, &steps
+}
+
+func testEndpoint(t *testing.T, channelID string) (Endpoint, common.Address) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return Endpoint{
+		PortID:    "transfer",
+		ChannelID: channelID,
+		TxOpts:    client.MakeGenTxOpts(big.NewInt(1337), key),
+	}, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+var (
+	testToken     = common.HexToAddress("0xAbCdEf0000000000000000000000000000000001")
+	testReceiver  = common.HexToAddress("0x0000000000000000000000000000000000000002")
+	testBaseDenom = "0xabcdef0000000000000000000000000000000001"
+)
+
+func TestTransfer(t *testing.T) {
+	ctx := context.Background()
+	srcEndpoint, _ := testEndpoint(t, "channel-0")
+	dstEndpoint, _ := testEndpoint(t, "channel-1")
+	chains := &fakeChains{ack: app.EncodeICS20Acknowledgement(app.NewResultAcknowledgement(nil))}
+	transferer, steps := newTestTransferer(chains)
+	transferer.TimeoutBlocks = 10
+
+	result, err := transferer.Transfer(ctx, srcEndpoint, dstEndpoint, testToken, 100, testReceiver)
+	require.NoError(t, err)
+	require.True(t, result.Succeeded())
+	require.Equal(t, []Step{StepApprove, StepDeposit, StepSend, StepRecv, StepAcknowledge}, *steps)
+	require.Equal(t, []string{
+		"approve 100",
+		"deposit 100",
+		"sendTransfer " + testBaseDenom + " 100 " + testReceiver.Hex() + " 10",
+		"recvPacket channel-1",
+		"acknowledgePacket channel-0 01",
+	}, chains.calls)
+	// the receiver is credited with the voucher of the destination channel
+	require.Equal(t, "transfer/channel-1/"+testBaseDenom, result.Denom)
+	require.Equal(t, "channel-0", result.Packet.SourceChannel)
+}
+
+func TestTransferFailedStep(t *testing.T) {
+	ctx := context.Background()
+	srcEndpoint, _ := testEndpoint(t, "channel-0")
+	dstEndpoint, _ := testEndpoint(t, "channel-1")
+	cases := []struct {
+		fail  string
+		steps []Step
+	}{
+		{"approve", nil},
+		{"deposit", []Step{StepApprove}},
+		{"sendTransfer", []Step{StepApprove, StepDeposit}},
+		{"recvPacket", []Step{StepApprove, StepDeposit, StepSend}},
+		{"acknowledgePacket", []Step{StepApprove, StepDeposit, StepSend, StepRecv}},
+	}
+	for _, c := range cases {
+		t.Run(c.fail, func(t *testing.T) {
+			chains := &fakeChains{ack: app.EncodeICS20Acknowledgement(app.NewResultAcknowledgement(nil)), fail: c.fail}
+			transferer, steps := newTestTransferer(chains)
+			_, err := transferer.Transfer(ctx, srcEndpoint, dstEndpoint, testToken, 100, testReceiver)
+			require.Error(t, err)
+			require.False(t, errors.Is(err, ErrAcknowledgementFailed))
+			require.Equal(t, c.steps, *steps)
+		})
+	}
+}
+
+func TestTransferAcknowledgementFailed(t *testing.T) {
+	ctx := context.Background()
+	srcEndpoint, _ := testEndpoint(t, "channel-0")
+	dstEndpoint, _ := testEndpoint(t, "channel-1")
+	chains := &fakeChains{ack: app.EncodeICS20Acknowledgement(app.NewErrorAcknowledgement("failed"))}
+	transferer, steps := newTestTransferer(chains)
+
+	// the failed acknowledgement is still relayed so that the tokens are refunded to the sender
+	result, err := transferer.Transfer(ctx, srcEndpoint, dstEndpoint, testToken, 100, testReceiver)
+	require.ErrorIs(t, err, ErrAcknowledgementFailed)
+	require.NotNil(t, result)
+	require.False(t, result.Succeeded())
+	require.Equal(t, []Step{StepApprove, StepDeposit, StepSend, StepRecv, StepAcknowledge}, *steps)
+	require.Equal(t, "acknowledgePacket channel-0 00", chains.calls[len(chains.calls)-1])
+
+	// the returned tokens are not withdrawn
+	*steps = nil
+	chains.calls = nil
+	result, err = transferer.Return(ctx, dstEndpoint, srcEndpoint, testToken, 100, testReceiver)
+	require.ErrorIs(t, err, ErrAcknowledgementFailed)
+	require.False(t, result.Succeeded())
+	require.Equal(t, []Step{StepSend, StepRecv, StepAcknowledge}, *steps)
+	require.Equal(t, "acknowledgePacket channel-1 00", chains.calls[len(chains.calls)-1])
+}
+
+func TestReturn(t *testing.T) {
+	ctx := context.Background()
+	srcEndpoint, _ := testEndpoint(t, "channel-1")
+	dstEndpoint, dstAccount := testEndpoint(t, "channel-0")
+	chains := &fakeChains{ack: app.EncodeICS20Acknowledgement(app.NewResultAcknowledgement(nil))}
+	transferer, steps := newTestTransferer(chains)
+
+	result, err := transferer.Return(ctx, srcEndpoint, dstEndpoint, testToken, 100, testReceiver)
+	require.NoError(t, err)
+	require.True(t, result.Succeeded())
+	require.Equal(t, []Step{StepSend, StepRecv, StepAcknowledge, StepWithdraw}, *steps)
+	// the vouchers received through the source channel are sent to the destination account,
+	// which withdraws the tokens to the receiver
+	require.Equal(t, []string{
+		"sendTransfer transfer/channel-1/" + testBaseDenom + " 100 " + dstAccount.Hex() + " " + fmt.Sprint(DefaultTimeoutBlocks),
+		"recvPacket channel-0",
+		"acknowledgePacket channel-1 01",
+		"withdraw 100 " + testReceiver.Hex(),
+	}, chains.calls)
+	// the vouchers are unwound to the token on the chain it is native to
+	require.Equal(t, testBaseDenom, result.Denom)
+}
@@ -2,19 +2,24 @@ package e2e
 
 import (
 	"context"
-	"fmt"
 	"math/big"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app/denom"
 	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
 	clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/monitor"
 	ibctesting "github.com/hyperledger-labs/yui-ibc-solidity/pkg/testing"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/tracing"
 	testchain0 "github.com/hyperledger-labs/yui-ibc-solidity/tests/e2e/config/chain0"
 	testchain1 "github.com/hyperledger-labs/yui-ibc-solidity/tests/e2e/config/chain1"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 const mnemonicPhrase = "math razor capable expose worth grape metal sunset metal sudden usage scheme"
@@ -25,6 +30,25 @@ type ChainTestSuite struct {
 	coordinator ibctesting.Coordinator
 	chainA      *ibctesting.Chain
 	chainB      *ibctesting.Chain
+
+	tracerProvider *sdktrace.TracerProvider
+}
+
+// SetupSuite exports the spans of the packets to the OTLP endpoint if OTEL_EXPORTER_OTLP_ENDPOINT is set.
+func (suite *ChainTestSuite) SetupSuite() {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return
+	}
+	provider, err := tracing.NewOTLPTracerProvider(context.Background(), "yui-ibc-solidity-e2e", "", false)
+	suite.Require().NoError(err)
+	otel.SetTracerProvider(provider)
+	suite.tracerProvider = provider
+}
+
+func (suite *ChainTestSuite) TearDownSuite() {
+	if suite.tracerProvider != nil {
+		suite.Require().NoError(suite.tracerProvider.Shutdown(context.Background()))
+	}
 }
 
 func (suite *ChainTestSuite) SetupTest() {
@@ -35,8 +59,9 @@ func (suite *ChainTestSuite) SetupTest() {
 	suite.Require().NoError(err)
 
 	ibcID := uint64(time.Now().UnixNano())
-	suite.chainA = ibctesting.NewChain(suite.T(), 2018, *chainClientA, testchain0.Contract, mnemonicPhrase, ibcID)
-	suite.chainB = ibctesting.NewChain(suite.T(), 3018, *chainClientB, testchain1.Contract, mnemonicPhrase, ibcID)
+	// the blocks of IBFT2 are final once committed
+	suite.chainA = ibctesting.NewChain(suite.T(), 2018, chainClientA.WithCache(client.DefaultCacheSize, 0), testchain0.Contract, mnemonicPhrase, ibcID)
+	suite.chainB = ibctesting.NewChain(suite.T(), 3018, chainClientB.WithCache(client.DefaultCacheSize, 0), testchain1.Contract, mnemonicPhrase, ibcID)
 	suite.coordinator = ibctesting.NewCoordinator(suite.T(), suite.chainA, suite.chainB)
 }
 
@@ -54,10 +79,25 @@ func (suite ChainTestSuite) TestChannel() {
 	chainA := suite.chainA
 	chainB := suite.chainB
 
+	fromA, fromB := chainA.LastHeader().Number.Uint64(), chainB.LastHeader().Number.Uint64()
 	clientA, clientB := suite.coordinator.SetupClients(ctx, chainA, chainB, clienttypes.BesuIBFT2Client)
 	connA, connB := suite.coordinator.CreateConnection(ctx, chainA, chainB, clientA, clientB)
 	chanA, chanB := suite.coordinator.CreateChannel(ctx, chainA, chainB, connA, connB, ibctesting.TransferPort, ibctesting.TransferPort, channeltypes.UNORDERED)
 
+	// ensure that the headers submitted during the handshakes are the canonical ones
+	evidences, err := monitor.NewMisbehaviourMonitor(chainA.Host(), chainB.Host(), clientA).Check(ctx, fromA, chainA.LastHeader().Number.Uint64())
+	suite.Require().NoError(err)
+	suite.Require().Empty(evidences)
+	evidences, err = monitor.NewMisbehaviourMonitor(chainB.Host(), chainA.Host(), clientB).Check(ctx, fromB, chainB.LastHeader().Number.Uint64())
+	suite.Require().NoError(err)
+	suite.Require().Empty(evidences)
+	statuses, err := monitor.NewStalenessMonitor(chainA.Host(), chainB.Host()).Check(ctx)
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(statuses)
+	for _, status := range statuses {
+		suite.Require().False(status.Unupdatable, status.String())
+	}
+
 	/// Tests for Transfer module ///
 
 	balanceA0, err := chainA.SimpleToken.BalanceOf(chainA.CallOpts(ctx, relayer), chainA.CallOpts(ctx, deployerA).From)
@@ -128,14 +168,14 @@ func (suite ChainTestSuite) TestChannel() {
 	transferPacket, err := chainA.GetLastSentPacket(ctx, chanA.PortID, chanA.ID)
 	suite.Require().NoError(err)
 	suite.Require().Error(suite.coordinator.HandlePacketRecv(ctx, chainB, chainA, chanB, chanA, *transferPacket))
-	waitForDelayPeriod()
+	suite.Require().NoError(chainB.WaitForDelayPeriod(ctx, chanB, nil))
 	suite.Require().NoError(suite.coordinator.HandlePacketRecv(ctx, chainB, chainA, chanB, chanA, *transferPacket))
 	suite.Require().Error(suite.coordinator.HandlePacketAcknowledgement(ctx, chainA, chainB, chanA, chanB, *transferPacket, []byte{1}))
-	waitForDelayPeriod()
+	suite.Require().NoError(chainA.WaitForDelayPeriod(ctx, chanA, nil))
 	suite.Require().NoError(suite.coordinator.HandlePacketAcknowledgement(ctx, chainA, chainB, chanA, chanB, *transferPacket, []byte{1}))
 
 	// ensure that chainB has correct balance
-	expectedDenom := fmt.Sprintf("%v/%v/%v", chanB.PortID, chanB.ID, baseDenom)
+	expectedDenom := denom.ReceivedDenom(chanA.PortID, chanA.ID, chanB.PortID, chanB.ID, baseDenom)
 	balance, err := chainB.ICS20Bank.BalanceOf(chainB.CallOpts(ctx, relayer), chainB.CallOpts(ctx, bobB).From, expectedDenom)
 	suite.Require().NoError(err)
 	suite.Require().Equal(int64(100), balance.Int64())
@@ -169,12 +209,11 @@ func (suite ChainTestSuite) TestChannel() {
 	transferPacket, err = chainB.GetLastSentPacket(ctx, chanB.PortID, chanB.ID)
 	suite.Require().NoError(err)
 	suite.Require().Error(suite.coordinator.HandlePacketRecv(ctx, chainA, chainB, chanA, chanB, *transferPacket))
-	waitForDelayPeriod()
-	suite.Require().Error(suite.coordinator.HandlePacketRecv(ctx, chainA, chainB, chanA, chanB, *transferPacket))
-	waitForDelayPeriod()
+	// the block delay on chainA is doubled, so the delay period ends on its height rather than its time
+	suite.Require().NoError(chainA.WaitForDelayPeriod(ctx, chanA, nil))
 	suite.Require().NoError(suite.coordinator.HandlePacketRecv(ctx, chainA, chainB, chanA, chanB, *transferPacket))
 	suite.Require().Error(suite.coordinator.HandlePacketAcknowledgement(ctx, chainB, chainA, chanB, chanA, *transferPacket, []byte{1}))
-	waitForDelayPeriod()
+	suite.Require().NoError(chainB.WaitForDelayPeriod(ctx, chanB, nil))
 	suite.Require().NoError(suite.coordinator.HandlePacketAcknowledgement(ctx, chainB, chainA, chanB, chanA, *transferPacket, []byte{1}))
 
 	// withdraw tokens from the bank
@@ -205,10 +244,6 @@ func (suite ChainTestSuite) TestChannel() {
 	suite.Require().Equal(channeltypes.Channel_State(chanData.State), channeltypes.CLOSED)
 }
 
-func waitForDelayPeriod() {
-	time.Sleep(time.Duration(ibctesting.DefaultDelayPeriod) * time.Nanosecond)
-}
-
 func TestChainTestSuite(t *testing.T) {
 	suite.Run(t, new(ChainTestSuite))
 }
@@ -2,19 +2,27 @@ package tests
 
 import (
 	"context"
-	"fmt"
 	"math/big"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/audit"
 	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/client"
 	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/consts"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/app/denom"
 	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
 	clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	connectiontypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/connection"
 	ibctesting "github.com/hyperledger-labs/yui-ibc-solidity/pkg/testing"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/tracing"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/transfer"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const mnemonicPhrase = "math razor capable expose worth grape metal sunset metal sudden usage scheme"
@@ -56,6 +64,11 @@ func (suite *ContractTestSuite) TestChannel() {
 	connA, connB := suite.coordinator.CreateConnection(ctx, chainA, chainB, clientA, clientB)
 	chanA, chanB := suite.coordinator.CreateChannel(ctx, chainA, chainB, connA, connB, ibctesting.TransferPort, ibctesting.TransferPort, channeltypes.UNORDERED)
 
+	// ensure that the channels and connections on both chains point to each other
+	report, err := audit.CheckChannel(ctx, chainA.Host(), chainB.Host(), chanA.PortID, chanA.ID)
+	suite.Require().NoError(err)
+	suite.Require().True(report.OK(), report.String())
+
 	/// Tests for Transfer module ///
 
 	balance0, err := chainA.SimpleToken.BalanceOf(chainA.CallOpts(ctx, relayer), chainA.CallOpts(ctx, deployer).From)
@@ -105,35 +118,70 @@ func (suite *ContractTestSuite) TestChannel() {
 	// relay the packet
 	transferPacket, err := chainA.GetLastSentPacket(ctx, chanA.PortID, chanA.ID)
 	suite.Require().NoError(err)
-	suite.Require().NoError(suite.coordinator.HandlePacketRecv(ctx, chainB, chainA, chanB, chanA, *transferPacket))
-	suite.Require().NoError(suite.coordinator.HandlePacketAcknowledgement(ctx, chainA, chainB, chanA, chanB, *transferPacket, []byte{1}))
+	seqs, err := chainA.QueryUnrelayedSequences(ctx, chainB, chanA, chanB)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]uint64{transferPacket.Sequence}, seqs.Packets, ibctesting.FormatPacket(*transferPacket))
+	bz, err := suite.coordinator.RecvPacket(ctx, chainB, chainA, chanB, chanA, *transferPacket)
+	suite.Require().NoError(err)
+	ack, err := app.DecodeAcknowledgement(bz)
+	suite.Require().NoError(err)
+	suite.Require().True(ack.Success(), ack.String())
+	seqs, err = chainA.QueryUnrelayedSequences(ctx, chainB, chanA, chanB)
+	suite.Require().NoError(err)
+	suite.Require().Empty(seqs.Packets)
+	suite.Require().Equal([]uint64{transferPacket.Sequence}, seqs.Acknowledgements)
+	ack, err = suite.coordinator.RelayAcknowledgement(ctx, chainA, chainB, chanA, chanB, *transferPacket)
+	suite.Require().NoError(err)
+	suite.Require().True(ack.Success(), ack.String())
+	seqs, err = chainA.QueryUnrelayedSequences(ctx, chainB, chanA, chanB)
+	suite.Require().NoError(err)
+	suite.Require().Empty(seqs.Packets)
+	suite.Require().Empty(seqs.Acknowledgements)
 
 	// ensure that chainB has correct balance
-	expectedDenom := fmt.Sprintf("%v/%v/%v", chanB.PortID, chanB.ID, baseDenom)
+	expectedDenom := denom.ReceivedDenom(chanA.PortID, chanA.ID, chanB.PortID, chanB.ID, baseDenom)
 	balance, err := chainB.ICS20Bank.BalanceOf(chainB.CallOpts(ctx, relayer), chainB.CallOpts(ctx, bob).From, expectedDenom)
 	suite.Require().NoError(err)
 	suite.Require().Equal(int64(100), balance.Int64())
 
-	// try to transfer the token to chainA
-	suite.Require().NoError(chainB.WaitIfNoError(ctx)(
-		chainB.ICS20Transfer.SendTransfer(
-			chainB.TxOpts(ctx, bob),
-			expectedDenom,
-			100,
-			chainA.CallOpts(ctx, alice).From,
-			chanB.PortID,
-			chanB.ID,
-			uint64(chainB.LastHeader().Number.Int64())+1000,
-		),
-	))
+	// try to transfer the token to chainA in two packets
+	sendTransferToA := func(amount uint64) {
+		suite.Require().NoError(chainB.WaitIfNoError(ctx)(
+			chainB.ICS20Transfer.SendTransfer(
+				chainB.TxOpts(ctx, bob),
+				expectedDenom,
+				amount,
+				chainA.CallOpts(ctx, alice).From,
+				chanB.PortID,
+				chanB.ID,
+				uint64(chainB.LastHeader().Number.Int64())+1000,
+			),
+		))
+	}
+	sendTransferToA(50)
+	transferPacket, err = chainB.GetLastSentPacket(ctx, chanB.PortID, chanB.ID)
+	suite.Require().NoError(err)
+	sendTransferToA(50)
 	chainB.UpdateHeader()
 	suite.Require().NoError(suite.coordinator.UpdateClient(ctx, chainA, chainB, clientA))
 
-	// relay the packet
-	transferPacket, err = chainB.GetLastSentPacket(ctx, chanB.PortID, chanB.ID)
+	// relay the first packet and its acknowledgement, and then the rest of the packets
+	ack, err = suite.coordinator.RelayPacket(ctx, chainB, chainA, chanB, chanA, *transferPacket)
 	suite.Require().NoError(err)
-	suite.Require().NoError(suite.coordinator.HandlePacketRecv(ctx, chainA, chainB, chanA, chanB, *transferPacket))
-	suite.Require().NoError(suite.coordinator.HandlePacketAcknowledgement(ctx, chainB, chainA, chanB, chanA, *transferPacket, []byte{1}))
+	suite.Require().True(ack.Success(), ack.String())
+	seqs, err = chainB.QueryUnrelayedSequences(ctx, chainA, chanB, chanA)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]uint64{transferPacket.Sequence + 1}, seqs.Packets)
+	// clear the rest as the clear command does
+	cleared, err := chainB.Host().ClearPackets(ctx, chainB.TxOpts(ctx, relayer), chainA.Host(), chainA.TxOpts(ctx, relayer), chanB.PortID, chanB.ID)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]uint64{transferPacket.Sequence + 1}, cleared.Received, cleared.String())
+	suite.Require().Equal([]uint64{transferPacket.Sequence + 1}, cleared.Acknowledged, cleared.String())
+	suite.Require().Empty(cleared.TimedOut)
+	seqs, err = chainB.QueryUnrelayedSequences(ctx, chainA, chanB, chanA)
+	suite.Require().NoError(err)
+	suite.Require().Empty(seqs.Packets)
+	suite.Require().Empty(seqs.Acknowledgements)
 
 	// withdraw tokens from the bank
 	suite.Require().NoError(chainA.WaitIfNoError(ctx)(
@@ -149,6 +197,17 @@ func (suite *ContractTestSuite) TestChannel() {
 	suite.Require().NoError(err)
 	suite.Require().Equal(balance0.Int64(), balanceA2.Int64())
 
+	// ensure that the commitments stored on both chains match the ones computed in Go
+	for _, c := range []struct {
+		chain   *ibctesting.Chain
+		channel ibctesting.TestChannel
+	}{{chainA, chanA}, {chainB, chanB}} {
+		report, err := audit.AuditCommitments(ctx, c.chain.Host(), c.channel.PortID, c.channel.ID)
+		suite.Require().NoError(err)
+		suite.Require().True(report.OK(), report.String())
+		suite.Require().NotZero(report.Packets)
+	}
+
 	// close channel
 	suite.coordinator.CloseChannel(ctx, chainA, chainB, chanA, chanB)
 	// confirm that the channel is CLOSED on chain A
@@ -163,6 +222,136 @@ func (suite *ContractTestSuite) TestChannel() {
 	suite.Require().Equal(channeltypes.Channel_State(chanData.State), channeltypes.CLOSED)
 }
 
+func (suite *ContractTestSuite) TestTransfer() {
+	ctx := context.Background()
+
+	const (
+		relayer         = ibctesting.RelayerKeyIndex // the key-index of relayer on chain
+		deployer        = ibctesting.RelayerKeyIndex // the key-index of contract deployer on chain
+		bob      uint32 = 2                          // the key-index of bob on chain
+	)
+
+	chainA := suite.chainA
+	chainB := suite.chainB
+
+	clientA, clientB := suite.coordinator.SetupClients(ctx, chainA, chainB, clienttypes.MockClient)
+	connA, connB := suite.coordinator.CreateConnection(ctx, chainA, chainB, clientA, clientB)
+	chanA, chanB := suite.coordinator.CreateChannel(ctx, chainA, chainB, connA, connB, ibctesting.TransferPort, ibctesting.TransferPort, channeltypes.UNORDERED)
+
+	token := chainA.ContractConfig.GetSimpleTokenAddress()
+	balance0, err := chainA.SimpleToken.BalanceOf(chainA.CallOpts(ctx, relayer), chainA.CallOpts(ctx, deployer).From)
+	suite.Require().NoError(err)
+
+	// record the spans of the transfers
+	provider, exporter := tracing.NewInMemoryTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	var steps []transfer.Step
+	transferer := transfer.NewTransferer()
+	transferer.Progress = func(step transfer.Step) {
+		steps = append(steps, step)
+	}
+	txOpts := func(chain *ibctesting.Chain, index uint32) client.GenTxOpts {
+		return func(ctx context.Context) *bind.TransactOpts { return chain.TxOpts(ctx, index) }
+	}
+	endpointA := transfer.Endpoint{Chain: chainA.Host(), PortID: chanA.PortID, ChannelID: chanA.ID, TxOpts: txOpts(chainA, deployer)}
+	endpointB := transfer.Endpoint{Chain: chainB.Host(), PortID: chanB.PortID, ChannelID: chanB.ID, TxOpts: txOpts(chainB, bob)}
+
+	baseDenom := strings.ToLower(token.String())
+	supply0 := ibctesting.RequireSupplyInvariant(suite.T(), ctx, chainA, chainB, chanA, baseDenom)
+
+	// transfer the token to chainB
+	result, err := transferer.Transfer(ctx, endpointA, endpointB, token, 100, chainB.CallOpts(ctx, bob).From)
+	suite.Require().NoError(err)
+	suite.Require().True(result.Succeeded())
+	suite.Require().Equal(chanA.ID, result.Packet.SourceChannel)
+	suite.Require().Equal([]transfer.Step{transfer.StepApprove, transfer.StepDeposit, transfer.StepSend, transfer.StepRecv, transfer.StepAcknowledge}, steps)
+
+	// ensure that the steps relaying the packet are linked in its trace
+	traceID := tracing.PacketSpanContext(ibctesting.PacketRoute(chainA, chainB), result.Packet).TraceID()
+	var spans []string
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID() == traceID {
+			spans = append(spans, span.Name)
+		}
+	}
+	suite.Require().Equal([]string{
+		tracing.SpanSendPacket,
+		tracing.SpanUpdateClient,
+		tracing.SpanRecvPacket,
+		tracing.SpanUpdateClient,
+		tracing.SpanAcknowledgePacket,
+	}, spans)
+	balance, err := chainB.ICS20Bank.BalanceOf(chainB.CallOpts(ctx, relayer), chainB.CallOpts(ctx, bob).From, result.Denom)
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(100), balance.Int64())
+
+	// ensure that the escrowed token backs the vouchers on chainB
+	supply1 := ibctesting.RequireSupplyInvariant(suite.T(), ctx, chainA, chainB, chanA, baseDenom)
+	suite.Require().Equal(result.Denom, supply1.VoucherDenom)
+	suite.Require().Equal(int64(100), supply1.Vouchers.Int64())
+	suite.Require().Equal(int64(100), new(big.Int).Sub(supply1.Escrowed, supply0.Escrowed).Int64())
+
+	// return the token to chainA
+	steps = nil
+	endpointA.TxOpts = txOpts(chainA, bob)
+	result, err = transferer.Return(ctx, endpointB, endpointA, token, 100, chainA.CallOpts(ctx, deployer).From)
+	suite.Require().NoError(err)
+	suite.Require().True(result.Succeeded())
+	suite.Require().Equal(chanB.ID, result.Packet.SourceChannel)
+	suite.Require().Equal([]transfer.Step{transfer.StepSend, transfer.StepRecv, transfer.StepAcknowledge, transfer.StepWithdraw}, steps)
+
+	// ensure that token balance equals original value
+	balance1, err := chainA.SimpleToken.BalanceOf(chainA.CallOpts(ctx, relayer), chainA.CallOpts(ctx, deployer).From)
+	suite.Require().NoError(err)
+	suite.Require().Equal(balance0.Int64(), balance1.Int64())
+
+	// ensure that the returned token is released from the escrow for the channel
+	supply2 := ibctesting.RequireSupplyInvariant(suite.T(), ctx, chainA, chainB, chanA, baseDenom)
+	suite.Require().Zero(supply0.Escrowed.Cmp(supply2.Escrowed), supply2.String())
+}
+
+func (suite *ContractTestSuite) TestResumeHandshake() {
+	ctx := context.Background()
+
+	chainA := suite.chainA
+	chainB := suite.chainB
+
+	clientA, clientB := suite.coordinator.SetupClients(ctx, chainA, chainB, clienttypes.MockClient)
+
+	// stop the connection handshake after ConnOpenTry
+	connA, connB, err := suite.coordinator.ConnOpenInit(ctx, chainA, chainB, clientA, clientB)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.coordinator.ConnOpenTry(ctx, chainB, chainA, connB, connA))
+	connA, connB, err = suite.coordinator.ResumeConnection(ctx, chainA, chainB, connA.ID)
+	suite.Require().NoError(err)
+	connData, ok, err := chainB.IBCHost.GetConnection(chainB.CallOpts(ctx, ibctesting.RelayerKeyIndex), connB.ID)
+	suite.Require().NoError(err)
+	suite.Require().True(ok)
+	suite.Require().Equal(connectiontypes.ConnectionEnd_STATE_OPEN, connectiontypes.ConnectionEnd_State(connData.State))
+
+	// stop the channel handshake after ChanOpenInit
+	chanA, _, err := suite.coordinator.ChanOpenInit(ctx, chainA, chainB, connA, connB, ibctesting.TransferPort, ibctesting.TransferPort, channeltypes.UNORDERED)
+	suite.Require().NoError(err)
+	chanA, chanB, err := suite.coordinator.ResumeChannel(ctx, chainA, chainB, chanA.PortID, chanA.ID)
+	suite.Require().NoError(err)
+	chanData, ok, err := chainB.IBCHost.GetChannel(chainB.CallOpts(ctx, ibctesting.RelayerKeyIndex), chanB.PortID, chanB.ID)
+	suite.Require().NoError(err)
+	suite.Require().True(ok)
+	suite.Require().Equal(channeltypes.OPEN, channeltypes.Channel_State(chanData.State))
+
+	// resuming the completed handshake is a no-op
+	numConnections, numChannels := len(chainB.Connections), len(connB.Channels)
+	heightA, heightB := chainA.GetMockClientState(clientA).LatestHeight, chainB.GetMockClientState(clientB).LatestHeight
+	_, _, err = suite.coordinator.ResumeChannel(ctx, chainB, chainA, chanB.PortID, chanB.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(chainB.Connections, numConnections)
+	suite.Require().Len(connB.Channels, numChannels)
+	suite.Require().Equal(heightA, chainA.GetMockClientState(clientA).LatestHeight)
+	suite.Require().Equal(heightB, chainB.GetMockClientState(clientB).LatestHeight)
+}
+
 func TestContractTestSuite(t *testing.T) {
 	suite.Run(t, new(ContractTestSuite))
 }
@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/chains"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/contract/ibchandler"
+	clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	ibft2clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client/ibft2"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/monitor"
+	ibctesting "github.com/hyperledger-labs/yui-ibc-solidity/pkg/testing"
+)
+
+// IBFT2SimulatedTestSuite tests the IBFT2 client against a chain simulated in process, whose headers are sealed
+// by synthetic validators, through the scenarios which are hard to reproduce on a Besu network.
+// The client on chainA verifies chainB, which starts with the first 4 validators.
+type IBFT2SimulatedTestSuite struct {
+	suite.Suite
+
+	coordinator ibctesting.Coordinator
+	chainA      *ibctesting.Chain
+	chainB      *ibctesting.Chain
+	clientA     string
+
+	sealer     *chains.IBFT2Sealer
+	validators []*ecdsa.PrivateKey
+}
+
+func (suite *IBFT2SimulatedTestSuite) SetupTest() {
+	validators, err := chains.GenerateValidatorKeys(7)
+	suite.Require().NoError(err)
+	suite.validators = validators
+	suite.sealer = chains.NewIBFT2Sealer(chains.IBFT2Consensus{Validators: validators[:4]})
+
+	simulated := ibctesting.NewSimulatedIBFT2Chains(suite.T(), suite.sealer, artifactsDir(suite.T()), mnemonicPhrase,
+		uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano()))
+	suite.chainA, suite.chainB = simulated[0], simulated[1]
+	suite.coordinator = ibctesting.NewCoordinator(suite.T(), suite.chainA, suite.chainB)
+	suite.clientA, err = suite.coordinator.CreateClient(context.Background(), suite.chainA, suite.chainB, clienttypes.BesuIBFT2Client)
+	suite.Require().NoError(err)
+}
+
+func (suite *IBFT2SimulatedTestSuite) TestMissingSeals() {
+	ctx := context.Background()
+	vals := suite.validators[:4]
+
+	// a validator of 4 misses its seal
+	suite.schedule(chains.IBFT2Consensus{Validators: vals, Signers: vals[:3]})
+	suite.Require().NoError(suite.updateClient(ctx))
+
+	// 2 validators of 4 miss their seals
+	header := suite.sealHeader(chains.IBFT2Consensus{Validators: vals, Signers: vals[:2]})
+	suite.Require().Error(suite.submitHeader(ctx, header, suite.latestHeight()))
+	suite.Require().Less(suite.latestHeight(), header.Number.Uint64())
+
+	// the validators seal the headers again
+	suite.schedule(chains.IBFT2Consensus{Validators: vals})
+	suite.Require().NoError(suite.updateClient(ctx))
+}
+
+func (suite *IBFT2SimulatedTestSuite) TestValidatorRotation() {
+	ctx := context.Background()
+	vals := suite.validators
+
+	// the validators are replaced with the ones none of which is trusted
+	suite.schedule(chains.IBFT2Consensus{Validators: vals[4:7]})
+	suite.Require().Error(suite.updateClient(ctx))
+
+	// a half of the validators are rotated, voting to add another validator
+	suite.schedule(chains.IBFT2Consensus{
+		Validators: vals[2:6],
+		Vote:       &chains.Vote{Recipient: crypto.PubkeyToAddress(vals[6].PublicKey), Type: chains.VoteAdd},
+	})
+	suite.Require().NoError(suite.updateClient(ctx))
+	bz, found, err := suite.chainA.IBCHost.GetConsensusState(suite.chainA.CallOpts(ctx, ibctesting.RelayerKeyIndex), suite.clientA, suite.latestHeight())
+	suite.Require().NoError(err)
+	suite.Require().True(found)
+	var consensusState ibft2clienttypes.ConsensusState
+	suite.Require().NoError(ibctesting.UnmarshalWithAny(bz, &consensusState))
+	var trusted [][]byte
+	for _, val := range (chains.IBFT2Consensus{Validators: vals[2:6]}).ValidatorAddresses() {
+		trusted = append(trusted, val.Bytes())
+	}
+	suite.Require().Equal(trusted, consensusState.Validators)
+
+	// the validators trusted by the last update sign the next rotation
+	suite.schedule(chains.IBFT2Consensus{Validators: vals[4:7]})
+	suite.Require().NoError(suite.updateClient(ctx))
+}
+
+func (suite *IBFT2SimulatedTestSuite) TestEquivocation() {
+	ctx := context.Background()
+	m := monitor.NewMisbehaviourMonitor(suite.chainA.Host(), suite.chainB.Host(), suite.clientA)
+	head, err := suite.chainA.Client().BlockByNumber(ctx, nil)
+	suite.Require().NoError(err)
+	fromBlock := head.NumberU64() + 1
+
+	trustedHeight := suite.latestHeight()
+	suite.Require().NoError(suite.updateClient(ctx))
+	header := suite.chainB.LastHeader()
+
+	// the validators commit another block at the same height, which the client accepts as well
+	conflicting, err := suite.sealer.SealConflicting(header, func(header *gethtypes.Header) { header.Time++ })
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.submitHeader(ctx, conflicting, trustedHeight))
+
+	// the monitor finds the conflicting header against the one submitted before and the canonical one
+	head, err = suite.chainA.Client().BlockByNumber(ctx, nil)
+	suite.Require().NoError(err)
+	evidences, err := m.Check(ctx, fromBlock, head.NumberU64())
+	suite.Require().NoError(err)
+	suite.Require().Len(evidences, 2)
+	for _, evidence := range evidences {
+		suite.Require().Equal(monitor.MisbehaviourConflictingHeader, evidence.Type)
+		suite.Require().Equal(header.Number.Uint64(), evidence.Height)
+		suite.Require().Equal(conflicting.Time, evidence.Submitted.Base.Time)
+		suite.Require().Equal(header.Time, evidence.Conflicting.Base.Time)
+	}
+}
+
+// schedule makes the consensus seal the blocks of chainB from the next one.
+func (suite *IBFT2SimulatedTestSuite) schedule(consensus chains.IBFT2Consensus) {
+	// the latest block is mined by the query
+	block, err := suite.chainB.Client().BlockByNumber(context.Background(), nil)
+	suite.Require().NoError(err)
+	suite.sealer.Schedule(block.NumberU64()+1, consensus)
+}
+
+// sealHeader returns the header of the next block of chainB sealed by the consensus.
+func (suite *IBFT2SimulatedTestSuite) sealHeader(consensus chains.IBFT2Consensus) *gethtypes.Header {
+	suite.schedule(consensus)
+	block, err := suite.chainB.Client().BlockByNumber(context.Background(), nil)
+	suite.Require().NoError(err)
+	return block.Header()
+}
+
+// updateClient updates the client on chainA with the latest header of chainB.
+func (suite *IBFT2SimulatedTestSuite) updateClient(ctx context.Context) error {
+	suite.chainB.UpdateHeader()
+	if err := suite.chainA.UpdateIBFT2Client(ctx, suite.chainB, suite.clientA); err != nil {
+		return err
+	}
+	suite.Require().Equal(suite.chainB.LastHeader().Number.Uint64(), suite.latestHeight())
+	return nil
+}
+
+// submitHeader updates the client on chainA with the header verified against the trusted height, even if
+// its seals are insufficient or it is not the canonical header, which UpdateHeader of chainB rejects.
+func (suite *IBFT2SimulatedTestSuite) submitHeader(ctx context.Context, header *gethtypes.Header, trustedHeight uint64) error {
+	parsed, err := chains.ParseHeader(header)
+	suite.Require().NoError(err)
+	sealingHeader, err := parsed.GetSealingHeaderBytes()
+	suite.Require().NoError(err)
+	signers, err := chains.RecoverCommitterAddressesVals(crypto.Keccak256(sealingHeader), parsed.Seals)
+	suite.Require().NoError(err)
+	// the seals are aligned with the validators
+	var seals [][]byte
+	for _, val := range parsed.Validators {
+		seals = append(seals, signers[val])
+	}
+	proof, err := suite.chainB.Client().GetETHProof(suite.chainB.IBCHostAddress(), nil, header.Number)
+	suite.Require().NoError(err)
+
+	bz, err := ibctesting.MarshalWithAny(&ibft2clienttypes.Header{
+		BesuHeaderRlp:     sealingHeader,
+		Seals:             seals,
+		TrustedHeight:     trustedHeight,
+		AccountStateProof: proof.AccountProofRLP,
+	})
+	suite.Require().NoError(err)
+	return suite.chainA.WaitIfNoError(ctx)(suite.chainA.IBCHandler.UpdateClient(
+		suite.chainA.TxOpts(ctx, ibctesting.RelayerKeyIndex),
+		ibchandler.IBCMsgsMsgUpdateClient{ClientId: suite.clientA, Header: bz},
+	))
+}
+
+func (suite *IBFT2SimulatedTestSuite) latestHeight() uint64 {
+	return suite.chainA.GetIBFT2ClientState(suite.clientA).LatestHeight
+}
+
+func TestIBFT2SimulatedTestSuite(t *testing.T) {
+	suite.Run(t, new(IBFT2SimulatedTestSuite))
+}
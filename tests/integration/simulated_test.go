@@ -0,0 +1,259 @@
+package tests
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/audit"
+	"github.com/hyperledger-labs/yui-ibc-solidity/pkg/host"
+	channeltypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/channel"
+	clienttypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/client"
+	connectiontypes "github.com/hyperledger-labs/yui-ibc-solidity/pkg/ibc/connection"
+	ibctesting "github.com/hyperledger-labs/yui-ibc-solidity/pkg/testing"
+)
+
+// artifactsDir returns the directory of the artifacts of the contracts built by `truffle compile`.
+// The test fails if they have not been built, rather than being skipped unnoticed.
+func artifactsDir(t *testing.T) string {
+	dir := filepath.Join("..", "..", ibctesting.DefaultArtifactsDir)
+	_, err := os.Stat(dir)
+	require.NoError(t, err, "the artifacts of the contracts are not found; build them by `npx truffle compile`")
+	return dir
+}
+
+// SimulatedContractTestSuite runs the tests of ContractTestSuite on a chain simulated in process, which needs
+// no node running but the artifacts of the contracts built by `truffle compile`.
+type SimulatedContractTestSuite struct {
+	ContractTestSuite
+}
+
+func (suite *SimulatedContractTestSuite) SetupTest() {
+	chains := ibctesting.NewSimulatedChains(suite.T(), clienttypes.MockClient, artifactsDir(suite.T()), mnemonicPhrase,
+		uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano()))
+	suite.chainA, suite.chainB = chains[0], chains[1]
+	suite.coordinator = ibctesting.NewCoordinator(suite.T(), suite.chainA, suite.chainB)
+}
+
+// TestTimedOutPacket checks that a packet timed out on the counterparty chain is returned with the proof of
+// the absence of its receipt, and is skipped when the packets are cleared. The proofs are verified against the
+// storage root of IBCHost, which the simulated chain serves by eth_getProof.
+func (suite *SimulatedContractTestSuite) TestTimedOutPacket() {
+	ctx := context.Background()
+
+	const (
+		deployer        = ibctesting.RelayerKeyIndex // the key-index of contract deployer on chain
+		alice    uint32 = 1                          // the key-index of alice on chain
+		bob      uint32 = 2                          // the key-index of bob on chain
+	)
+
+	chainA := suite.chainA
+	chainB := suite.chainB
+
+	clientA, clientB := suite.coordinator.SetupClients(ctx, chainA, chainB, clienttypes.MockClient)
+	connA, connB := suite.coordinator.CreateConnection(ctx, chainA, chainB, clientA, clientB)
+	chanA, chanB := suite.coordinator.CreateChannel(ctx, chainA, chainB, connA, connB, ibctesting.TransferPort, ibctesting.TransferPort, channeltypes.UNORDERED)
+
+	token := chainA.ContractConfig.GetSimpleTokenAddress()
+	baseDenom := strings.ToLower(token.String())
+	suite.Require().NoError(chainA.WaitIfNoError(ctx)(
+		chainA.SimpleToken.Approve(chainA.TxOpts(ctx, deployer), chainA.ContractConfig.GetICS20BankAddress(), big.NewInt(200)),
+	))
+	suite.Require().NoError(chainA.WaitIfNoError(ctx)(
+		chainA.ICS20Bank.Deposit(chainA.TxOpts(ctx, deployer), token, big.NewInt(200), chainA.CallOpts(ctx, alice).From),
+	))
+	sendTransfer := func(timeoutHeight uint64) channeltypes.Packet {
+		suite.Require().NoError(chainA.WaitIfNoError(ctx)(
+			chainA.ICS20Transfer.SendTransfer(
+				chainA.TxOpts(ctx, alice),
+				baseDenom,
+				100,
+				chainB.CallOpts(ctx, bob).From,
+				chanA.PortID, chanA.ID,
+				timeoutHeight,
+			),
+		))
+		packet, err := chainA.GetLastSentPacket(ctx, chanA.PortID, chanA.ID)
+		suite.Require().NoError(err)
+		return *packet
+	}
+
+	// send a packet timing out two blocks after the latest block of chainB known to chainA
+	chainB.UpdateHeader()
+	suite.Require().NoError(suite.coordinator.UpdateClient(ctx, chainA, chainB, clientA))
+	timeoutHeight := chainB.LastHeader().Number.Uint64() + 2
+	timedOut := sendTransfer(timeoutHeight)
+
+	// advance chainB to the timeout height
+	for chainB.LastHeader().Number.Uint64() < timeoutHeight {
+		suite.Require().NoError(suite.coordinator.UpdateClient(ctx, chainB, chainA, clientB))
+		chainB.UpdateHeader()
+	}
+
+	packets, err := suite.coordinator.QueryTimedOutPackets(ctx, chainA, chainB, chanA, chanB)
+	suite.Require().NoError(err)
+	suite.Require().Len(packets, 1)
+	suite.Require().Equal(timedOut, packets[0].Packet, ibctesting.FormatPacket(packets[0].Packet))
+	proofHeight := new(big.Int).SetUint64(packets[0].Proof.Height.RevisionHeight)
+	suite.Require().GreaterOrEqual(proofHeight.Uint64(), timeoutHeight)
+	receiptSlot := chainB.PacketReceiptCommitmentSlot(chanB.PortID, chanB.ID, timedOut.Sequence)
+	suite.Require().Nil(suite.proveSlot(ctx, chainB, receiptSlot, proofHeight))
+
+	// the timed-out packet is skipped, and the packet sent after it is relayed
+	received := sendTransfer(chainB.LastHeader().Number.Uint64() + 1000)
+	suite.Require().NoError(suite.coordinator.ClearPackets(ctx, chainA, chainB, chanA, chanB))
+	seqs, err := chainA.QueryUnrelayedSequences(ctx, chainB, chanA, chanB)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]uint64{timedOut.Sequence}, seqs.Packets)
+	suite.Require().Empty(seqs.Acknowledgements)
+
+	// the receipt of the received packet and the next receive sequence are stored in the slots derived by IBCIdentifier
+	chainB.UpdateHeader()
+	height := chainB.LastHeader().Number
+	suite.Require().Nil(suite.proveSlot(ctx, chainB, receiptSlot, height))
+	suite.Require().Equal([]byte{1}, suite.proveSlot(ctx, chainB, chainB.PacketReceiptCommitmentSlot(chanB.PortID, chanB.ID, received.Sequence), height))
+	suite.Require().Equal([]byte{1}, suite.proveSlot(ctx, chainB, chainB.NextSequenceRecvCommitmentSlot(chanB.PortID, chanB.ID), height))
+}
+
+// TestCommitmentPrefix checks that a connection is opened between the chains whose IBC hosts have different
+// commitment prefixes, and that the prefix can no longer be changed once a connection is created.
+func (suite *SimulatedContractTestSuite) TestCommitmentPrefix() {
+	ctx := context.Background()
+
+	chainA := suite.chainA
+	chainB := suite.chainB
+
+	prefixB := []byte("ibc-b")
+	suite.Require().Equal([]byte(host.DefaultPrefix), chainA.GetCommitmentPrefix())
+	suite.Require().NoError(chainB.SetCommitmentPrefix(ctx, prefixB))
+	suite.Require().Equal(prefixB, chainB.GetCommitmentPrefix())
+
+	clientA, clientB := suite.coordinator.SetupClients(ctx, chainA, chainB, clienttypes.MockClient)
+	connA, connB := suite.coordinator.CreateConnection(ctx, chainA, chainB, clientA, clientB)
+	for _, c := range []struct {
+		chain          *ibctesting.Chain
+		connectionID   string
+		expectedPrefix []byte
+	}{{chainA, connA.ID, prefixB}, {chainB, connB.ID, []byte(host.DefaultPrefix)}} {
+		conn, found, err := c.chain.IBCHost.GetConnection(c.chain.CallOpts(ctx, ibctesting.RelayerKeyIndex), c.connectionID)
+		suite.Require().NoError(err)
+		suite.Require().True(found)
+		suite.Require().Equal(connectiontypes.ConnectionEnd_STATE_OPEN, connectiontypes.ConnectionEnd_State(conn.State))
+		suite.Require().Equal(c.expectedPrefix, conn.Counterparty.Prefix.KeyPrefix)
+	}
+	report, err := audit.CheckConnection(ctx, chainA.Host(), chainB.Host(), connA.ID)
+	suite.Require().NoError(err)
+	suite.Require().True(report.OK(), report.String())
+
+	suite.Require().Error(chainB.SetCommitmentPrefix(ctx, []byte(host.DefaultPrefix)))
+	suite.Require().Equal(prefixB, chainB.GetCommitmentPrefix())
+}
+
+// TestCheckAll checks that all the connections and channels between the chains are checked, and that the ones
+// whose counterparty has not been created yet are reported as unpaired.
+func (suite *SimulatedContractTestSuite) TestCheckAll() {
+	ctx := context.Background()
+
+	chainA := suite.chainA
+	chainB := suite.chainB
+
+	clientA, clientB := suite.coordinator.SetupClients(ctx, chainA, chainB, clienttypes.MockClient)
+	connA, connB := suite.coordinator.CreateConnection(ctx, chainA, chainB, clientA, clientB)
+	chanA, chanB := suite.coordinator.CreateChannel(ctx, chainA, chainB, connA, connB, ibctesting.TransferPort, ibctesting.TransferPort, channeltypes.UNORDERED)
+	initChanA, _, err := suite.coordinator.ChanOpenInit(ctx, chainA, chainB, connA, connB, ibctesting.TransferPort, ibctesting.TransferPort, channeltypes.UNORDERED)
+	suite.Require().NoError(err)
+	initConnB, _, err := suite.coordinator.ConnOpenInit(ctx, chainB, chainA, clientB, clientA)
+	suite.Require().NoError(err)
+
+	report, err := audit.CheckAll(ctx, chainA.Host(), chainB.Host(), ibctesting.TransferPort)
+	suite.Require().NoError(err)
+	suite.Require().True(report.OK(), report.String())
+	for _, path := range []string{
+		"connections/" + connA.ID,
+		"channelEnds/ports/transfer/channels/" + chanA.ID,
+		"channelEnds/ports/transfer/channels/" + chanB.ID,
+	} {
+		suite.Require().Contains(report.Checked, path)
+	}
+	suite.Require().Equal([]audit.Inconsistency{
+		{ChainID: chainB.ChainIDString(), Path: "connections/" + initConnB.ID, Message: "no counterparty connection has been created in STATE_INIT"},
+		{ChainID: chainA.ChainIDString(), Path: "channelEnds/ports/transfer/channels/" + initChanA.ID, Message: "no counterparty channel has been created in STATE_INIT"},
+	}, report.Unpaired)
+	// the mock clients are noted as not checked against the chains they track
+	suite.Require().NotEmpty(report.Notes)
+	suite.Require().Contains(report.String(), "mock client")
+}
+
+// proveSlot returns the value of the storage slot of IBCHost on the chain at the height, which is verified by its
+// proof against the storage root of IBCHost. nil is returned if the slot is empty.
+func (suite *SimulatedContractTestSuite) proveSlot(ctx context.Context, chain *ibctesting.Chain, slot string, height *big.Int) []byte {
+	proof, err := chain.Client().GetETHProof(chain.IBCHostAddress(), [][]byte{[]byte(slot)}, height)
+	suite.Require().NoError(err)
+	root, err := chain.Client().GetStorageRoot(ctx, chain.IBCHostAddress(), height)
+	suite.Require().NoError(err)
+
+	var nodes []rlp.RawValue
+	suite.Require().NoError(rlp.DecodeBytes(proof.StorageProofRLP[0], &nodes))
+	db := memorydb.New()
+	for _, node := range nodes {
+		suite.Require().NoError(db.Put(crypto.Keccak256(node), node))
+	}
+	value, err := trie.VerifyProof(root, crypto.Keccak256(common.HexToHash(slot).Bytes()), db)
+	suite.Require().NoError(err)
+	if value == nil {
+		return nil
+	}
+	var content []byte
+	suite.Require().NoError(rlp.DecodeBytes(value, &content))
+	return content
+}
+
+func TestSimulatedContractTestSuite(t *testing.T) {
+	suite.Run(t, new(SimulatedContractTestSuite))
+}
+
+// TestSimulatedDeployContracts checks that the contracts built by truffle are deployed and wired together
+// as the truffle migrations do.
+func TestSimulatedDeployContracts(t *testing.T) {
+	ctx := context.Background()
+	chain := ibctesting.NewSimulatedChains(t, clienttypes.MockClient, artifactsDir(t), mnemonicPhrase, 0)[0]
+	contracts, ok := chain.ContractConfig.(*ibctesting.DeployedContracts)
+	require.True(t, ok)
+	opts := chain.CallOpts(ctx, ibctesting.RelayerKeyIndex)
+
+	hostAddress, err := chain.IBCHandler.GetHostAddress(opts)
+	require.NoError(t, err)
+	require.Equal(t, contracts.IBCHost, hostAddress)
+	for clientType, impl := range map[string]common.Address{
+		clienttypes.BesuIBFT2Client: contracts.IBFT2Client,
+		clienttypes.MockClient:      contracts.MockClient,
+	} {
+		address, found, err := chain.IBCHost.GetClientImpl(opts, clientType)
+		require.NoError(t, err)
+		require.True(t, found, clientType)
+		require.Equal(t, impl, address, clientType)
+	}
+	// the capability path of a port is the port ID
+	owner, found, err := chain.IBCHost.GetModuleOwner(opts, []byte(ibctesting.TransferPort))
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, contracts.ICS20TransferBank, owner)
+
+	role, err := chain.ICS20Bank.OPERATORROLE(opts)
+	require.NoError(t, err)
+	isOperator, err := chain.ICS20Bank.HasRole(opts, role, contracts.ICS20TransferBank)
+	require.NoError(t, err)
+	require.True(t, isOperator)
+}